@@ -0,0 +1,124 @@
+package bloomsdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/zilionixx/zilion-base/common/bigendian"
+	"github.com/zilionixx/zilion-base/kvdb"
+	"github.com/zilionixx/zilion-base/kvdb/table"
+)
+
+// DefaultSectionSize is a number of blocks in a section of the index.
+const DefaultSectionSize = 4096
+
+var (
+	ErrUnexpectedSection = errors.New("unexpected section")
+	ErrSectionSize       = errors.New("wrong number of blooms in a section")
+
+	sectionsKey = []byte("s")
+)
+
+// Index is a bloom-bits index of blocks. Blocks are grouped into sections of the fixed size,
+// and the bloom filters of a section are rotated, so that each of the bloom bits
+// is stored as a bitset of the section blocks.
+type Index struct {
+	db          kvdb.Store
+	sectionSize uint64
+	table       struct {
+		// section+bit -> compressed bitset of the section blocks
+		Bits kvdb.Store `table:"b"`
+		// "s" -> number of the indexed sections
+		Sections kvdb.Store `table:"s"`
+	}
+}
+
+// New Index instance. Section size has to be a multiple of 8.
+func New(db kvdb.Store, sectionSize uint64) *Index {
+	if sectionSize == 0 || sectionSize%8 != 0 {
+		panic(fmt.Sprintf("bloom-bits section size %d isn't a multiple of 8", sectionSize))
+	}
+	tt := &Index{
+		db:          db,
+		sectionSize: sectionSize,
+	}
+
+	table.MigrateTables(&tt.table, tt.db)
+
+	return tt
+}
+
+// SectionSize returns the number of blocks in a section.
+func (tt *Index) SectionSize() uint64 {
+	return tt.sectionSize
+}
+
+// Sections returns the number of the indexed sections.
+// Sections are indexed in order, so all the blocks before Sections()*SectionSize() are covered by the index.
+func (tt *Index) Sections() (uint64, error) {
+	b, err := tt.table.Sections.Get(sectionsKey)
+	if err != nil || b == nil {
+		return 0, err
+	}
+	return bigendian.BytesToUint64(b), nil
+}
+
+// AddSection indexes the blooms of the section blocks. Sections have to be added in order.
+func (tt *Index) AddSection(section uint64, blooms []types.Bloom) error {
+	sections, err := tt.Sections()
+	if err != nil {
+		return err
+	}
+	if section != sections {
+		return ErrUnexpectedSection
+	}
+	if uint64(len(blooms)) != tt.sectionSize {
+		return ErrSectionSize
+	}
+
+	gen, err := bloombits.NewGenerator(uint(tt.sectionSize))
+	if err != nil {
+		return err
+	}
+	for i, bloom := range blooms {
+		if err := gen.AddBloom(uint(i), bloom); err != nil {
+			return err
+		}
+	}
+
+	batch := tt.table.Bits.NewBatch()
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		bits, err := gen.Bitset(bit)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(bitsKey(section, bit), bitutil.CompressBytes(bits)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	return tt.table.Sections.Put(sectionsKey, bigendian.Uint64ToBytes(section+1))
+}
+
+// getBits returns the bitset of the bloom bit within the indexed section.
+func (tt *Index) getBits(section uint64, bit uint) ([]byte, error) {
+	b, err := tt.table.Bits.Get(bitsKey(section, bit))
+	if err != nil {
+		return nil, err
+	}
+	// a zero bitset is compressed into an empty value
+	return bitutil.DecompressBytes(b, int(tt.sectionSize/8))
+}
+
+func bitsKey(section uint64, bit uint) []byte {
+	key := make([]byte, 0, 8+2)
+	key = append(key, bigendian.Uint64ToBytes(section)...)
+	key = append(key, byte(bit>>8), byte(bit))
+	return key
+}
@@ -0,0 +1,96 @@
+package bloomsdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/kvdb/memorydb"
+)
+
+type blockRange struct {
+	from, to idx.Block
+}
+
+func TestIndexMatch(t *testing.T) {
+	require := require.New(t)
+
+	const sectionSize = 16
+	var (
+		addr1  = common.BytesToAddress([]byte("addr1"))
+		addr2  = common.BytesToAddress([]byte("addr2"))
+		addr3  = common.BytesToAddress([]byte("addr3"))
+		topic1 = common.BytesToHash([]byte("topic1"))
+		topic2 = common.BytesToHash([]byte("topic2"))
+	)
+	logs := map[idx.Block][]*types.Log{
+		3:  {{Address: addr1, Topics: []common.Hash{topic1}}},
+		4:  {{Address: addr1, Topics: []common.Hash{topic2}}},
+		5:  {{Address: addr1}},
+		17: {{Address: addr2, Topics: []common.Hash{topic1}}},
+		31: {{Address: addr1, Topics: []common.Hash{topic1, topic2}}},
+		32: {{Address: addr1, Topics: []common.Hash{topic1}}},
+		40: {{Address: addr3}},
+	}
+	bloomOf := func(n idx.Block) types.Bloom {
+		return types.BytesToBloom(types.LogsBloom(logs[n]))
+	}
+
+	index := New(memorydb.New(), sectionSize)
+	sections, err := index.Sections()
+	require.NoError(err)
+	require.Equal(uint64(0), sections)
+
+	for section := uint64(0); section < 2; section++ {
+		blooms := make([]types.Bloom, sectionSize)
+		for i := range blooms {
+			blooms[i] = bloomOf(idx.Block(section*sectionSize + uint64(i)))
+		}
+		require.Equal(ErrSectionSize, index.AddSection(section, blooms[1:]))
+		require.Equal(ErrUnexpectedSection, index.AddSection(section+1, blooms))
+		require.NoError(index.AddSection(section, blooms))
+	}
+	sections, err = index.Sections()
+	require.NoError(err)
+	require.Equal(uint64(2), sections)
+
+	match := func(from, to idx.Block, addresses []common.Address, topics [][]common.Hash) ([]blockRange, idx.Block) {
+		var got []blockRange
+		next, err := index.Match(context.Background(), from, to, addresses, topics, func(from, to idx.Block) error {
+			got = append(got, blockRange{from, to})
+			return nil
+		})
+		require.NoError(err)
+		return got, next
+	}
+
+	got, next := match(0, 100, []common.Address{addr1}, nil)
+	require.Equal([]blockRange{{3, 5}, {31, 31}}, got)
+	require.Equal(idx.Block(32), next)
+
+	got, next = match(4, 20, []common.Address{addr1, addr2}, nil)
+	require.Equal([]blockRange{{4, 5}, {17, 17}}, got)
+	require.Equal(idx.Block(21), next)
+
+	got, _ = match(0, 31, []common.Address{addr1}, [][]common.Hash{{topic1}})
+	require.Equal([]blockRange{{3, 3}, {31, 31}}, got)
+
+	// blooms don't keep positions of topics
+	got, _ = match(0, 31, nil, [][]common.Hash{{}, {topic2}})
+	require.Equal([]blockRange{{4, 4}, {31, 31}}, got)
+
+	got, _ = match(0, 31, []common.Address{addr3}, nil)
+	require.Empty(got)
+
+	got, next = match(10, 20, nil, nil)
+	require.Equal([]blockRange{{10, 20}}, got)
+	require.Equal(idx.Block(21), next)
+
+	// blocks after the indexed sections aren't checked
+	got, next = match(40, 50, []common.Address{addr3}, nil)
+	require.Empty(got)
+	require.Equal(idx.Block(40), next)
+}
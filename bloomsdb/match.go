@@ -0,0 +1,152 @@
+package bloomsdb
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/zilionixx/zilion-base/inter/idx"
+)
+
+// bloomIndexes represents the bit indexes inside the bloom filter that belong
+// to some key.
+type bloomIndexes [3]uint
+
+// calcBloomIndexes returns the bloom filter bit indexes belonging to the given key.
+func calcBloomIndexes(b []byte) bloomIndexes {
+	b = crypto.Keccak256(b)
+
+	var idxs bloomIndexes
+	for i := 0; i < len(idxs); i++ {
+		idxs[i] = (uint(b[2*i])<<8)&2047 + uint(b[2*i+1])
+	}
+	return idxs
+}
+
+// Match calls onMatched for the ranges of blocks which may contain a log matching the criteria:
+// a log of any of the addresses, and any of the topics on each position (empty list is a wildcard).
+// Only blocks of the indexed sections are checked, the first block of the range
+// which isn't covered by the index is returned (it's greater than the range end if the whole range is covered).
+func (tt *Index) Match(ctx context.Context, from, to idx.Block, addresses []common.Address, topics [][]common.Hash, onMatched func(from, to idx.Block) error) (idx.Block, error) {
+	sections, err := tt.Sections()
+	if err != nil {
+		return from, err
+	}
+	indexed := idx.Block(sections * tt.sectionSize)
+	if from >= indexed || from > to {
+		return from, nil
+	}
+	last := to
+	if last >= indexed {
+		last = indexed - 1
+	}
+
+	var groups [][]bloomIndexes
+	if len(addresses) != 0 {
+		group := make([]bloomIndexes, len(addresses))
+		for i, addr := range addresses {
+			group[i] = calcBloomIndexes(addr.Bytes())
+		}
+		groups = append(groups, group)
+	}
+	for _, variants := range topics {
+		if len(variants) == 0 {
+			continue
+		}
+		group := make([]bloomIndexes, len(variants))
+		for i, topic := range variants {
+			group[i] = calcBloomIndexes(topic.Bytes())
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		return last + 1, onMatched(from, last)
+	}
+
+	var (
+		started    bool
+		start, end idx.Block
+	)
+	for section := uint64(from) / tt.sectionSize; section*tt.sectionSize <= uint64(last); section++ {
+		if err := ctx.Err(); err != nil {
+			return from, err
+		}
+		vector, err := tt.matchSection(section, groups)
+		if err != nil {
+			return from, err
+		}
+
+		first := idx.Block(section * tt.sectionSize)
+		for i, b := range vector {
+			if b == 0 {
+				continue
+			}
+			for bit := uint(0); bit < 8; bit++ {
+				if b&(1<<(7-bit)) == 0 {
+					continue
+				}
+				n := first + idx.Block(uint(i)*8+bit)
+				if n < from || n > last {
+					continue
+				}
+				if started && n == end+1 {
+					end = n
+					continue
+				}
+				if started {
+					if err := onMatched(start, end); err != nil {
+						return from, err
+					}
+				}
+				started, start, end = true, n, n
+			}
+		}
+	}
+	if started {
+		if err := onMatched(start, end); err != nil {
+			return from, err
+		}
+	}
+
+	return last + 1, nil
+}
+
+// matchSection returns the bitset of the section blocks which may match all the groups of keys.
+func (tt *Index) matchSection(section uint64, groups [][]bloomIndexes) ([]byte, error) {
+	bits := make(map[uint][]byte)
+	getBits := func(bit uint) ([]byte, error) {
+		if b, ok := bits[bit]; ok {
+			return b, nil
+		}
+		b, err := tt.getBits(section, bit)
+		bits[bit] = b
+		return b, err
+	}
+
+	var result []byte
+	for _, group := range groups {
+		groupVector := make([]byte, tt.sectionSize/8)
+		for _, key := range group {
+			keyVector := make([]byte, len(groupVector))
+			for i, bit := range key {
+				b, err := getBits(bit)
+				if err != nil {
+					return nil, err
+				}
+				if i == 0 {
+					copy(keyVector, b)
+				} else {
+					bitutil.ANDBytes(keyVector, keyVector, b)
+				}
+			}
+			bitutil.ORBytes(groupVector, groupVector, keyVector)
+		}
+		if result == nil {
+			result = groupVector
+		} else {
+			bitutil.ANDBytes(result, result, groupVector)
+		}
+	}
+	return result, nil
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/zilionixx/zilion-base/utils/simplewlru"
 	"gopkg.in/urfave/cli.v1"
 
-	"github.com/zilionixx/go-zilionixx/integration"
 	"github.com/zilionixx/go-zilionixx/inter"
 )
 
@@ -32,7 +31,7 @@ func checkEvm(ctx *cli.Context) error {
 
 	cfg := makeAllConfigs(ctx)
 
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
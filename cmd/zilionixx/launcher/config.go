@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"math/big"
 	"os"
-	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -22,11 +21,14 @@ import (
 	"github.com/zilionixx/go-zilionixx/zilionixx"
 	"github.com/zilionixx/zilion-base/abft"
 	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/kvdb"
 	"github.com/zilionixx/zilion-base/utils/cachescale"
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/gossip"
+	"github.com/zilionixx/go-zilionixx/gossip/emitter"
 	"github.com/zilionixx/go-zilionixx/gossip/gasprice"
 	"github.com/zilionixx/go-zilionixx/integration"
 	"github.com/zilionixx/go-zilionixx/integration/makegenesis"
@@ -73,12 +75,52 @@ var (
 		Value: DefaultCacheSize,
 	}
 
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Database engine of a new datadir (" + integration.LevelDBEngine + " or " + integration.PebbleEngine + "), an existing datadir is opened only with its recorded engine",
+	}
+
+	DBFreezerEpochsFlag = cli.Uint64Flag{
+		Name:  "db.freezer.epochs",
+		Usage: "Number of the last sealed epochs whose blocks, events and receipts are kept in the database, older ones are moved into the flat-file freezer (0 = disabled)",
+	}
+
+	DBEventsEpochsFlag = cli.Uint64Flag{
+		Name:  "db.events.epochs",
+		Usage: "Number of the last sealed epochs whose events are kept in the database, older events are pruned while blocks and receipts are kept (0 = keep all)",
+	}
+
+	TxLookupLimitFlag = cli.Uint64Flag{
+		Name:  "txlookuplimit",
+		Usage: "Number of recent blocks to maintain transactions index for (0 = entire chain)",
+	}
+
 	// GenesisFlag specifies network genesis configuration
 	GenesisFlag = cli.StringFlag{
 		Name:  "genesis",
 		Usage: "'path to genesis file' - sets the network genesis configuration.",
 	}
 
+	TxPoolSnapshotFlag = cli.StringFlag{
+		Name:  "txpool.snapshot",
+		Usage: "Disk snapshot of all transactions with arrival times to survive node restarts (disabled if empty)",
+	}
+	TxPoolResnapshotFlag = cli.DurationFlag{
+		Name:  "txpool.resnapshot",
+		Usage: "Time interval to regenerate the transactions snapshot",
+		Value: evmcore.DefaultTxPoolConfig.Resnapshot,
+	}
+	TxPoolPrioritySlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.priorityslots",
+		Usage: "Number of executable transaction slots reserved for the priority contract methods (SFC staking operations)",
+		Value: evmcore.DefaultTxPoolConfig.PrioritySlots,
+	}
+	TxPoolPrivateLifetimeFlag = cli.Uint64Flag{
+		Name:  "txpool.privatelifetime",
+		Usage: "Number of blocks after which not included private transactions are dropped",
+		Value: evmcore.DefaultTxPoolConfig.PrivateLifetime,
+	}
+
 	RPCGlobalGasCapFlag = cli.Uint64Flag{
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in ftm_call/estimateGas (0=infinite)",
@@ -89,6 +131,16 @@ var (
 		Usage: "Sets a cap on transaction fee (in ZNX) that can be sent via the RPC APIs (0 = no cap)",
 		Value: gossip.DefaultConfig(cachescale.Identity).RPCTxFeeCap,
 	}
+	RPCTxSenderRateFlag = cli.Float64Flag{
+		Name:  "rpc.txlimit.sender",
+		Usage: "Sets a limit on transactions per second per sender address that can be sent via the RPC APIs (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).TxSubmitLimits.SenderRate,
+	}
+	RPCTxOriginRateFlag = cli.Float64Flag{
+		Name:  "rpc.txlimit.origin",
+		Usage: "Sets a limit on transactions per second per client IP address that can be sent via the RPC APIs (0 = no limit). HTTP clients are limited per IP address, WebSocket and IPC clients per connection",
+		Value: gossip.DefaultConfig(cachescale.Identity).TxSubmitLimits.OriginRate,
+	}
 
 	AllowedzilionixxGenesisHashes = map[uint64]hash.Hash{
 		zilionixx.MainNetworkID: hash.HexToHash("0xe03d5d95a0fb5348e78bb1d055e552403bec5979673cd45a3181fba1e5fd9010"),
@@ -261,6 +313,12 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) {
 	if ctx.GlobalIsSet(utils.TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(utils.TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.GlobalString(TxPoolSnapshotFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolResnapshotFlag.Name) {
+		cfg.Resnapshot = ctx.GlobalDuration(TxPoolResnapshotFlag.Name)
+	}
 	if ctx.GlobalIsSet(utils.TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(utils.TxPoolPriceLimitFlag.Name)
 	}
@@ -282,6 +340,12 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) {
 	if ctx.GlobalIsSet(utils.TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(utils.TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPrioritySlotsFlag.Name) {
+		cfg.PrioritySlots = ctx.GlobalUint64(TxPoolPrioritySlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateLifetime = ctx.GlobalUint64(TxPoolPrivateLifetimeFlag.Name)
+	}
 }
 
 func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config, error) {
@@ -296,6 +360,15 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config,
 	if ctx.GlobalIsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCTxSenderRateFlag.Name) {
+		cfg.TxSubmitLimits.SenderRate = ctx.GlobalFloat64(RPCTxSenderRateFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTxOriginRateFlag.Name) {
+		cfg.TxSubmitLimits.OriginRate = ctx.GlobalFloat64(RPCTxOriginRateFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
+		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
+	}
 
 	err := setValidator(ctx, &cfg.Emitter)
 	if err != nil {
@@ -310,6 +383,12 @@ func gossipStoreConfigWithFlags(ctx *cli.Context, src gossip.StoreConfig) (gossi
 	if !ctx.GlobalBool(utils.SnapshotFlag.Name) {
 		cfg.EVM.EnableSnapshots = false
 	}
+	if ctx.GlobalIsSet(DBFreezerEpochsFlag.Name) {
+		cfg.Freezer.Epochs = idx.Epoch(ctx.GlobalUint64(DBFreezerEpochsFlag.Name))
+	}
+	if ctx.GlobalIsSet(DBEventsEpochsFlag.Name) {
+		cfg.EventsPruner.Epochs = idx.Epoch(ctx.GlobalUint64(DBEventsEpochsFlag.Name))
+	}
 	return cfg, nil
 }
 
@@ -337,6 +416,15 @@ func cacheScaler(ctx *cli.Context) cachescale.Func {
 	}
 }
 
+// makeDBProducer returns the producer of databases in the chaindata directory with the engine from the flag
+func makeDBProducer(ctx *cli.Context, chaindataDir string) kvdb.IterableDBProducer {
+	producer, err := integration.DBProducer(chaindataDir, ctx.GlobalString(DBEngineFlag.Name), cacheScaler(ctx))
+	if err != nil {
+		utils.Fatalf("Failed to open chaindata: %v", err)
+	}
+	return producer
+}
+
 func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 	// Defaults (low priority)
 	cacheRatio := cacheScaler(ctx)
@@ -372,8 +460,11 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		return nil, err
 	}
 	cfg.Node = nodeConfigWithFlags(ctx, cfg.Node)
+	if cfg.ZilionixxStore.Freezer.Dir == "" && cfg.Node.DataDir != "" {
+		cfg.ZilionixxStore.Freezer.Dir = filepath.Join(cfg.Node.DataDir, "ancient")
+	}
 	if cfg.Zilionixx.Emitter.Validator.ID != 0 && len(cfg.Zilionixx.Emitter.PrevEmittedEventFile.Path) == 0 {
-		cfg.Zilionixx.Emitter.PrevEmittedEventFile.Path = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("last-%d", cfg.Zilionixx.Emitter.Validator.ID)))
+		cfg.Zilionixx.Emitter.PrevEmittedEventFile.Path = emitter.PrevEmittedEventFilePath(cfg.Node.ResolvePath("emitter"), cfg.Zilionixx.Emitter.Validator.ID)
 	}
 
 	if err := cfg.Zilionixx.Validate(); err != nil {
@@ -395,8 +486,8 @@ func defaultNodeConfig() node.Config {
 	cfg := NodeDefaultConfig
 	cfg.Name = clientIdentifier
 	cfg.Version = params.VersionWithCommit(gitCommit, gitDate)
-	cfg.HTTPModules = append(cfg.HTTPModules, "eth", "znx", "dag", "sfc", "abft", "web3")
-	cfg.WSModules = append(cfg.WSModules, "eth", "znx", "dag", "sfc", "abft", "web3")
+	cfg.HTTPModules = append(cfg.HTTPModules, "eth", "znx", "dag", "sfc", "abft", "zilionixx", "web3")
+	cfg.WSModules = append(cfg.WSModules, "eth", "znx", "dag", "sfc", "abft", "zilionixx", "web3")
 	cfg.IPCPath = "zilionixx.ipc"
 	cfg.DataDir = DefaultDataDir()
 	return cfg
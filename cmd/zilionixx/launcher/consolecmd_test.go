@@ -17,8 +17,8 @@ import (
 )
 
 const (
-	ipcAPIs  = "abft:1.0 admin:1.0 dag:1.0 debug:1.0 znx:1.0 net:1.0 personal:1.0 rpc:1.0 sfc:1.0 txpool:1.0 web3:1.0"
-	httpAPIs = "abft:1.0 dag:1.0 znx:1.0 rpc:1.0 sfc:1.0 web3:1.0"
+	ipcAPIs  = "abft:1.0 admin:1.0 dag:1.0 debug:1.0 emitter:1.0 znx:1.0 net:1.0 personal:1.0 rpc:1.0 sfc:1.0 txpool:1.0 validator:1.0 web3:1.0 zilionixx:1.0"
+	httpAPIs = "abft:1.0 dag:1.0 znx:1.0 rpc:1.0 sfc:1.0 web3:1.0 zilionixx:1.0"
 )
 
 // Tests that a node embedded within a console can be started up properly and
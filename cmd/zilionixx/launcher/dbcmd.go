@@ -0,0 +1,187 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/zilionixx/go-zilionixx/integration"
+)
+
+var (
+	DBConvertToFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Database engine to convert to (" + integration.LevelDBEngine + " or " + integration.PebbleEngine + ")",
+	}
+	DBVerifyRepairFlag = cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Repair the found inconsistencies which can be rebuilt from the stored data",
+	}
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "A set of commands related to the databases",
+		Category: "MISCELLANEOUS COMMANDS",
+
+		Subcommands: []cli.Command{
+			{
+				Name:   "convert",
+				Usage:  "Convert the databases to another engine",
+				Action: utils.MigrateFlags(convertDBs),
+				Flags: []cli.Flag{
+					DataDirFlag,
+					DBConvertToFlag,
+				},
+				Description: `
+    zilionixx db convert --to <engine>
+
+Copies every database of the datadir into a new directory with the given engine
+and verifies key counts and checksums of every table. The node has to be stopped.
+An interrupted conversion is resumed by running the command again.
+After the conversion, the original databases are kept in chaindata-<old engine>.
+`,
+			},
+			{
+				Name:   "stats",
+				Usage:  "Print number of keys and their sizes per each table of the databases",
+				Action: utils.MigrateFlags(printDBStats),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    zilionixx db stats
+
+Iterates over every database of the datadir and prints the number of keys,
+the keys size and the values size per each logical table.
+Keys which don't belong to a known table are grouped by their first byte.
+The node has to be stopped, use debug_dbStats RPC for a running node.
+`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Check consistency of the gossip, consensus and EVM databases",
+				Action: utils.MigrateFlags(verifyDBs),
+				Flags: []cli.Flag{
+					DataDirFlag,
+					DBVerifyRepairFlag,
+				},
+				Description: `
+    zilionixx db verify [--repair]
+
+Cross-checks the latest block and epoch state with the latest block, the consensus
+epoch and validators, events and EVM states of every block, and heads and last events
+of the current epoch. Only EVM states of the blocks after the latest state pruning are required. With --repair, heads and last events are rebuilt from the epoch
+events, and blocks written after the latest block state are deleted.
+The node has to be stopped.
+`,
+			},
+		},
+	}
+)
+
+func convertDBs(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		utils.Fatalf("This command doesn't require an argument.")
+	}
+	engine := ctx.String(DBConvertToFlag.Name)
+	if err := integration.CheckDBEngine(engine); err != nil {
+		utils.Fatalf("Invalid flag --%s: %v", DBConvertToFlag.Name, err)
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	chaindataDir := path.Join(cfg.Node.DataDir, "chaindata")
+	if err := integration.FinishDBsSwap(chaindataDir); err != nil {
+		utils.Fatalf("Failed to finish the interrupted swap of databases: %v", err)
+	}
+	current, err := integration.ReadDBEngine(chaindataDir)
+	if err != nil {
+		utils.Fatalf("Failed to read the database engine: %v", err)
+	}
+	if current == "" {
+		utils.Fatalf("No databases found in %s", chaindataDir)
+	}
+	if current == engine {
+		utils.Fatalf("Databases are already stored with %s", engine)
+	}
+	convertedDir := chaindataDir + "-" + engine
+	backupDir := chaindataDir + "-" + current
+	if _, err := os.Stat(backupDir); err == nil {
+		utils.Fatalf("Backup directory %s already exists", backupDir)
+	}
+
+	log.Info("Converting databases", "from", current, "to", engine, "dir", convertedDir)
+	if err := integration.ConvertDBs(chaindataDir, convertedDir, engine, cacheScaler(ctx)); err != nil {
+		utils.Fatalf("Conversion failed, run the command again to resume it: %v", err)
+	}
+
+	if err := integration.SwapDBs(chaindataDir, convertedDir, backupDir); err != nil {
+		utils.Fatalf("Failed to move the converted databases: %v", err)
+	}
+	log.Info("Databases are converted", "engine", engine, "backup", backupDir)
+	return nil
+}
+
+func printDBStats(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		utils.Fatalf("This command doesn't require an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	chaindataDir := path.Join(cfg.Node.DataDir, "chaindata")
+	stats, err := integration.DBStats(context.Background(), chaindataDir, cacheScaler(ctx))
+	if err != nil {
+		utils.Fatalf("Failed to collect database stats: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "DB\tTABLE\tPREFIX\tKEYS\tKEYS SIZE\tVALUES SIZE")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", s.DB, s.Table, s.Prefix, s.Keys,
+			common.StorageSize(s.KeysSize), common.StorageSize(s.ValuesSize))
+	}
+	return w.Flush()
+}
+
+func verifyDBs(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		utils.Fatalf("This command doesn't require an argument.")
+	}
+	repair := ctx.Bool(DBVerifyRepairFlag.Name)
+
+	cfg := makeAllConfigs(ctx)
+
+	chaindataDir := path.Join(cfg.Node.DataDir, "chaindata")
+	report, err := integration.VerifyDBs(context.Background(), chaindataDir, cfg.AppConfigs(), cacheScaler(ctx), repair)
+	if err != nil {
+		utils.Fatalf("Failed to verify databases: %v", err)
+	}
+
+	unrepaired := 0
+	for _, issue := range report.Issues {
+		if issue.Repaired {
+			log.Warn("Repaired inconsistency", "check", issue.Check, "err", issue.Err)
+			continue
+		}
+		unrepaired++
+		log.Error("Found inconsistency", "check", issue.Check, "err", issue.Err)
+	}
+	if report.MissingStates != 0 {
+		log.Info("EVM states of blocks before the pruning horizon aren't stored", "blocks", report.MissingStates)
+	}
+	log.Info("Databases are verified", "blocks", report.Blocks, "issues", len(report.Issues), "unrepaired", unrepaired)
+	if unrepaired != 0 {
+		if !repair {
+			utils.Fatalf("Found %d inconsistencies, run with --%s to repair the fixable ones", unrepaired, DBVerifyRepairFlag.Name)
+		}
+		utils.Fatalf("Found %d inconsistencies which can't be repaired", unrepaired)
+	}
+	return nil
+}
@@ -40,7 +40,7 @@ func exportEvents(ctx *cli.Context) error {
 
 	cfg := makeAllConfigs(ctx)
 
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -79,6 +79,14 @@ func exportEvents(ctx *cli.Context) error {
 		to = idx.Epoch(n)
 	}
 
+	if pruned := gdb.GetPrunedEventsEpoch(); from < pruned {
+		if to != 0 && to < pruned {
+			utils.Fatalf("Events of epochs %d-%d are pruned, the first kept epoch is %d", from, to, pruned)
+		}
+		log.Warn("Events of the requested range are pruned, exporting from the first kept epoch", "from", from, "first kept", pruned)
+		from = pruned
+	}
+
 	log.Info("Exporting events to file", "file", fn)
 	// Write header and version
 	_, err = writer.Write(append(eventsFileHeader, eventsFileVersion...))
@@ -52,7 +52,7 @@ func importEvm(ctx *cli.Context) error {
 
 	cfg := makeAllConfigs(ctx)
 
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -102,6 +102,7 @@ func importEvents(ctx *cli.Context) error {
 	cfg.Zilionixx.Protocol.EventsSemaphoreLimit.Num = math.MaxUint32
 	cfg.Zilionixx.Emitter.Validator = emitter.ValidatorConfig{}
 	cfg.Zilionixx.TxPool.Journal = ""
+	cfg.Zilionixx.TxPool.Snapshot = ""
 	cfg.Node.IPCPath = ""
 	cfg.Node.HTTPHost = ""
 	cfg.Node.WSHost = ""
@@ -73,6 +73,10 @@ func initFlags() {
 	}
 	performanceFlags = []cli.Flag{
 		CacheFlag,
+		DBEngineFlag,
+		DBFreezerEpochsFlag,
+		DBEventsEpochsFlag,
+		TxLookupLimitFlag,
 		utils.SnapshotFlag,
 	}
 	networkingFlags = []cli.Flag{
@@ -92,6 +96,8 @@ func initFlags() {
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		TxPoolSnapshotFlag,
+		TxPoolResnapshotFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -99,6 +105,8 @@ func initFlags() {
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		TxPoolPrioritySlotsFlag,
+		TxPoolPrivateLifetimeFlag,
 	}
 	zilionixxFlags = []cli.Flag{
 		GenesisFlag,
@@ -146,6 +154,8 @@ func initFlags() {
 		utils.IPCPathFlag,
 		RPCGlobalGasCapFlag,
 		RPCGlobalTxFeeCapFlag,
+		RPCTxSenderRateFlag,
+		RPCTxOriginRateFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -205,6 +215,8 @@ func init() {
 		checkCommand,
 		// See snapshot.go
 		snapshotCommand,
+		// See dbcmd.go
+		dbCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -275,7 +287,7 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 	if err := os.MkdirAll(chaindataDir, 0700); err != nil {
 		utils.Fatalf("Failed to create chaindata directory: %v", err)
 	}
-	engine, dagIndex, gdb, cdb, genesisStore, blockProc := integration.MakeEngine(integration.DBProducer(chaindataDir, cacheScaler(ctx)), genesis, cfg.AppConfigs())
+	engine, dagIndex, gdb, cdb, genesisStore, blockProc := integration.MakeEngine(makeDBProducer(ctx, chaindataDir), genesis, cfg.AppConfigs())
 	_ = genesis.Close()
 	metrics.SetDataDir(cfg.Node.DataDir)
 
@@ -308,6 +320,7 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 	}
 
 	stack.RegisterAPIs(svc.APIs())
+	stack.RegisterAPIs(svc.ValidatorAPIs(valKeystore))
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
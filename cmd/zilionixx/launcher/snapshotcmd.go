@@ -35,7 +35,6 @@ import (
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/zilionixx/go-zilionixx/gossip/evmstore/evmpruner"
-	"github.com/zilionixx/go-zilionixx/integration"
 )
 
 var (
@@ -154,7 +153,7 @@ It's also usable without snapshot enabled.
 
 func pruneState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -192,12 +191,14 @@ func pruneState(ctx *cli.Context) error {
 		log.Error("Failed to prune state", "err", err)
 		return err
 	}
+	// states of the blocks up to the latest one are pruned, except the target state
+	gdb.SetStatesPrunedBlock(gdb.GetLatestBlockIndex())
 	return nil
 }
 
 func verifyState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -240,7 +241,7 @@ func verifyState(ctx *cli.Context) error {
 // contract codes are present.
 func traverseState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -333,7 +334,7 @@ func traverseState(ctx *cli.Context) error {
 // but it will check each trie node.
 func traverseRawState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, path.Join(cfg.Node.DataDir, "chaindata"))
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -4,19 +4,34 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"fmt"
+	"math/big"
 	"path"
+	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/node"
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/zilionixx/go-zilionixx/gossip/contract/driverauth100"
 	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
 	"github.com/zilionixx/go-zilionixx/valkeystore"
 	"github.com/zilionixx/go-zilionixx/valkeystore/encryption"
+	"github.com/zilionixx/go-zilionixx/zilionixx/genesis/driverauth"
 )
 
+var driverAuthAbi = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(driverauth100.ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
 var (
 	validatorCommand = cli.Command{
 		Name:     "validator",
@@ -79,6 +94,32 @@ password to file or expose in any other way.
     zilionixx validator convert
 
 Converts an account private key to a validator private key and saves in the validator keystore.
+`,
+			},
+			{
+				Name:   "rotate",
+				Usage:  "Rotate a validator key through NodeDriverAuth",
+				Action: utils.MigrateFlags(validatorKeyRotate),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+				},
+				ArgsUsage: "<validator ID> <NodeDriverAuth owner address> [endpoint]",
+				Description: `
+    zilionixx validator rotate
+
+Creates a new validator private key, unlocks it on a running node and submits
+the pubkey update to the NodeDriverAuth contract. Only the owner of NodeDriverAuth
+may update validator pubkeys, so the transaction is sent from the owner account,
+which has to be unlocked on the node.
+
+The node keeps signing events with the current key until the epoch where
+the validator profile reflects the new key, and then switches to the new key automatically.
+Remember to change --validator.pubkey before the next restart.
+
+The node is accessed via IPC at <DATADIR>/zilionixx.ipc unless an endpoint is specified.
+The validator and admin RPC APIs have to be enabled on the endpoint.
 `,
 			},
 		},
@@ -92,6 +133,12 @@ func validatorKeyCreate(ctx *cli.Context) error {
 
 	password := getPassPhrase("Your new validator key is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
+	generateValidatorKey(cfg.Node, password)
+	return nil
+}
+
+// generateValidatorKey creates a new validator key in the keystore and prints it.
+func generateValidatorKey(cfg node.Config, password string) validatorpk.PubKey {
 	privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
 	if err != nil {
 		utils.Fatalf("Failed to create account: %v", err)
@@ -102,7 +149,7 @@ func validatorKeyCreate(ctx *cli.Context) error {
 		Type: validatorpk.Types.Secp256k1,
 	}
 
-	valKeystore := valkeystore.NewDefaultFileRawKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	valKeystore := valkeystore.NewDefaultFileRawKeystore(path.Join(getValKeystoreDir(cfg), "validator"))
 	err = valKeystore.Add(publicKey, privateKey, password)
 	if err != nil {
 		utils.Fatalf("Failed to create account: %v", err)
@@ -121,6 +168,66 @@ func validatorKeyCreate(ctx *cli.Context) error {
 	fmt.Printf("- You must NEVER share the secret key with anyone! The key controls access to your validator!\n")
 	fmt.Printf("- You must BACKUP your key file! Without the key, it's impossible to zilionixxte the validator!\n")
 	fmt.Printf("- You must REMEMBER your password! Without the password, it's impossible to decrypt the key!\n\n")
+	return publicKey
+}
+
+// packUpdateValidatorPubkey packs a call of NodeDriverAuth.updateValidatorPubkey
+func packUpdateValidatorPubkey(validatorID uint64, pubkey validatorpk.PubKey) ([]byte, error) {
+	return driverAuthAbi.Pack("updateValidatorPubkey", new(big.Int).SetUint64(validatorID), pubkey.Bytes())
+}
+
+// validatorKeyRotate creates a new validator key and submits it to NodeDriverAuth via a running node.
+func validatorKeyRotate(ctx *cli.Context) error {
+	if len(ctx.Args()) < 2 {
+		utils.Fatalf("This command requires 2 arguments.")
+	}
+	cfg := makeAllConfigs(ctx)
+	utils.SetNodeConfig(ctx, &cfg.Node)
+
+	validatorID, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+	if err != nil || validatorID == 0 {
+		utils.Fatalf("Invalid validator ID: %s", ctx.Args().First())
+	}
+	if !common.IsHexAddress(ctx.Args().Get(1)) {
+		utils.Fatalf("Invalid sender address: %s", ctx.Args().Get(1))
+	}
+	from := common.HexToAddress(ctx.Args().Get(1))
+
+	endpoint := ctx.Args().Get(2)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("%s/zilionixx.ipc", cfg.Node.DataDir)
+	}
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		utils.Fatalf("Unable to attach to zilionixx: %v", err)
+	}
+	defer client.Close()
+
+	password := getPassPhrase("Your new validator key is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
+	pubkey := generateValidatorKey(cfg.Node, password)
+
+	// the node has to be able to sign with the new key once it takes effect
+	err = client.Call(nil, "validator_unlockKey", pubkey.String(), password)
+	if err != nil {
+		utils.Fatalf("Failed to unlock the new key on the node: %v", err)
+	}
+
+	data, err := packUpdateValidatorPubkey(validatorID, pubkey)
+	if err != nil {
+		utils.Fatalf("Failed to pack NodeDriverAuth call: %v", err)
+	}
+	var txHash common.Hash
+	err = client.Call(&txHash, "eth_sendTransaction", map[string]interface{}{
+		"from": from,
+		"to":   driverauth.ContractAddress,
+		"data": hexutil.Bytes(data),
+	})
+	if err != nil {
+		utils.Fatalf("Failed to submit the pubkey update: %v", err)
+	}
+
+	fmt.Printf("Pubkey update is submitted to NodeDriverAuth in tx %s\n", txHash.Hex())
+	fmt.Printf("The node will switch to the new key at the epoch where the update takes effect\n")
 	return nil
 }
 
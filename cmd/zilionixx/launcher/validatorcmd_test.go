@@ -0,0 +1,44 @@
+package launcher
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/gossip/contract/driverauth100"
+	"github.com/zilionixx/go-zilionixx/gossip/contract/sfc100"
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
+)
+
+func TestPackUpdateValidatorPubkey(t *testing.T) {
+	require := require.New(t)
+
+	pubkey := validatorpk.PubKey{
+		Raw:  hexutil.MustDecode("0x04a1b2c3"),
+		Type: validatorpk.Types.Secp256k1,
+	}
+	data, err := packUpdateValidatorPubkey(5, pubkey)
+	require.NoError(err)
+
+	parsed, err := abi.JSON(strings.NewReader(driverauth100.ContractABI))
+	require.NoError(err)
+	method, err := parsed.MethodById(data[:4])
+	require.NoError(err)
+	require.Equal("updateValidatorPubkey", method.Name)
+	require.Equal(hexutil.MustDecode("0x242a6e3f"), data[:4])
+
+	args, err := method.Inputs.Unpack(data[4:])
+	require.NoError(err)
+	require.Equal(big.NewInt(5), args[0])
+	require.Equal(pubkey.Bytes(), args[1])
+
+	// SFC has no such method, the update has to be sent to NodeDriverAuth
+	sfcAbi, err := abi.JSON(strings.NewReader(sfc100.ContractABI))
+	require.NoError(err)
+	_, err = sfcAbi.MethodById(data[:4])
+	require.Error(err)
+}
@@ -49,6 +49,7 @@ import (
 
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/utils/dbstats"
 	"github.com/zilionixx/go-zilionixx/utils/gsignercache"
 )
 
@@ -111,12 +112,13 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 		"queued":  make(map[string]map[string]*RPCTransaction),
 	}
 	pending, queue := s.b.TxPoolContent()
+	sponsorship := s.b.Upgrades().Sponsorship
 
 	// Flatten the pending transactions
 	for account, txs := range pending {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, sponsorship)
 		}
 		content["pending"][account.Hex()] = dump
 	}
@@ -124,20 +126,104 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range queue {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, sponsorship)
 		}
 		content["queued"][account.Hex()] = dump
 	}
 	return content
 }
 
+// RPCTxPoolStatus is the status of a single transaction as seen by the pool
+type RPCTxPoolStatus struct {
+	Status    string          `json:"status"`
+	Reason    string          `json:"reason,omitempty"`
+	DroppedAt *hexutil.Uint64 `json:"droppedAt,omitempty"`
+}
+
 // Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
-	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+// If the hash is specified, it returns the status of the transaction instead,
+// explaining why the transaction was dropped if it was dropped recently.
+func (s *PublicTxPoolAPI) Status(hash *common.Hash) interface{} {
+	if hash == nil {
+		pending, queue := s.b.Stats()
+		return map[string]hexutil.Uint{
+			"pending": hexutil.Uint(pending),
+			"queued":  hexutil.Uint(queue),
+		}
+	}
+	status, drop := s.b.TxPoolTxStatus(*hash)
+	res := &RPCTxPoolStatus{}
+	switch status {
+	case evmcore.TxStatusPending:
+		res.Status = "pending"
+	case evmcore.TxStatusQueued:
+		res.Status = "queued"
+	case evmcore.TxStatusIncluded:
+		res.Status = "included"
+	case evmcore.TxStatusDropped:
+		res.Status = "dropped"
+		res.Reason = drop.Reason.String()
+		droppedAt := hexutil.Uint64(drop.Time.Unix())
+		res.DroppedAt = &droppedAt
+	default:
+		res.Status = "unknown"
+	}
+	return res
+}
+
+// RPCNonceGap is a range of missing nonces which blocks queued transactions
+type RPCNonceGap struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// RPCTxReplacement is a pending transaction along with the minimum gas price of its replacement
+type RPCTxReplacement struct {
+	Hash             common.Hash    `json:"hash"`
+	Nonce            hexutil.Uint64 `json:"nonce"`
+	GasPrice         *hexutil.Big   `json:"gasPrice"`
+	ReplacementPrice *hexutil.Big   `json:"replacementPrice"`
+}
+
+// RPCTxPoolAccountStatus is a state of an account's transactions in the pool
+type RPCTxPoolAccountStatus struct {
+	StateNonce   hexutil.Uint64     `json:"stateNonce"`
+	PendingNonce hexutil.Uint64     `json:"pendingNonce"`
+	Pending      []RPCTxReplacement `json:"pending"`
+	Queued       []hexutil.Uint64   `json:"queued"`
+	Gaps         []RPCNonceGap      `json:"gaps"`
+}
+
+// AccountStatus returns the state and pending nonces of the account, nonces of its queued transactions,
+// the nonce gaps which keep queued transactions from being executed, and the minimum gas prices
+// required to replace its pending transactions.
+func (s *PublicTxPoolAPI) AccountStatus(address common.Address) *RPCTxPoolAccountStatus {
+	status := s.b.TxPoolAccountStatus(address)
+	res := &RPCTxPoolAccountStatus{
+		StateNonce:   hexutil.Uint64(status.StateNonce),
+		PendingNonce: hexutil.Uint64(status.PendingNonce),
+		Pending:      make([]RPCTxReplacement, 0, len(status.Pending)),
+		Queued:       make([]hexutil.Uint64, 0, len(status.Queued)),
+		Gaps:         make([]RPCNonceGap, 0, len(status.Gaps)),
+	}
+	for _, r := range status.Pending {
+		res.Pending = append(res.Pending, RPCTxReplacement{
+			Hash:             r.Tx.Hash(),
+			Nonce:            hexutil.Uint64(r.Tx.Nonce()),
+			GasPrice:         (*hexutil.Big)(r.Tx.GasPrice()),
+			ReplacementPrice: (*hexutil.Big)(r.MinPrice),
+		})
+	}
+	for _, nonce := range status.Queued {
+		res.Queued = append(res.Queued, hexutil.Uint64(nonce))
+	}
+	for _, gap := range status.Gaps {
+		res.Gaps = append(res.Gaps, RPCNonceGap{
+			From: hexutil.Uint64(gap.From),
+			To:   hexutil.Uint64(gap.To),
+		})
 	}
+	return res
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -825,11 +911,11 @@ func (args *CallArgs) ToMessage(globalGasCap uint64) types.Message {
 // if statDiff is set, all diff will be applied first and then execute the call
 // message.
 type OverrideAccount struct {
-	Nonce     *hexutil.Uint64              `json:"nonce"`
-	Code      *hexutil.Bytes               `json:"code"`
-	Balance   **hexutil.Big                `json:"balance"`
-	State     *map[common.Hash]common.Hash `json:"state"`
-	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+	Nonce     *hexutil.Uint64              `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes               `json:"code,omitempty"`
+	Balance   **hexutil.Big                `json:"balance,omitempty"`
+	State     *map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
 }
 
 // StateOverride is the collection of overridden accounts.
@@ -871,6 +957,11 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 }
 
 func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalGasCap uint64) (*evmcore.ExecutionResult, error) {
+	return doCall(ctx, b, args, blockNrOrHash, overrides, vmCfg, timeout, globalGasCap, nil)
+}
+
+// doCall executes the call, and records the state changes into diff if it isn't nil
+func doCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalGasCap uint64, diff *StateDiff) (*evmcore.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -905,6 +996,12 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 		evm.Cancel()
 	}()
 
+	var recorder *stateDiffRecorder
+	if diff != nil {
+		recorder = newStateDiffRecorder(evm.StateDB)
+		evm.StateDB = recorder
+	}
+
 	// Execute the message.
 	gp := new(evmcore.GasPool).AddGas(math.MaxUint64)
 	result, err := evmcore.ApplyMessage(evm, msg, gp)
@@ -919,6 +1016,10 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 	if err != nil {
 		return result, fmt.Errorf("err: %w (supplied gas %d)", err, msg.Gas())
 	}
+	if recorder != nil {
+		state.Finalise(true)
+		*diff = recorder.Diff()
+	}
 	return result, nil
 }
 
@@ -1212,7 +1313,7 @@ func eventIDToHex(id hash.Event) hexutil.Bytes {
 
 // RPCMarshalHeader converts the given header to the RPC output .
 func RPCMarshalHeader(head *evmcore.EvmHeader, bloom types.Bloom) map[string]interface{} {
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"number":           (*hexutil.Big)(head.Number),
 		"hash":             head.Hash, // store EvmBlock's hash in extra, because extra is always empty
 		"parentHash":       head.ParentHash,
@@ -1232,12 +1333,17 @@ func RPCMarshalHeader(head *evmcore.EvmHeader, bloom types.Bloom) map[string]int
 		"transactionsRoot": head.TxHash,
 		"receiptsRoot":     common.Hash{},
 	}
+	if head.BaseFee != nil {
+		result["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
+	}
+	return result
 }
 
 // RPCMarshalBlock converts the given block to the RPC output which depends on fullTx. If inclTx is true transactions are
 // returned. When fullTx is true the returned block contains full transaction details, otherwise it will only contain
 // transaction hashes.
-func RPCMarshalBlock(block *evmcore.EvmBlock, bloom types.Bloom, inclTx bool, fullTx bool) (map[string]interface{}, error) {
+// Sponsors of the transactions are reported if sponsorship is enabled for the block.
+func RPCMarshalBlock(block *evmcore.EvmBlock, bloom types.Bloom, inclTx bool, fullTx bool, sponsorship bool) (map[string]interface{}, error) {
 	fields := RPCMarshalHeader(block.Header(), bloom)
 	fields["size"] = hexutil.Uint64(block.EthBlock().Size())
 
@@ -1247,7 +1353,7 @@ func RPCMarshalBlock(block *evmcore.EvmBlock, bloom types.Bloom, inclTx bool, fu
 		}
 		if fullTx {
 			formatTx = func(tx *types.Transaction) (interface{}, error) {
-				return newRPCTransactionFromBlockHash(block, tx.Hash()), nil
+				return newRPCTransactionFromBlockHash(block, tx.Hash(), sponsorship), nil
 			}
 		}
 		txs := block.Transactions
@@ -1281,7 +1387,7 @@ func (s *PublicBlockChainAPI) rpcMarshalHeader(header *evmcore.EvmHeader, bloom
 // rpcMarshalBlock uses the generalized output filler, then adds the total difficulty field, which requires
 // a `PublicBlockchainAPI`.
 func (s *PublicBlockChainAPI) rpcMarshalBlock(b *evmcore.EvmBlock, bloom types.Bloom, inclTx bool, fullTx bool) (map[string]interface{}, error) {
-	fields, err := RPCMarshalBlock(b, bloom, inclTx, fullTx)
+	fields, err := RPCMarshalBlock(b, bloom, inclTx, fullTx, s.b.BlockUpgrades(b.NumberU64()).Sponsorship)
 	if err != nil {
 		return nil, err
 	}
@@ -1308,11 +1414,13 @@ type RPCTransaction struct {
 	V                *hexutil.Big      `json:"v"`
 	R                *hexutil.Big      `json:"r"`
 	S                *hexutil.Big      `json:"s"`
+	Sponsor          *common.Address   `json:"sponsor,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
 // representation, with the given location metadata set (if available).
-func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
+// The sponsor is reported only if sponsorship is enabled for the transaction's block.
+func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, sponsorship bool) *RPCTransaction {
 	// Determine the signer. For replay-protected transactions, use the most permissive
 	// signer, because we assume that signers are backwards-compatible with old
 	// transactions. For non-protected transactions, the homestead signer signer is used
@@ -1349,22 +1457,27 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		al := tx.AccessList()
 		result.Accesses = &al
 		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		if sponsorship {
+			if sponsor, err := inter.TxSponsor(tx, from); err == nil {
+				result.Sponsor = &sponsor
+			}
+		}
 	}
 	return result
 }
 
 // newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
-func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
-	return newRPCTransaction(tx, common.Hash{}, 0, 0)
+func newRPCPendingTransaction(tx *types.Transaction, sponsorship bool) *RPCTransaction {
+	return newRPCTransaction(tx, common.Hash{}, 0, 0, sponsorship)
 }
 
 // newRPCTransactionFromBlockIndex returns a transaction that will serialize to the RPC representation.
-func newRPCTransactionFromBlockIndex(b *evmcore.EvmBlock, index uint64) *RPCTransaction {
+func newRPCTransactionFromBlockIndex(b *evmcore.EvmBlock, index uint64, sponsorship bool) *RPCTransaction {
 	txs := b.Transactions
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	return newRPCTransaction(txs[index], b.Hash, b.NumberU64(), index)
+	return newRPCTransaction(txs[index], b.Hash, b.NumberU64(), index, sponsorship)
 }
 
 // newRPCRawTransactionFromBlockIndex returns the bytes of a transaction given a block and a transaction index.
@@ -1378,10 +1491,10 @@ func newRPCRawTransactionFromBlockIndex(b *evmcore.EvmBlock, index uint64) hexut
 }
 
 // newRPCTransactionFromBlockHash returns a transaction that will serialize to the RPC representation.
-func newRPCTransactionFromBlockHash(b *evmcore.EvmBlock, hash common.Hash) *RPCTransaction {
+func newRPCTransactionFromBlockHash(b *evmcore.EvmBlock, hash common.Hash, sponsorship bool) *RPCTransaction {
 	for idx, tx := range b.Transactions {
 		if tx.Hash() == hash {
-			return newRPCTransactionFromBlockIndex(b, uint64(idx))
+			return newRPCTransactionFromBlockIndex(b, uint64(idx), sponsorship)
 		}
 	}
 	return nil
@@ -1523,7 +1636,7 @@ func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Co
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
 func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.BlockUpgrades(block.NumberU64()).Sponsorship)
 	}
 	return nil
 }
@@ -1531,7 +1644,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx conte
 // GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
 func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
 	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return newRPCTransactionFromBlockIndex(block, uint64(index), s.b.BlockUpgrades(block.NumberU64()).Sponsorship)
 	}
 	return nil
 }
@@ -1583,17 +1696,56 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 		if err != nil {
 			return nil, err
 		}
-		return newRPCTransaction(tx, header.Hash, blockNumber, index), nil
+		return newRPCTransaction(tx, header.Hash, blockNumber, index, s.b.BlockUpgrades(blockNumber).Sponsorship), nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
-		return newRPCPendingTransaction(tx), nil
+		return newRPCPendingTransaction(tx, s.b.Upgrades().Sponsorship), nil
+	}
+	// The transaction is finalized in a block which isn't indexed anymore
+	if block, pruned := s.b.TxPruned(hash); pruned {
+		return nil, newTxIndexPrunedError(hash, block)
 	}
 
 	// Transaction unknown, return as such
 	return nil, nil
 }
 
+// txIndexPrunedError is an API error that is returned if a transaction
+// is finalized in an old block, whose transactions are removed from the index.
+type txIndexPrunedError struct {
+	error
+	block hexutil.Uint64
+}
+
+func newTxIndexPrunedError(hash common.Hash, block uint64) *txIndexPrunedError {
+	return &txIndexPrunedError{
+		error: fmt.Errorf("transaction %s of block %d is pruned from the index", hash.Hex(), block),
+		block: hexutil.Uint64(block),
+	}
+}
+
+// ErrorCode returns the JSON error code for a transaction pruned from the index.
+func (e *txIndexPrunedError) ErrorCode() int {
+	return -32000
+}
+
+// ErrorData returns the block of the pruned transaction.
+func (e *txIndexPrunedError) ErrorData() interface{} {
+	return map[string]interface{}{"pruned": true, "blockNumber": e.block}
+}
+
+// TxIndexTail returns the first block whose transactions are indexed,
+// or null if transactions of all the blocks are indexed.
+// Transactions of the older blocks aren't found by hash.
+func (s *PublicTransactionPoolAPI) TxIndexTail() *hexutil.Uint64 {
+	tail, pruned := s.b.TxIndexTail()
+	if !pruned {
+		return nil
+	}
+	return (*hexutil.Uint64)(&tail)
+}
+
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	// Retrieve a finalized transaction, or a pooled otherwise
@@ -1804,6 +1956,16 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, b.SendTx)
+}
+
+// SubmitPrivateTransaction is a helper function that submits tx to txPool as a private
+// transaction, which isn't gossiped publicly, and logs a message.
+func SubmitPrivateTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, b.SendPrivateTx)
+}
+
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, send func(context.Context, *types.Transaction) error) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
@@ -1813,14 +1975,17 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
-		return common.Hash{}, err
-	} // Print a log with full tx details for manual investigations and interventions
 	signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number)
 	from, err := types.Sender(signer, tx)
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if err := b.TxSubmitLimiter().Allow(ctx, from); err != nil {
+		return common.Hash{}, err
+	}
+	if err := send(ctx, tx); err != nil {
+		return common.Hash{}, err
+	} // Print a log with full tx details for manual investigations and interventions
 
 	if tx.To() == nil {
 		addr := crypto.CreateAddress(from, tx.Nonce())
@@ -1889,6 +2054,17 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// SendPrivateTransaction will add the signed transaction to the transaction pool without public gossip.
+// The transaction is forwarded only to validators of the current epoch, and is dropped
+// if it isn't included into a block within the configured number of blocks.
+func (s *PublicTransactionPoolAPI) SendPrivateTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitPrivateTransaction(ctx, s.b, tx)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -1965,10 +2141,11 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 		}
 	}
 	transactions := make([]*RPCTransaction, 0, len(pending))
+	sponsorship := s.b.Upgrades().Sponsorship
 	for _, tx := range pending {
 		from, _ := types.Sender(s.signer, tx)
 		if _, exists := accounts[from]; exists {
-			transactions = append(transactions, newRPCPendingTransaction(tx))
+			transactions = append(transactions, newRPCPendingTransaction(tx, sponsorship))
 		}
 	}
 	return transactions, nil
@@ -2143,6 +2320,21 @@ func (api *PrivateDebugAPI) ChaindbCompact() error {
 	return nil
 }
 
+// DbStats returns the number of keys and their sizes per each table of the node databases.
+func (api *PrivateDebugAPI) DbStats(ctx context.Context) ([]dbstats.Stats, error) {
+	return api.b.DBStats(ctx)
+}
+
+// DbCompact compacts the key range of a table of the database,
+// or the whole database if the table isn't specified.
+func (api *PrivateDebugAPI) DbCompact(db string, table *string) error {
+	var t string
+	if table != nil {
+		t = *table
+	}
+	return api.b.DBCompact(db, t)
+}
+
 // SetHead rewinds the head of the blockchain to a previous block.
 func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) error {
 	return errors.New("zilionixx cannot rewind blocks due to the BFT algorithm")
@@ -0,0 +1,94 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+// prunedTxsBackend is a Backend which knows only the transactions removed from the index
+type prunedTxsBackend struct {
+	Backend
+	pruned map[common.Hash]uint64
+}
+
+func (b *prunedTxsBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (b *prunedTxsBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	return nil, 0, 0, nil
+}
+
+func (b *prunedTxsBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
+	return nil
+}
+
+func (b *prunedTxsBackend) TxPruned(txHash common.Hash) (uint64, bool) {
+	block, ok := b.pruned[txHash]
+	return block, ok
+}
+
+func TestGetTransactionByHashPruned(t *testing.T) {
+	require := require.New(t)
+
+	api := NewPublicTransactionPoolAPI(&prunedTxsBackend{
+		pruned: map[common.Hash]uint64{{1}: 5},
+	}, nil)
+
+	// unknown transaction
+	tx, err := api.GetTransactionByHash(context.Background(), common.Hash{2})
+	require.NoError(err)
+	require.Nil(tx)
+
+	// transaction pruned from the index
+	tx, err = api.GetTransactionByHash(context.Background(), common.Hash{1})
+	require.Nil(tx)
+	require.Error(err)
+	prunedErr, ok := err.(*txIndexPrunedError)
+	require.True(ok)
+	require.Equal(-32000, prunedErr.ErrorCode())
+	require.Equal(map[string]interface{}{"pruned": true, "blockNumber": hexutil.Uint64(5)}, prunedErr.ErrorData())
+}
+
+func TestNewRPCTransactionSponsor(t *testing.T) {
+	require := require.New(t)
+
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	sponsorKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+
+	data := &types.AccessListTx{
+		ChainID:  params.TestChainConfig.ChainID,
+		GasPrice: big.NewInt(1),
+		Gas:      100000,
+		To:       &common.Address{1},
+		Value:    new(big.Int),
+	}
+	sig, err := crypto.Sign(inter.SponsorHash(types.NewTx(data), sender).Bytes(), sponsorKey)
+	require.NoError(err)
+	sponsored, err := inter.WithSponsorSignature(data, sig)
+	require.NoError(err)
+	tx, err := types.SignNewTx(senderKey, types.NewEIP2930Signer(params.TestChainConfig.ChainID), sponsored)
+	require.NoError(err)
+
+	// the sponsor is reported only for the blocks after the Sponsorship upgrade
+	res := newRPCTransaction(tx, common.Hash{1}, 1, 0, true)
+	require.Equal(sender, res.From)
+	require.NotNil(res.Sponsor)
+	require.Equal(crypto.PubkeyToAddress(sponsorKey.PublicKey), *res.Sponsor)
+
+	res = newRPCTransaction(tx, common.Hash{1}, 1, 0, false)
+	require.Nil(res.Sponsor)
+}
@@ -37,6 +37,8 @@ import (
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/gossip/sfcapi"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/utils/dbstats"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -56,11 +58,14 @@ type Backend interface {
 	Progress() PeerProgress
 	SuggestPrice(ctx context.Context) (*big.Int, error)
 	ChainDb() ethdb.Database
+	DBStats(ctx context.Context) ([]dbstats.Stats, error)
+	DBCompact(db, table string) error
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
-	RPCGasCap() uint64        // global gas cap for eth_call over rpc: DoS protection
-	RPCTxFeeCap() float64     // global tx fee cap for all transaction related APIs
-	UnprotectedAllowed() bool // allows only for EIP155 transactions.
+	RPCGasCap() uint64                 // global gas cap for eth_call over rpc: DoS protection
+	RPCTxFeeCap() float64              // global tx fee cap for all transaction related APIs
+	TxSubmitLimiter() *TxSubmitLimiter // rate limits of transaction submission over rpc
+	UnprotectedAllowed() bool          // allows only for EIP155 transactions.
 	CalcLogsBloom() bool
 
 	// Blockchain API
@@ -78,21 +83,29 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error)
+	TxIndexTail() (uint64, bool)
+	TxPruned(txHash common.Hash) (uint64, bool)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
+	TxPoolTxStatus(hash common.Hash) (evmcore.TxStatus, *evmcore.DroppedTx)
+	TxPoolAccountStatus(addr common.Address) evmcore.AccountStatus
 	SubscribeNewTxsNotify(chan<- evmcore.NewTxsNotify) notify.Subscription
 
 	ChainConfig() *params.ChainConfig
+	Upgrades() zilionixx.Upgrades
+	BlockUpgrades(number uint64) zilionixx.Upgrades
 	CurrentBlock() *evmcore.EvmBlock
 
 	// ZilionBFT DAG API
 	GetEventPayload(ctx context.Context, shortEventID string) (*inter.EventPayload, error)
 	GetEvent(ctx context.Context, shortEventID string) (*inter.Event, error)
 	GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error)
+	ForEachEpochEvent(ctx context.Context, epoch rpc.BlockNumber, onEvent func(event *inter.EventPayload) bool) error
 	CurrentEpoch(ctx context.Context) idx.Epoch
 	SealedEpochTiming(ctx context.Context) (start inter.Timestamp, end inter.Timestamp)
 
@@ -0,0 +1,175 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/zilionixx/go-zilionixx/evmcore"
+)
+
+var errEmptyBundle = errors.New("empty bundle")
+
+// BundleTx is a transaction of a bundle, either a signed RLP-encoded transaction or an unsigned call
+type BundleTx struct {
+	Signed *types.Transaction
+	Call   *CallArgs
+}
+
+// UnmarshalJSON parses either a hex string with a signed transaction or call arguments
+func (t *BundleTx) UnmarshalJSON(input []byte) error {
+	if len(input) != 0 && input[0] == '"' {
+		var raw hexutil.Bytes
+		if err := json.Unmarshal(input, &raw); err != nil {
+			return err
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(raw, tx); err != nil {
+			return err
+		}
+		t.Signed = tx
+		return nil
+	}
+	t.Call = new(CallArgs)
+	return json.Unmarshal(input, t.Call)
+}
+
+// BundleTxResult is a result of a bundle transaction execution
+type BundleTxResult struct {
+	TxHash     *common.Hash   `json:"txHash,omitempty"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Error      string         `json:"error,omitempty"`
+	Revert     hexutil.Bytes  `json:"revert,omitempty"`
+	Logs       []*types.Log   `json:"logs"`
+	StateDiff  StateDiff      `json:"stateDiff"`
+}
+
+// CallBundleResult is a result of a bundle execution
+type CallBundleResult struct {
+	Results []BundleTxResult `json:"results"`
+	GasUsed hexutil.Uint64   `json:"gasUsed"`
+}
+
+// DoCallBundle executes the transactions one after another on top of the block's state.
+// Failed or reverted transactions don't interrupt the bundle, while the transactions which
+// cannot be applied (e.g. with a wrong nonce or insufficient balance) make the whole bundle fail.
+func DoCallBundle(ctx context.Context, b Backend, txs []BundleTx, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*CallBundleResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM bundle finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(txs) == 0 {
+		return nil, errEmptyBundle
+	}
+	statedb, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		return nil, err
+	}
+	// Setup context so it may be cancelled the bundle has completed
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	signer := types.MakeSigner(b.ChainConfig(), header.Number)
+	gp := new(evmcore.GasPool).AddGas(math.MaxUint64)
+	res := &CallBundleResult{
+		Results: make([]BundleTxResult, 0, len(txs)),
+	}
+	for i, tx := range txs {
+		var (
+			msg    evmcore.Message
+			txHash common.Hash
+			item   BundleTxResult
+		)
+		if tx.Signed != nil {
+			if globalGasCap != 0 && tx.Signed.Gas() > globalGasCap {
+				return nil, fmt.Errorf("tx %d: gas %d exceeds the cap %d", i, tx.Signed.Gas(), globalGasCap)
+			}
+			m, err := evmcore.TxAsMessage(tx.Signed, signer, b.Upgrades().Sponsorship)
+			if err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			msg = m
+			txHash = tx.Signed.Hash()
+			item.TxHash = &txHash
+		} else {
+			msg = tx.Call.ToMessage(globalGasCap)
+			// unsigned calls have no hash, a placeholder is used to collect the logs
+			txHash = common.BigToHash(new(big.Int).SetUint64(uint64(i + 1)))
+		}
+		statedb.Prepare(txHash, header.Hash, i)
+
+		evm, vmError, err := b.GetEVM(ctx, msg, statedb, header, nil)
+		if err != nil {
+			return nil, err
+		}
+		recorder := newStateDiffRecorder(evm.StateDB)
+		evm.StateDB = recorder
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-stop:
+			}
+		}()
+		result, err := evmcore.ApplyMessage(evm, msg, gp)
+		close(stop)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w (supplied gas %d)", i, err, msg.Gas())
+		}
+		statedb.Finalise(true)
+
+		item.GasUsed = hexutil.Uint64(result.UsedGas)
+		item.ReturnData = result.Return()
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+			item.Revert = result.Revert()
+		}
+		item.Logs = statedb.GetLogs(txHash)
+		if item.Logs == nil {
+			item.Logs = []*types.Log{}
+		}
+		if tx.Signed == nil {
+			for _, l := range item.Logs {
+				l.TxHash = common.Hash{}
+			}
+		}
+		item.StateDiff = recorder.Diff()
+
+		res.Results = append(res.Results, item)
+		res.GasUsed += item.GasUsed
+	}
+	return res, nil
+}
+
+// CallBundle executes an ordered list of signed or unsigned transactions on top of the given block's state,
+// returning results, gas used, logs and state changes of each transaction.
+//
+// Note, this function doesn't make any changes in the state/blockchain or the transaction pool.
+func (s *PublicBlockChainAPI) CallBundle(ctx context.Context, txs []BundleTx, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (*CallBundleResult, error) {
+	return DoCallBundle(ctx, s.b, txs, blockNrOrHash, overrides, 5*time.Second, s.b.RPCGasCap())
+}
@@ -0,0 +1,435 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/evmcore"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+var (
+	// logCode emits an empty LOG0
+	logCode = common.FromHex("0x60006000a000")
+	// counterCode increments slot 0 and returns its new value
+	counterCode = common.FromHex("0x6000546001018060005560005260206000f3")
+	// revertCode reverts with empty data
+	revertCode = common.FromHex("0x60006000fd")
+	// suicideCode self-destructs in favour of the caller
+	suicideCode = common.FromHex("0x33ff")
+	// deployCode deploys a contract with code 0x00
+	deployCode = common.FromHex("0x600060005360016000f3")
+
+	logAddr     = common.Address{0x10}
+	counterAddr = common.Address{0x11}
+	revertAddr  = common.Address{0x12}
+	suicideAddr = common.Address{0x13}
+)
+
+const testGasLimit = 100000000
+
+type testTxPosition struct {
+	block uint64
+	index uint64
+}
+
+// testBackend is a Backend over an in-memory chain of states, only the methods used by calls are implemented
+type testBackend struct {
+	Backend
+
+	db     state.Database
+	roots  []common.Hash
+	blocks []*evmcore.EvmBlock
+	txs    map[common.Hash]testTxPosition
+}
+
+func newTestBackend(t *testing.T, genesis func(statedb *state.StateDB)) *testBackend {
+	b := &testBackend{
+		db:  state.NewDatabase(rawdb.NewMemoryDatabase()),
+		txs: make(map[common.Hash]testTxPosition),
+	}
+	statedb, err := state.New(common.Hash{}, b.db, nil)
+	require.NoError(t, err)
+	genesis(statedb)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	b.roots = append(b.roots, root)
+	b.blocks = append(b.blocks, evmcore.NewEvmBlock(&evmcore.EvmHeader{
+		Number:   big.NewInt(0),
+		Hash:     common.Hash{0},
+		Root:     root,
+		GasLimit: testGasLimit,
+	}, nil))
+	return b
+}
+
+// addBlock processes the internal transactions and then the regular transactions on top of the last block
+func (b *testBackend) addBlock(t *testing.T, internalTxs, txs types.Transactions) types.Receipts {
+	parent := b.blocks[len(b.blocks)-1]
+	statedb, err := state.New(b.roots[len(b.roots)-1], b.db, nil)
+	require.NoError(t, err)
+
+	all := append(append(types.Transactions{}, internalTxs...), txs...)
+	block := evmcore.NewEvmBlock(&evmcore.EvmHeader{
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Hash:       common.Hash{byte(len(b.blocks))},
+		ParentHash: parent.Hash,
+		GasLimit:   testGasLimit,
+	}, all)
+
+	processor := evmcore.NewStateProcessor(b.ChainConfig(), nil, b.Upgrades())
+	var gasUsed uint64
+	internalReceipts, _, _, err := processor.Process(evmcore.NewEvmBlock(&block.EvmHeader, internalTxs), statedb, vm.Config{}, &gasUsed, true, nil)
+	require.NoError(t, err)
+	receipts, _, skipped, err := processor.Process(evmcore.NewEvmBlock(&block.EvmHeader, txs), statedb, vm.Config{}, &gasUsed, false, nil)
+	require.NoError(t, err)
+	require.Empty(t, skipped)
+
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	block.Root = root
+	for i, tx := range all {
+		b.txs[tx.Hash()] = testTxPosition{uint64(len(b.blocks)), uint64(i)}
+	}
+	b.roots = append(b.roots, root)
+	b.blocks = append(b.blocks, block)
+	return append(internalReceipts, receipts...)
+}
+
+func (b *testBackend) blockNumber(number rpc.BlockNumber) int {
+	if number < 0 {
+		return len(b.blocks) - 1
+	}
+	return int(number)
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	number, ok := blockNrOrHash.Number()
+	if !ok {
+		return nil, nil, errors.New("only block numbers are supported")
+	}
+	n := b.blockNumber(number)
+	if n >= len(b.blocks) {
+		return nil, nil, nil
+	}
+	statedb, err := state.New(b.roots[n], b.db, nil)
+	return statedb, &b.blocks[n].EvmHeader, err
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	n := b.blockNumber(number)
+	if n >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[n], nil
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	pos, ok := b.txs[txHash]
+	if !ok {
+		return nil, 0, 0, nil
+	}
+	return b.blocks[pos.block].Transactions[pos.index], pos.block, pos.index, nil
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg evmcore.Message, state *state.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	if vmConfig == nil {
+		vmConfig = &vm.Config{}
+	}
+	txContext := evmcore.NewEVMTxContext(msg)
+	context := evmcore.NewEVMBlockContext(header, nil, nil)
+	return vm.NewEVM(context, txContext, state, b.ChainConfig(), *vmConfig), func() error { return nil }, nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (b *testBackend) Upgrades() zilionixx.Upgrades {
+	return zilionixx.Upgrades{}
+}
+
+func (b *testBackend) BlockUpgrades(number uint64) zilionixx.Upgrades {
+	return b.Upgrades()
+}
+
+func (b *testBackend) signer() types.Signer {
+	return types.MakeSigner(b.ChainConfig(), big.NewInt(int64(len(b.blocks))))
+}
+
+func signTestTx(t *testing.T, b *testBackend, key *ecdsa.PrivateKey, nonce uint64, to *common.Address, value int64, gas uint64, data []byte) *types.Transaction {
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(nonce, big.NewInt(value), gas, common.Big1, data)
+	} else {
+		tx = types.NewTransaction(nonce, *to, big.NewInt(value), gas, common.Big1, data)
+	}
+	signed, err := types.SignTx(tx, b.signer(), key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func genesisWith(balances map[common.Address]int64) func(statedb *state.StateDB) {
+	return func(statedb *state.StateDB) {
+		for addr, balance := range balances {
+			statedb.SetBalance(addr, big.NewInt(balance))
+		}
+		statedb.SetCode(logAddr, logCode)
+		statedb.SetCode(counterAddr, counterCode)
+		statedb.SetCode(revertAddr, revertCode)
+		statedb.SetCode(suicideAddr, suicideCode)
+		statedb.SetBalance(suicideAddr, big.NewInt(7))
+		statedb.SetNonce(suicideAddr, 1)
+	}
+}
+
+func callTo(from, to common.Address) BundleTx {
+	return BundleTx{Call: &CallArgs{From: &from, To: &to}}
+}
+
+func TestDoCallBundleFailures(t *testing.T) {
+	aliceKey, alice := newTestKey(t)
+	poorKey, _ := newTestKey(t)
+	_, bob := newTestKey(t)
+	// unsigned calls increment the nonce of the sender, so they are sent by another account
+	_, carol := newTestKey(t)
+	b := newTestBackend(t, genesisWith(map[common.Address]int64{alice: 1e18}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	for _, tt := range []struct {
+		name   string
+		txs    []BundleTx
+		gasCap uint64
+		err    error
+		errors []string // per-tx errors, if the bundle succeeds
+	}{
+		{
+			name: "empty bundle",
+			err:  errEmptyBundle,
+		},
+		{
+			name: "nonce too high",
+			txs: []BundleTx{
+				callTo(carol, counterAddr),
+				{Signed: signTestTx(t, b, aliceKey, 1, &bob, 1, 21000, nil)},
+			},
+			err: evmcore.ErrNonceTooHigh,
+		},
+		{
+			name: "nonce reused within the bundle",
+			txs: []BundleTx{
+				{Signed: signTestTx(t, b, aliceKey, 0, &bob, 1, 21000, nil)},
+				{Signed: signTestTx(t, b, aliceKey, 0, &bob, 1, 21000, nil)},
+			},
+			err: evmcore.ErrNonceTooLow,
+		},
+		{
+			name: "insufficient balance",
+			txs: []BundleTx{
+				{Signed: signTestTx(t, b, poorKey, 0, &bob, 1, 21000, nil)},
+			},
+			err: evmcore.ErrInsufficientFunds,
+		},
+		{
+			name:   "gas above the cap",
+			txs:    []BundleTx{{Signed: signTestTx(t, b, aliceKey, 0, &bob, 1, 50000, nil)}},
+			gasCap: 30000,
+			err:    errors.New("tx 0: gas 50000 exceeds the cap 30000"),
+		},
+		{
+			name: "intrinsic gas too low",
+			txs: []BundleTx{
+				{Call: &CallArgs{From: &carol, To: &bob, Gas: (*hexutil.Uint64)(new(uint64))}},
+			},
+			err: evmcore.ErrIntrinsicGas,
+		},
+		{
+			name: "reverted and out of gas txs don't fail the bundle",
+			txs: []BundleTx{
+				callTo(carol, revertAddr),
+				{Call: &CallArgs{From: &carol, To: &counterAddr, Gas: func() *hexutil.Uint64 { g := hexutil.Uint64(21100); return &g }()}},
+				{Signed: signTestTx(t, b, aliceKey, 0, &revertAddr, 0, 50000, nil)},
+				callTo(carol, counterAddr),
+			},
+			errors: []string{vm.ErrExecutionReverted.Error(), vm.ErrOutOfGas.Error(), vm.ErrExecutionReverted.Error(), ""},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			res, err := DoCallBundle(context.Background(), b, tt.txs, latest, nil, 0, tt.gasCap)
+			if tt.err != nil {
+				require.Error(err)
+				require.Nil(res)
+				if !errors.Is(err, tt.err) {
+					require.Equal(tt.err.Error(), err.Error())
+				}
+				return
+			}
+			require.NoError(err)
+			require.Len(res.Results, len(tt.txs))
+			var gasUsed hexutil.Uint64
+			for i, item := range res.Results {
+				require.Equal(tt.errors[i], item.Error, i)
+				gasUsed += item.GasUsed
+			}
+			require.Equal(gasUsed, res.GasUsed)
+			// the last counter call sees the state left by the preceding txs
+			require.Equal(common.BigToHash(common.Big1).Bytes(), []byte(res.Results[len(tt.txs)-1].ReturnData))
+		})
+	}
+}
+
+func TestDoCallBundleLogs(t *testing.T) {
+	require := require.New(t)
+
+	aliceKey, alice := newTestKey(t)
+	_, carol := newTestKey(t)
+	b := newTestBackend(t, genesisWith(map[common.Address]int64{alice: 1e18}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	signed := signTestTx(t, b, aliceKey, 0, &logAddr, 0, 50000, nil)
+	res, err := DoCallBundle(context.Background(), b, []BundleTx{
+		callTo(carol, logAddr),
+		callTo(carol, counterAddr),
+		{Signed: signed},
+		callTo(carol, logAddr),
+	}, latest, nil, 0, 0)
+	require.NoError(err)
+	require.Len(res.Results, 4)
+
+	for i, item := range res.Results {
+		if i == 2 {
+			require.NotNil(item.TxHash)
+			require.Equal(signed.Hash(), *item.TxHash)
+		} else {
+			require.Nil(item.TxHash, i)
+		}
+	}
+	// each tx gets only its own logs, the placeholder hashes of unsigned calls aren't exposed
+	require.Empty(res.Results[1].Logs)
+	require.NotNil(res.Results[1].Logs)
+	for _, i := range []int{0, 2, 3} {
+		logs := res.Results[i].Logs
+		require.Len(logs, 1, i)
+		require.Equal(logAddr, logs[0].Address)
+		require.Equal(uint(i), logs[0].TxIndex)
+		if i == 2 {
+			require.Equal(signed.Hash(), logs[0].TxHash)
+		} else {
+			require.Equal(common.Hash{}, logs[0].TxHash)
+		}
+	}
+}
+
+func TestDoCallBundleStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	aliceKey, alice := newTestKey(t)
+	_, bob := newTestKey(t)
+	b := newTestBackend(t, genesisWith(map[common.Address]int64{alice: 1e18}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	transfer := signTestTx(t, b, aliceKey, 0, &bob, 5, 21000, nil)
+	create := signTestTx(t, b, aliceKey, 1, nil, 0, 100000, deployCode)
+	created := crypto.CreateAddress(alice, 1)
+	res, err := DoCallBundle(context.Background(), b, []BundleTx{
+		{Signed: transfer},
+		{Signed: create},
+		callTo(alice, counterAddr),
+		callTo(alice, suicideAddr),
+		callTo(alice, revertAddr),
+	}, latest, nil, 0, 0)
+	require.NoError(err)
+	require.Len(res.Results, 5)
+
+	hexBig := func(v *big.Int) **hexutil.Big {
+		h := (*hexutil.Big)(v)
+		return &h
+	}
+	hexUint := func(v uint64) *hexutil.Uint64 {
+		h := hexutil.Uint64(v)
+		return &h
+	}
+	hexBytes := func(v []byte) *hexutil.Bytes {
+		h := hexutil.Bytes(v)
+		return &h
+	}
+	storage := func(key, val common.Hash) *map[common.Hash]common.Hash {
+		return &map[common.Hash]common.Hash{key: val}
+	}
+	aliceBalance := big.NewInt(1e18)
+	spent := func(gas hexutil.Uint64, value int64) *big.Int {
+		aliceBalance = new(big.Int).Sub(aliceBalance, big.NewInt(int64(gas)+value))
+		return aliceBalance
+	}
+
+	// value transfer changes both balances and the sender's nonce
+	diff := res.Results[0].StateDiff
+	require.Equal(StateOverride{
+		alice: {Balance: hexBig(big.NewInt(1e18)), Nonce: hexUint(0)},
+		bob:   {Balance: hexBig(new(big.Int))},
+	}, diff.Pre)
+	require.Equal(StateOverride{
+		alice: {Balance: hexBig(spent(res.Results[0].GasUsed, 5)), Nonce: hexUint(1)},
+		bob:   {Balance: hexBig(big.NewInt(5))},
+	}, diff.Post)
+
+	// contract creation sets the code and the nonce of the new account
+	diff = res.Results[1].StateDiff
+	require.Equal(StateOverride{
+		alice:   {Balance: hexBig(aliceBalance), Nonce: hexUint(1)},
+		created: {Nonce: hexUint(0), Code: hexBytes(nil)},
+	}, diff.Pre)
+	require.Equal(StateOverride{
+		alice:   {Balance: hexBig(spent(res.Results[1].GasUsed, 0)), Nonce: hexUint(2)},
+		created: {Nonce: hexUint(1), Code: hexBytes([]byte{0})},
+	}, diff.Post)
+
+	// unsigned calls change the nonce and the storage, but don't pay for gas
+	diff = res.Results[2].StateDiff
+	require.Equal(StateOverride{
+		alice:       {Nonce: hexUint(2)},
+		counterAddr: {StateDiff: storage(common.Hash{}, common.Hash{})},
+	}, diff.Pre)
+	require.Equal(StateOverride{
+		alice:       {Nonce: hexUint(3)},
+		counterAddr: {StateDiff: storage(common.Hash{}, common.BigToHash(common.Big1))},
+	}, diff.Post)
+
+	// self-destructed account is deleted, its balance goes to the beneficiary
+	diff = res.Results[3].StateDiff
+	require.Equal(StateOverride{
+		alice:       {Balance: hexBig(aliceBalance), Nonce: hexUint(3)},
+		suicideAddr: {Balance: hexBig(big.NewInt(7)), Nonce: hexUint(1), Code: hexBytes(suicideCode)},
+	}, diff.Pre)
+	require.Equal(StateOverride{
+		alice:       {Balance: hexBig(new(big.Int).Add(aliceBalance, big.NewInt(7))), Nonce: hexUint(4)},
+		suicideAddr: {Balance: hexBig(new(big.Int)), Nonce: hexUint(0), Code: hexBytes(nil)},
+	}, diff.Post)
+
+	// reverted call changes only the nonce
+	diff = res.Results[4].StateDiff
+	require.Equal(StateOverride{alice: {Nonce: hexUint(4)}}, diff.Pre)
+	require.Equal(StateOverride{alice: {Nonce: hexUint(5)}}, diff.Post)
+}
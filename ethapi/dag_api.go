@@ -5,10 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
 )
 
 // PublicDAGChainAPI provides an API to access the directed acyclic graph chain.
@@ -77,3 +82,99 @@ func (s *PublicBlockChainAPI) GetEpochStats(ctx context.Context, requestedEpoch
 		"totalTxRewardWeight":   (*hexutil.Big)(new(big.Int)),
 	}, nil
 }
+
+// RPCExtraValue is a metadata value published by a validator in event extra data.
+type RPCExtraValue struct {
+	Validator hexutil.Uint64 `json:"validator"`
+	Event     hexutil.Bytes  `json:"event"`
+	Value     interface{}    `json:"value"`
+}
+
+// RPCExtraRecord is an aggregation of metadata records with the same type and key.
+type RPCExtraRecord struct {
+	Type   string          `json:"type"`
+	Key    string          `json:"key,omitempty"`
+	Values []RPCExtraValue `json:"values"`
+	Median *hexutil.Big    `json:"median,omitempty"`
+}
+
+// GetEpochExtraData returns the latest metadata records published by every validator in epoch events,
+// along with a median of numeric values. Records of unknown types are skipped.
+// * When epoch is -2 the records of latest epoch are returned.
+// * When epoch is -1 the records of latest sealed epoch are returned.
+func (s *PublicDAGChainAPI) GetEpochExtraData(ctx context.Context, epoch rpc.BlockNumber) ([]RPCExtraRecord, error) {
+	type recordID struct {
+		t   inter.ExtraRecordType
+		key string
+	}
+	type published struct {
+		seq   idx.Event
+		event hash.Event
+		value []byte
+	}
+	latest := make(map[recordID]map[idx.ValidatorID]published)
+	err := s.b.ForEachEpochEvent(ctx, epoch, func(e *inter.EventPayload) bool {
+		extra, err := inter.ParseExtra(e.Extra())
+		if extra == nil || err != nil {
+			return true
+		}
+		for _, r := range extra.Records {
+			if !r.Type.Known() {
+				continue
+			}
+			id := recordID{r.Type, string(r.Key)}
+			if latest[id] == nil {
+				latest[id] = make(map[idx.ValidatorID]published)
+			}
+			if prev, ok := latest[id][e.Creator()]; !ok || prev.seq < e.Seq() {
+				latest[id][e.Creator()] = published{e.Seq(), e.ID(), r.Value}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]RPCExtraRecord, 0, len(latest))
+	for id, byValidator := range latest {
+		rec := RPCExtraRecord{
+			Type:   id.t.String(),
+			Key:    id.key,
+			Values: make([]RPCExtraValue, 0, len(byValidator)),
+		}
+		numeric := id.t != inter.SoftwareVersionRecord
+		nums := make([]*big.Int, 0, len(byValidator))
+		for v, p := range byValidator {
+			val := RPCExtraValue{
+				Validator: hexutil.Uint64(v),
+				Event:     hexutil.Bytes(p.event.Bytes()),
+			}
+			if numeric {
+				n := new(big.Int).SetBytes(p.value)
+				nums = append(nums, n)
+				val.Value = (*hexutil.Big)(n)
+			} else {
+				val.Value = string(p.value)
+			}
+			rec.Values = append(rec.Values, val)
+		}
+		sort.Slice(rec.Values, func(i, j int) bool {
+			return rec.Values[i].Validator < rec.Values[j].Validator
+		})
+		if numeric {
+			sort.Slice(nums, func(i, j int) bool {
+				return nums[i].Cmp(nums[j]) < 0
+			})
+			rec.Median = (*hexutil.Big)(nums[len(nums)/2])
+		}
+		res = append(res, rec)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Type != res[j].Type {
+			return res[i].Type < res[j].Type
+		}
+		return res[i].Key < res[j].Key
+	})
+	return res, nil
+}
@@ -0,0 +1,253 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/zilionixx/go-zilionixx/evmcore"
+)
+
+var errTxNotInBlock = errors.New("transaction isn't found in its block")
+
+// StateDiff is a change of accounts caused by an execution.
+// Both sides are in the StateOverride format and contain only the changed fields and storage slots,
+// so Post may be passed as overrides to a following call.
+type StateDiff struct {
+	Pre  StateOverride `json:"pre"`
+	Post StateOverride `json:"post"`
+}
+
+// originalAccount is a set of account values before the first modification
+type originalAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// stateDiffRecorder is a vm.StateDB which remembers the original values of modified accounts and storage slots.
+type stateDiffRecorder struct {
+	vm.StateDB
+	original map[common.Address]*originalAccount
+}
+
+func newStateDiffRecorder(statedb vm.StateDB) *stateDiffRecorder {
+	return &stateDiffRecorder{
+		StateDB:  statedb,
+		original: make(map[common.Address]*originalAccount),
+	}
+}
+
+func (r *stateDiffRecorder) touch(addr common.Address) *originalAccount {
+	if acc, ok := r.original[addr]; ok {
+		return acc
+	}
+	acc := &originalAccount{
+		balance: new(big.Int).Set(r.StateDB.GetBalance(addr)),
+		nonce:   r.StateDB.GetNonce(addr),
+		code:    common.CopyBytes(r.StateDB.GetCode(addr)),
+		storage: make(map[common.Hash]common.Hash),
+	}
+	r.original[addr] = acc
+	return acc
+}
+
+func (r *stateDiffRecorder) CreateAccount(addr common.Address) {
+	r.touch(addr)
+	r.StateDB.CreateAccount(addr)
+}
+
+func (r *stateDiffRecorder) SubBalance(addr common.Address, amount *big.Int) {
+	r.touch(addr)
+	r.StateDB.SubBalance(addr, amount)
+}
+
+func (r *stateDiffRecorder) AddBalance(addr common.Address, amount *big.Int) {
+	r.touch(addr)
+	r.StateDB.AddBalance(addr, amount)
+}
+
+func (r *stateDiffRecorder) SetNonce(addr common.Address, nonce uint64) {
+	r.touch(addr)
+	r.StateDB.SetNonce(addr, nonce)
+}
+
+func (r *stateDiffRecorder) SetCode(addr common.Address, code []byte) {
+	r.touch(addr)
+	r.StateDB.SetCode(addr, code)
+}
+
+func (r *stateDiffRecorder) SetState(addr common.Address, key, value common.Hash) {
+	acc := r.touch(addr)
+	if _, ok := acc.storage[key]; !ok {
+		acc.storage[key] = r.StateDB.GetState(addr, key)
+	}
+	r.StateDB.SetState(addr, key, value)
+}
+
+func (r *stateDiffRecorder) Suicide(addr common.Address) bool {
+	r.touch(addr)
+	return r.StateDB.Suicide(addr)
+}
+
+// Diff compares the original values with the current ones.
+// Should be called after the state is finalised, so self-destructed accounts are deleted.
+func (r *stateDiffRecorder) Diff() StateDiff {
+	diff := StateDiff{
+		Pre:  StateOverride{},
+		Post: StateOverride{},
+	}
+	for addr, acc := range r.original {
+		var pre, post OverrideAccount
+		changed := false
+		if balance := r.StateDB.GetBalance(addr); balance.Cmp(acc.balance) != 0 {
+			preBalance, postBalance := (*hexutil.Big)(acc.balance), (*hexutil.Big)(new(big.Int).Set(balance))
+			pre.Balance, post.Balance = &preBalance, &postBalance
+			changed = true
+		}
+		if nonce := r.StateDB.GetNonce(addr); nonce != acc.nonce {
+			preNonce, postNonce := hexutil.Uint64(acc.nonce), hexutil.Uint64(nonce)
+			pre.Nonce, post.Nonce = &preNonce, &postNonce
+			changed = true
+		}
+		if code := r.StateDB.GetCode(addr); !bytes.Equal(code, acc.code) {
+			preCode, postCode := hexutil.Bytes(acc.code), hexutil.Bytes(common.CopyBytes(code))
+			pre.Code, post.Code = &preCode, &postCode
+			changed = true
+		}
+		preStorage, postStorage := make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)
+		for key, val := range acc.storage {
+			if cur := r.StateDB.GetState(addr, key); cur != val {
+				preStorage[key], postStorage[key] = val, cur
+			}
+		}
+		if len(preStorage) != 0 {
+			pre.StateDiff, post.StateDiff = &preStorage, &postStorage
+			changed = true
+		}
+		if changed {
+			diff.Pre[addr], diff.Post[addr] = pre, post
+		}
+	}
+	return diff
+}
+
+// StateDiffResult is a result of an execution along with the state changes it made
+type StateDiffResult struct {
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Error      string         `json:"error,omitempty"`
+	Revert     hexutil.Bytes  `json:"revert,omitempty"`
+	StateDiff
+}
+
+func newStateDiffResult(result *evmcore.ExecutionResult, diff StateDiff) *StateDiffResult {
+	res := &StateDiffResult{
+		GasUsed:    hexutil.Uint64(result.UsedGas),
+		ReturnData: result.Return(),
+		StateDiff:  diff,
+	}
+	if result.Err != nil {
+		res.Error = result.Err.Error()
+		res.Revert = result.Revert()
+	}
+	return res
+}
+
+// DoCallStateDiff executes the call in the same way as DoCall, and returns the state changes made by the call.
+func DoCallStateDiff(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration, globalGasCap uint64) (*StateDiffResult, error) {
+	var diff StateDiff
+	result, err := doCall(ctx, b, args, blockNrOrHash, overrides, vmCfg, timeout, globalGasCap, &diff)
+	if err != nil {
+		return nil, err
+	}
+	return newStateDiffResult(result, diff), nil
+}
+
+// replayMessage converts a mined transaction into a message.
+// Internal transactions have no signature and are sent from the zero address.
+func replayMessage(tx *types.Transaction, signer types.Signer, sponsorship bool) (evmcore.Message, error) {
+	if _, r, s := tx.RawSignatureValues(); r.Sign() == 0 && s.Sign() == 0 {
+		return types.NewMessage(common.Address{}, tx.To(), tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data(), tx.AccessList(), false), nil
+	}
+	return evmcore.TxAsMessage(tx, signer, sponsorship)
+}
+
+// ReplayTransactionStateDiff re-executes a mined transaction on top of the parent block's state
+// and the preceding transactions of its block, and returns the state changes made by the transaction.
+func ReplayTransactionStateDiff(ctx context.Context, b Backend, hash common.Hash) (*StateDiffResult, error) {
+	tx, blockNumber, index, err := b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		return nil, err
+	}
+	block, err := b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	if blockNumber == 0 || index >= uint64(len(block.Transactions)) || block.Transactions[index].Hash() != hash {
+		return nil, errTxNotInBlock
+	}
+	statedb, _, err := b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNumber-1)))
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+
+	signer := types.MakeSigner(b.ChainConfig(), block.Number)
+	// txs are decoded according to the rules which were applied to the block
+	sponsorship := b.BlockUpgrades(blockNumber).Sponsorship
+	gp := new(evmcore.GasPool).AddGas(math.MaxUint64)
+	for i, btx := range block.Transactions[:index+1] {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, err := replayMessage(btx, signer, sponsorship)
+		if err != nil {
+			return nil, err
+		}
+		statedb.Prepare(btx.Hash(), block.Hash, i)
+		evm, vmError, err := b.GetEVM(ctx, msg, statedb, &block.EvmHeader, nil)
+		if err != nil {
+			return nil, err
+		}
+		var recorder *stateDiffRecorder
+		if uint64(i) == index {
+			recorder = newStateDiffRecorder(evm.StateDB)
+			evm.StateDB = recorder
+		}
+		result, err := evmcore.ApplyMessage(evm, msg, gp)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay transaction %s: %w", btx.Hash().Hex(), err)
+		}
+		statedb.Finalise(true)
+		if recorder != nil {
+			return newStateDiffResult(result, recorder.Diff()), nil
+		}
+	}
+	return nil, errTxNotInBlock
+}
+
+// CallStateDiff executes the call on top of the given block's state and returns the state changes
+// it made, in the StateOverride format.
+func (api *PublicDebugAPI) CallStateDiff(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (*StateDiffResult, error) {
+	return DoCallStateDiff(ctx, api.b, args, blockNrOrHash, overrides, vm.Config{}, 5*time.Second, api.b.RPCGasCap())
+}
+
+// ReplayTransactionStateDiff replays a mined transaction and returns the state changes
+// it made, in the StateOverride format.
+func (api *PublicDebugAPI) ReplayTransactionStateDiff(ctx context.Context, hash common.Hash) (*StateDiffResult, error) {
+	return ReplayTransactionStateDiff(ctx, api.b, hash)
+}
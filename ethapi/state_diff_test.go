@@ -0,0 +1,129 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiffRecorder(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		alice = common.Address{1}
+		bob   = common.Address{2}
+		key1  = common.Hash{1}
+		key2  = common.Hash{2}
+	)
+	b := newTestBackend(t, func(statedb *state.StateDB) {
+		statedb.SetBalance(alice, big.NewInt(10))
+		statedb.SetState(alice, key1, common.Hash{0xa})
+		statedb.SetState(alice, key2, common.Hash{0xb})
+	})
+	statedb, _, err := b.StateAndHeaderByNumberOrHash(context.Background(), rpc.BlockNumberOrHashWithNumber(0))
+	require.NoError(err)
+
+	r := newStateDiffRecorder(statedb)
+	// the original value is remembered on the first write only
+	r.SetState(alice, key1, common.Hash{0xc})
+	r.SetState(alice, key1, common.Hash{0xd})
+	// restored values and no-op changes aren't reported
+	r.SetState(alice, key2, common.Hash{0xe})
+	r.SetState(alice, key2, common.Hash{0xb})
+	r.AddBalance(bob, new(big.Int))
+	r.SetNonce(bob, 0)
+	statedb.Finalise(true)
+
+	diff := r.Diff()
+	require.Equal(StateOverride{alice: {StateDiff: &map[common.Hash]common.Hash{key1: {0xa}}}}, diff.Pre)
+	require.Equal(StateOverride{alice: {StateDiff: &map[common.Hash]common.Hash{key1: {0xd}}}}, diff.Post)
+}
+
+func TestDoCallStateDiffRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	_, alice := newTestKey(t)
+	b := newTestBackend(t, genesisWith(map[common.Address]int64{alice: 1e18}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	args := CallArgs{From: &alice, To: &counterAddr}
+
+	res, err := DoCallStateDiff(context.Background(), b, args, latest, nil, vm.Config{}, 0, 0)
+	require.NoError(err)
+	require.Empty(res.Error)
+	require.Equal(common.BigToHash(common.Big1).Bytes(), []byte(res.ReturnData))
+	require.Equal(common.BigToHash(common.Big1), (*res.Post[counterAddr].StateDiff)[common.Hash{}])
+
+	// Post passes through JSON as overrides of a following call
+	raw, err := json.Marshal(res.Post)
+	require.NoError(err)
+	var overrides StateOverride
+	require.NoError(json.Unmarshal(raw, &overrides))
+	require.Equal(res.Post, overrides)
+
+	result, err := DoCall(context.Background(), b, args, latest, &overrides, vm.Config{}, 0, 0)
+	require.NoError(err)
+	require.NoError(result.Err)
+	require.Equal(common.BigToHash(big.NewInt(2)).Bytes(), result.Return())
+
+	// the following diff starts where the previous one ended
+	next, err := DoCallStateDiff(context.Background(), b, args, latest, &overrides, vm.Config{}, 0, 0)
+	require.NoError(err)
+	require.Equal(res.Post, next.Pre)
+}
+
+func TestReplayTransactionStateDiff(t *testing.T) {
+	require := require.New(t)
+
+	aliceKey, alice := newTestKey(t)
+	_, bob := newTestKey(t)
+	b := newTestBackend(t, genesisWith(map[common.Address]int64{alice: 1e18}))
+
+	// internal txs are unsigned and sent from the zero address
+	internal := types.NewTransaction(0, counterAddr, new(big.Int), 100000, new(big.Int), nil)
+	transfer := signTestTx(t, b, aliceKey, 0, &bob, 5, 21000, nil)
+	call := signTestTx(t, b, aliceKey, 1, &counterAddr, 0, 100000, nil)
+	receipts := b.addBlock(t, types.Transactions{internal}, types.Transactions{transfer, call})
+	require.Len(receipts, 3)
+
+	slot := func(diff StateOverride) common.Hash {
+		acc, ok := diff[counterAddr]
+		require.True(ok)
+		require.NotNil(acc.StateDiff)
+		return (*acc.StateDiff)[common.Hash{}]
+	}
+
+	for i, tx := range []*types.Transaction{internal, transfer, call} {
+		res, err := ReplayTransactionStateDiff(context.Background(), b, tx.Hash())
+		require.NoError(err, i)
+		require.Empty(res.Error, i)
+		require.Equal(receipts[i].GasUsed, uint64(res.GasUsed), i)
+
+		switch tx {
+		case internal:
+			require.Equal(common.Hash{}, slot(res.Pre))
+			require.Equal(common.BigToHash(common.Big1), slot(res.Post))
+		case transfer:
+			require.Equal(uint64(0), uint64(*res.Pre[alice].Nonce))
+			require.Equal(uint64(1), uint64(*res.Post[alice].Nonce))
+			require.Equal(big.NewInt(5), (*res.Post[bob].Balance).ToInt())
+		case call:
+			// the preceding internal and regular txs are applied before the replayed one
+			require.Equal(uint64(1), uint64(*res.Pre[alice].Nonce))
+			require.Equal(common.BigToHash(common.Big1), slot(res.Pre))
+			require.Equal(common.BigToHash(big.NewInt(2)), slot(res.Post))
+		}
+	}
+
+	// unknown transaction
+	res, err := ReplayTransactionStateDiff(context.Background(), b, common.Hash{1})
+	require.NoError(err)
+	require.Nil(res)
+}
@@ -0,0 +1,93 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/zilionixx/go-zilionixx/utils/rate"
+)
+
+var (
+	errSenderRateLimited = errors.New("transaction submission rate limit exceeded for sender")
+	errOriginRateLimited = errors.New("transaction submission rate limit exceeded for client")
+
+	senderRateLimitedMeter = metrics.NewRegisteredMeter("rpc/txlimit/sender/rejected", nil)
+	originRateLimitedMeter = metrics.NewRegisteredMeter("rpc/txlimit/origin/rejected", nil)
+)
+
+// TxSubmitLimitsConfig is a config of transaction submission rate limits.
+// Rates are in transactions per second, zero rate disables the limit.
+type TxSubmitLimitsConfig struct {
+	SenderRate  float64
+	SenderBurst int
+	OriginRate  float64
+	OriginBurst int
+	// MaxKeys is a maximum number of tracked senders and client origins
+	MaxKeys int
+}
+
+// DefaultTxSubmitLimitsConfig returns config with disabled limits
+func DefaultTxSubmitLimitsConfig() TxSubmitLimitsConfig {
+	return TxSubmitLimitsConfig{
+		SenderBurst: 16,
+		OriginBurst: 64,
+		MaxKeys:     10000,
+	}
+}
+
+// TxSubmitLimiter enforces per-sender and per-client limits of transaction submission
+type TxSubmitLimiter struct {
+	sender *rate.Limiter
+	origin *rate.Limiter
+}
+
+// NewTxSubmitLimiter constructs TxSubmitLimiter
+func NewTxSubmitLimiter(cfg TxSubmitLimitsConfig) *TxSubmitLimiter {
+	return &TxSubmitLimiter{
+		sender: rate.NewLimiter(cfg.SenderRate, cfg.SenderBurst, cfg.MaxKeys),
+		origin: rate.NewLimiter(cfg.OriginRate, cfg.OriginBurst, cfg.MaxKeys),
+	}
+}
+
+// Allow takes a token from buckets of the sender and of the RPC client which submitted the transaction.
+// A token of the client is given back if the transaction is rejected by the sender limit.
+func (l *TxSubmitLimiter) Allow(ctx context.Context, from common.Address) error {
+	origin, limited := rpcClientOrigin(ctx)
+	if limited && !l.origin.Allow(origin) {
+		originRateLimitedMeter.Mark(1)
+		return errOriginRateLimited
+	}
+	if !l.sender.Allow(string(from.Bytes())) {
+		if limited {
+			l.origin.Refund(origin)
+		}
+		senderRateLimitedMeter.Mark(1)
+		return errSenderRateLimited
+	}
+	return nil
+}
+
+// rpcClientOrigin returns the key of the RPC client, and false if the client isn't limited.
+// HTTP clients are keyed by IP address, which is set into the context only by the HTTP transport.
+// The RPC server doesn't tell WebSocket, IPC and in-process connections apart, so each of them
+// is keyed by the connection, and doesn't share the limit with other clients.
+// Calls which aren't made over an RPC connection aren't limited.
+func rpcClientOrigin(ctx context.Context) (string, bool) {
+	if remote, ok := ctx.Value("remote").(string); ok && remote != "" {
+		host, _, err := net.SplitHostPort(remote)
+		if err != nil {
+			return remote, true
+		}
+		return host, true
+	}
+	if client, ok := rpc.ClientFromContext(ctx); ok {
+		return fmt.Sprintf("conn-%p", client), true
+	}
+	return "", false
+}
@@ -0,0 +1,90 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// originService reports the origin of RPC calls
+type originService struct{}
+
+func (originService) Origin(ctx context.Context) string {
+	origin, _ := rpcClientOrigin(ctx)
+	return origin
+}
+
+func TestTxSubmitLimiterOrigins(t *testing.T) {
+	require := require.New(t)
+
+	l := NewTxSubmitLimiter(TxSubmitLimitsConfig{
+		OriginRate:  0.001,
+		OriginBurst: 1,
+		MaxKeys:     100,
+	})
+	http1 := context.WithValue(context.Background(), "remote", "10.0.0.1:1000")
+	http1Port := context.WithValue(context.Background(), "remote", "10.0.0.1:2000")
+	http2 := context.WithValue(context.Background(), "remote", "10.0.0.2:1000")
+
+	origin, limited := rpcClientOrigin(http1)
+	require.Equal("10.0.0.1", origin)
+	require.True(limited)
+
+	require.NoError(l.Allow(http1, common.Address{1}))
+	require.Equal(errOriginRateLimited, l.Allow(http1Port, common.Address{2}))
+	require.NoError(l.Allow(http2, common.Address{3}))
+
+	// calls made not over RPC aren't limited
+	_, limited = rpcClientOrigin(context.Background())
+	require.False(limited)
+	require.NoError(l.Allow(context.Background(), common.Address{4}))
+	require.NoError(l.Allow(context.Background(), common.Address{5}))
+}
+
+func TestTxSubmitLimiterConnections(t *testing.T) {
+	require := require.New(t)
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	require.NoError(srv.RegisterName("test", originService{}))
+
+	origins := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		client := rpc.DialInProc(srv)
+		var first, second string
+		require.NoError(client.Call(&first, "test_origin"))
+		require.NoError(client.Call(&second, "test_origin"))
+		client.Close()
+
+		// calls of a connection share the key
+		require.NotEmpty(first)
+		require.Equal(first, second)
+		origins = append(origins, first)
+	}
+	// connections don't share the key
+	require.NotEqual(origins[0], origins[1])
+}
+
+func TestTxSubmitLimiterRefund(t *testing.T) {
+	require := require.New(t)
+
+	l := NewTxSubmitLimiter(TxSubmitLimitsConfig{
+		SenderRate:  0.001,
+		SenderBurst: 1,
+		OriginRate:  0.001,
+		OriginBurst: 2,
+		MaxKeys:     100,
+	})
+	http1 := context.WithValue(context.Background(), "remote", "10.0.0.1:1000")
+
+	require.NoError(l.Allow(http1, common.Address{1}))
+	// rejections by the sender limit don't consume tokens of the client
+	for i := 0; i < 3; i++ {
+		require.Equal(errSenderRateLimited, l.Allow(http1, common.Address{1}))
+	}
+	require.NoError(l.Allow(http1, common.Address{2}))
+	require.Equal(errOriginRateLimited, l.Allow(http1, common.Address{3}))
+}
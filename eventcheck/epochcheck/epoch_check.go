@@ -17,6 +17,7 @@ var (
 	ErrWrongGasUsed      = errors.New("event has incorrect gas power")
 	ErrUnderpriced       = errors.New("event transaction underpriced")
 	ErrTooBigExtra       = errors.New("event extra data is too large")
+	ErrMalformedExtra    = errors.New("malformed event extra data")
 	ErrUnsupportedTxType = errors.New("unsupported tx type")
 	ErrNotRelevant       = base.ErrNotRelevant
 	ErrAuth              = base.ErrAuth
@@ -98,6 +99,12 @@ func (v *Checker) Validate(e inter.EventPayloadI) error {
 	if uint32(len(e.Extra())) > rules.Dag.MaxExtraData {
 		return ErrTooBigExtra
 	}
+	// any extra data is accepted before the upgrade
+	if rules.Upgrades.TypedExtra {
+		if _, err := inter.ParseExtra(e.Extra()); err != nil {
+			return ErrMalformedExtra
+		}
+	}
 	if err := v.checkGas(e, rules); err != nil {
 		return err
 	}
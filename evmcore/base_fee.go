@@ -0,0 +1,53 @@
+package evmcore
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+const (
+	// MaxBaseFeeMultiplier caps the base fee as a multiple of MinGasPrice
+	MaxBaseFeeMultiplier = 10
+	// minBaseFeeWindow smooths the base fee of blocks which follow each other closely
+	minBaseFeeWindow = inter.Timestamp(time.Second)
+)
+
+// CalcBaseFee returns the base fee of a block after the London upgrade, or nil before it.
+// The base fee equals to MinGasPrice while the parent block consumes no more than
+// a half of the gas power allocated during the time between blocks (the target),
+// and grows proportionally to the usage above the target, up to MaxBaseFeeMultiplier.
+func CalcBaseFee(rules zilionixx.Rules, parent *EvmHeader, blockTime inter.Timestamp) *big.Int {
+	if !rules.Upgrades.London {
+		return nil
+	}
+	minPrice := rules.Economy.MinGasPrice
+	if parent == nil || parent.GasUsed == 0 {
+		return new(big.Int).Set(minPrice)
+	}
+
+	window := minBaseFeeWindow
+	if blockTime > parent.Time && blockTime-parent.Time > window {
+		window = blockTime - parent.Time
+	}
+	// target = AllocPerSec * window / 2
+	target := new(big.Int).SetUint64(rules.Economy.LongGasPower.AllocPerSec)
+	target.Mul(target, new(big.Int).SetUint64(uint64(window)))
+	target.Div(target, big.NewInt(2*int64(time.Second)))
+	if target.Sign() == 0 {
+		return new(big.Int).Mul(minPrice, big.NewInt(MaxBaseFeeMultiplier))
+	}
+
+	used := new(big.Int).SetUint64(parent.GasUsed)
+	if used.Cmp(target) <= 0 {
+		return new(big.Int).Set(minPrice)
+	}
+	baseFee := new(big.Int).Mul(minPrice, used)
+	baseFee.Div(baseFee, target)
+	if maxFee := new(big.Int).Mul(minPrice, big.NewInt(MaxBaseFeeMultiplier)); baseFee.Cmp(maxFee) > 0 {
+		return maxFee
+	}
+	return baseFee
+}
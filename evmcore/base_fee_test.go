@@ -0,0 +1,42 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+func TestCalcBaseFee(t *testing.T) {
+	require := require.New(t)
+
+	rules := zilionixx.FakeNetRules()
+	parent := &EvmHeader{Time: inter.Timestamp(time.Hour)}
+	require.Nil(CalcBaseFee(rules, parent, parent.Time+inter.Timestamp(time.Second)))
+
+	rules.Upgrades.London = true
+	minPrice := rules.Economy.MinGasPrice
+	target := rules.Economy.LongGasPower.AllocPerSec / 2
+
+	for _, tt := range []struct {
+		gasUsed  uint64
+		interval time.Duration
+		expected *big.Int
+	}{
+		{0, time.Second, minPrice},
+		{target, time.Second, minPrice},
+		{target * 2, time.Second, new(big.Int).Mul(minPrice, big.NewInt(2))},
+		{target * 2, time.Millisecond, new(big.Int).Mul(minPrice, big.NewInt(2))},
+		{target * 2, 2 * time.Second, minPrice},
+		{target * 100, time.Second, new(big.Int).Mul(minPrice, big.NewInt(MaxBaseFeeMultiplier))},
+	} {
+		parent.GasUsed = tt.gasUsed
+		got := CalcBaseFee(rules, parent, parent.Time+inter.Timestamp(tt.interval))
+		require.Equal(tt.expected.String(), got.String(), tt)
+	}
+	require.Equal(minPrice.String(), CalcBaseFee(rules, nil, 0).String())
+}
@@ -41,6 +41,8 @@ type (
 
 		GasLimit uint64
 		GasUsed  uint64
+
+		BaseFee *big.Int // nil before the London upgrade
 	}
 
 	EvmBlock struct {
@@ -24,6 +24,9 @@ import (
 // NewTxsNotify is posted when a batch of transactions enter the transaction pool.
 type NewTxsNotify struct{ Txs []*types.Transaction }
 
+// DroppedTxsNotify is posted when transactions are removed from the transaction pool without being included.
+type DroppedTxsNotify struct{ Drops []*DroppedTx }
+
 // PendingLogsNotify is posted pre mining and notifies of pending logs.
 type PendingLogsNotify struct {
 	Logs []*types.Log
@@ -0,0 +1,129 @@
+package evmcore
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+// SponsoredMessage is a message which gas is paid by the sponsor instead of the sender
+type SponsoredMessage interface {
+	Message
+	Sponsor() common.Address
+}
+
+type sponsoredMessage struct {
+	types.Message
+	sponsor common.Address
+}
+
+func (m sponsoredMessage) Sponsor() common.Address {
+	return m.sponsor
+}
+
+// TxAsMessage returns the transaction as a message.
+// If sponsorship is enabled, transactions with a sponsorship entry are converted into a SponsoredMessage,
+// otherwise the entry is treated as an ordinary access list entry.
+func TxAsMessage(tx *types.Transaction, signer types.Signer, sponsorship bool) (Message, error) {
+	msg, err := tx.AsMessage(signer)
+	if err != nil {
+		return nil, err
+	}
+	if !sponsorship || !inter.IsSponsored(tx) {
+		return msg, nil
+	}
+	sponsor, err := inter.TxSponsor(tx, msg.From())
+	if err != nil {
+		return nil, err
+	}
+	return sponsoredMessage{msg, sponsor}, nil
+}
+
+// gasCost returns GP * GL of the transaction, which is paid by the sponsor of a sponsored transaction
+func gasCost(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+}
+
+// senderCost returns the maximum amount which may be spent by the sender of the transaction
+func senderCost(tx *types.Transaction) *big.Int {
+	if inter.IsSponsored(tx) {
+		return new(big.Int).Set(tx.Value())
+	}
+	return tx.Cost()
+}
+
+// overlapping returns the pool transaction of the sender with the same nonce, if any.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) overlapping(from common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[from]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := pool.queue[from]; list != nil {
+		return list.txs.Get(nonce)
+	}
+	return nil
+}
+
+// trackSponsor records the gas cost of an added sponsored transaction as committed by its sponsor.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) trackSponsor(tx *types.Transaction, from common.Address) {
+	if !inter.IsSponsored(tx) {
+		return
+	}
+	sponsor, err := inter.TxSponsor(tx, from) // already validated
+	if err != nil {
+		return
+	}
+	pool.all.SetSponsor(tx.Hash(), sponsor)
+}
+
+// sponsorBalance returns the balance of the sponsor which is left for the sponsored transactions,
+// i.e. the balance minus the costs of the sponsor's own pending transactions. It may be negative.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) sponsorBalance(sponsor common.Address) *big.Int {
+	balance := new(big.Int).Set(pool.currentState.GetBalance(sponsor))
+	if list := pool.pending[sponsor]; list != nil {
+		for _, tx := range list.txs.items {
+			balance.Sub(balance, senderCost(tx))
+		}
+	}
+	return balance
+}
+
+// dropUnpayableSponsored drops sponsored transactions whose sponsor can't pay for all of them anymore,
+// starting from the cheapest and the latest ones.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) dropUnpayableSponsored() {
+	for sponsor, txs := range pool.all.Sponsors() {
+		balance := pool.sponsorBalance(sponsor)
+		if pool.all.SponsorCost(sponsor).Cmp(balance) <= 0 {
+			continue
+		}
+		sort.Slice(txs, func(i, j int) bool {
+			if cmp := txs[i].GasPrice().Cmp(txs[j].GasPrice()); cmp != 0 {
+				return cmp < 0
+			}
+			ti, _ := pool.all.Time(txs[i].Hash())
+			tj, _ := pool.all.Time(txs[j].Hash())
+			return ti.After(tj)
+		})
+		for _, tx := range txs {
+			if pool.all.SponsorCost(sponsor).Cmp(balance) <= 0 {
+				break
+			}
+			log.Trace("Removed unpayable sponsored transaction", "hash", tx.Hash(), "sponsor", sponsor)
+			pool.removeTx(tx.Hash(), true, DropUnexecutable)
+		}
+	}
+}
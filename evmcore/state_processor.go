@@ -25,6 +25,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
 )
 
 // StateProcessor is a basic Processor, which takes care of transitioning
@@ -32,15 +35,17 @@ import (
 //
 // StateProcessor implements Processor.
 type StateProcessor struct {
-	config *params.ChainConfig // Chain configuration options
-	bc     DummyChain          // Canonical block chain
+	config   *params.ChainConfig // Chain configuration options
+	bc       DummyChain          // Canonical block chain
+	upgrades zilionixx.Upgrades  // Network upgrades
 }
 
 // NewStateProcessor initialises a new StateProcessor.
-func NewStateProcessor(config *params.ChainConfig, bc DummyChain) *StateProcessor {
+func NewStateProcessor(config *params.ChainConfig, bc DummyChain, upgrades zilionixx.Upgrades) *StateProcessor {
 	return &StateProcessor{
-		config: config,
-		bc:     bc,
+		config:   config,
+		bc:       bc,
+		upgrades: upgrades,
 	}
 }
 
@@ -67,9 +72,15 @@ func (p *StateProcessor) Process(
 	)
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions {
-		var msg types.Message
+		var msg Message
 		if !internal {
-			msg, err = tx.AsMessage(types.MakeSigner(p.config, header.Number))
+			msg, err = TxAsMessage(tx, types.MakeSigner(p.config, header.Number), p.upgrades.Sponsorship)
+			// txs with an invalid sponsorship are skipped
+			if err == inter.ErrInvalidSponsor {
+				skipped = append(skipped, uint32(i))
+				err = nil
+				continue
+			}
 			if err != nil {
 				return nil, nil, nil, err
 			}
@@ -94,7 +105,7 @@ func (p *StateProcessor) Process(
 }
 
 func applyTransaction(
-	msg types.Message,
+	msg Message,
 	config *params.ChainConfig,
 	gp *GasPool,
 	statedb *state.StateDB,
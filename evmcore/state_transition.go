@@ -180,10 +180,19 @@ func (st *StateTransition) to() common.Address {
 	return *st.msg.To()
 }
 
+// payer returns the account which pays for gas, i.e. the sponsor of a sponsored message or the sender
+func (st *StateTransition) payer() common.Address {
+	if msg, ok := st.msg.(SponsoredMessage); ok {
+		return msg.Sponsor()
+	}
+	return st.msg.From()
+}
+
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if have, want := st.state.GetBalance(st.msg.From()), mgval; have.Cmp(want) < 0 {
-		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From().Hex(), have, want)
+	payer := st.payer()
+	if have, want := st.state.GetBalance(payer), mgval; have.Cmp(want) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, payer.Hex(), have, want)
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
 		return err
@@ -191,7 +200,7 @@ func (st *StateTransition) buyGas() error {
 	st.gas += st.msg.Gas()
 
 	st.initialGas = st.msg.Gas()
-	st.state.SubBalance(st.msg.From(), mgval)
+	st.state.SubBalance(payer, mgval)
 	return nil
 }
 
@@ -299,7 +308,7 @@ func (st *StateTransition) refundGas() {
 
 	// Return wei for remaining gas, exchanged at the original rate.
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(st.msg.From(), remaining)
+	st.state.AddBalance(st.payer(), remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
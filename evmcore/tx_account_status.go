@@ -0,0 +1,77 @@
+package evmcore
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NonceGap is a range of missing nonces [From, To], which blocks the following queued transactions
+type NonceGap struct {
+	From uint64
+	To   uint64
+}
+
+// TxReplacement is a pending transaction along with the minimum gas price of its replacement
+type TxReplacement struct {
+	Tx       *types.Transaction
+	MinPrice *big.Int
+}
+
+// AccountStatus is a state of an account's transactions in the pool
+type AccountStatus struct {
+	StateNonce   uint64
+	PendingNonce uint64
+	Pending      []TxReplacement
+	Queued       []uint64
+	Gaps         []NonceGap
+}
+
+// AccountStatus returns nonces of the account's pending and queued transactions,
+// the nonce gaps which prevent queued transactions from being executed,
+// and the minimum gas prices to replace pending transactions.
+func (pool *TxPool) AccountStatus(addr common.Address) AccountStatus {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	status := AccountStatus{
+		StateNonce:   pool.currentState.GetNonce(addr),
+		PendingNonce: pool.pendingNonces.get(addr),
+	}
+	if list := pool.pending[addr]; list != nil {
+		for _, tx := range list.Flatten() {
+			status.Pending = append(status.Pending, TxReplacement{
+				Tx:       tx,
+				MinPrice: replacementPrice(tx.GasPrice(), pool.config.PriceBump),
+			})
+		}
+	}
+	if list := pool.queue[addr]; list != nil {
+		next := status.PendingNonce
+		for _, tx := range list.Flatten() {
+			nonce := tx.Nonce()
+			status.Queued = append(status.Queued, nonce)
+			if nonce > next {
+				status.Gaps = append(status.Gaps, NonceGap{From: next, To: nonce - 1})
+			}
+			if nonce >= next {
+				next = nonce + 1
+			}
+		}
+	}
+	return status
+}
+
+// replacementPrice returns the minimum gas price which is accepted by txList.Add
+// to replace a transaction with the given gas price
+func replacementPrice(price *big.Int, priceBump uint64) *big.Int {
+	// threshold = oldGP * (100 + priceBump) / 100
+	threshold := new(big.Int).Mul(price, big.NewInt(100+int64(priceBump)))
+	threshold.Div(threshold, big.NewInt(100))
+	// the new price has to be strictly higher than the old one
+	if threshold.Cmp(price) <= 0 {
+		threshold.Add(price, common.Big1)
+	}
+	return threshold
+}
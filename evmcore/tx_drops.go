@@ -0,0 +1,147 @@
+package evmcore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// TxDropReason explains why a transaction was removed from the pool without being included
+type TxDropReason uint8
+
+const (
+	DropUnknown TxDropReason = iota
+	// DropUnderpriced is for transactions evicted by better priced ones, or below the minimum gas price
+	DropUnderpriced
+	// DropReplaced is for transactions replaced by another transaction with the same nonce
+	DropReplaced
+	// DropExpired is for transactions which were queued for longer than Lifetime
+	DropExpired
+	// DropPendingOverflow is for transactions exceeding GlobalSlots of the pending pool
+	DropPendingOverflow
+	// DropQueueOverflow is for transactions exceeding AccountQueue or GlobalQueue of the queue
+	DropQueueOverflow
+	// DropNonceTooLow is for transactions whose nonce was used by another transaction in the chain
+	DropNonceTooLow
+	// DropUnexecutable is for transactions with insufficient funds or gas above the block limit
+	DropUnexecutable
+	// DropPrivateExpired is for private transactions which weren't included within PrivateLifetime blocks
+	DropPrivateExpired
+)
+
+// droppedTxMeters counts dropped transactions per reason
+var droppedTxMeters = func() map[TxDropReason]metrics.Meter {
+	meters := make(map[TxDropReason]metrics.Meter)
+	for r := DropUnknown; r <= DropPrivateExpired; r++ {
+		meters[r] = metrics.NewRegisteredMeter("txpool/dropped/"+strings.ReplaceAll(r.String(), " ", ""), nil)
+	}
+	return meters
+}()
+
+func (r TxDropReason) String() string {
+	switch r {
+	case DropUnderpriced:
+		return "underpriced"
+	case DropReplaced:
+		return "replaced"
+	case DropExpired:
+		return "expired"
+	case DropPendingOverflow:
+		return "pending overflow"
+	case DropQueueOverflow:
+		return "queue overflow"
+	case DropNonceTooLow:
+		return "nonce too low"
+	case DropUnexecutable:
+		return "unexecutable"
+	case DropPrivateExpired:
+		return "private expired"
+	}
+	return "unknown"
+}
+
+// DroppedTx is a record of a transaction removed from the pool
+type DroppedTx struct {
+	Tx     *types.Transaction
+	Reason TxDropReason
+	Time   time.Time
+}
+
+// recordDrop remembers the reason of the transaction removal and schedules the notification.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) recordDrop(tx *types.Transaction, reason TxDropReason) {
+	drop := &DroppedTx{
+		Tx:     tx,
+		Reason: reason,
+		Time:   time.Now(),
+	}
+	pool.drops.Add(tx.Hash(), drop)
+	pool.newDrops = append(pool.newDrops, drop)
+	droppedTxMeters[reason].Mark(1)
+}
+
+// recordStale records a drop of a transaction with a nonce below the account nonce,
+// unless the transaction itself was included into the blocks applied by the current reset.
+// Nothing is recorded if the applied blocks aren't known, e.g. after a deep reorg.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) recordStale(tx *types.Transaction) {
+	if pool.includedTxs == nil {
+		return
+	}
+	if _, ok := pool.includedTxs[tx.Hash()]; !ok {
+		pool.recordDrop(tx, DropNonceTooLow)
+	}
+}
+
+// txHashSet returns the set of the transaction hashes
+func txHashSet(txs types.Transactions) map[common.Hash]struct{} {
+	set := make(map[common.Hash]struct{}, len(txs))
+	for _, tx := range txs {
+		set[tx.Hash()] = struct{}{}
+	}
+	return set
+}
+
+// takeDrops returns the drops recorded since the last call.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) takeDrops() []*DroppedTx {
+	drops := pool.newDrops
+	pool.newDrops = nil
+	return drops
+}
+
+// sendDrops notifies subscribers about dropped transactions, must be called without the pool lock
+func (pool *TxPool) sendDrops(drops []*DroppedTx) {
+	if len(drops) != 0 {
+		pool.dropFeed.Send(DroppedTxsNotify{drops})
+	}
+}
+
+// Dropped returns the record of a recently dropped transaction
+func (pool *TxPool) Dropped(hash common.Hash) (*DroppedTx, bool) {
+	drop, ok := pool.drops.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return drop.(*DroppedTx), true
+}
+
+// TxStatus returns the status of a transaction, along with the drop record if it was dropped recently
+func (pool *TxPool) TxStatus(hash common.Hash) (TxStatus, *DroppedTx) {
+	if status := pool.Status([]common.Hash{hash})[0]; status != TxStatusUnknown {
+		return status, nil
+	}
+	if pool.chain.TxExists(hash) {
+		return TxStatusIncluded, nil
+	}
+	if drop, ok := pool.Dropped(hash); ok {
+		return TxStatusDropped, drop
+	}
+	return TxStatusUnknown, nil
+}
@@ -26,15 +26,21 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	notify "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
+	lru "github.com/hashicorp/golang-lru"
 
+	"github.com/zilionixx/go-zilionixx/inter"
 	"github.com/zilionixx/go-zilionixx/utils/gsignercache"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+	"github.com/zilionixx/go-zilionixx/zilionixx/genesis/sfc"
 )
 
 const (
@@ -86,6 +92,14 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrSponsorshipNotActive is returned if a sponsored transaction is submitted
+	// before the Sponsorship upgrade.
+	ErrSponsorshipNotActive = errors.New("sponsored transactions aren't supported yet")
+
+	// ErrInsufficientSponsorFunds is returned if the sponsor of a transaction
+	// cannot cover gas * price.
+	ErrInsufficientSponsorFunds = errors.New("insufficient sponsor funds for gas * price")
 )
 
 var (
@@ -127,6 +141,7 @@ const (
 	TxStatusQueued
 	TxStatusPending
 	TxStatusIncluded
+	TxStatusDropped
 )
 
 // stateReader provides the state of blockchain and current gas limit to do
@@ -141,6 +156,7 @@ type stateReader interface {
 	SubscribeNewBlock(ch chan<- ChainHeadNotify) notify.Subscription
 	TxExists(common.Hash) bool
 	Config() *params.ChainConfig
+	Upgrades() zilionixx.Upgrades
 }
 
 // TxPoolConfig are the configuration parameters of the transaction pool.
@@ -150,6 +166,9 @@ type TxPoolConfig struct {
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
+	Snapshot   string        // Snapshot of all transactions to survive node restarts, disabled if empty
+	Resnapshot time.Duration // Time interval to regenerate the transactions snapshot
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -158,7 +177,28 @@ type TxPoolConfig struct {
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
+	PriorityMethods []PriorityMethod // Contract methods, calls of which are in the priority lane
+	PrioritySlots   uint64           // Number of executable transaction slots reserved for the priority lane
+
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	PrivateLifetime uint64 // Number of blocks after which not included private transactions are dropped
+
+	DropsIndex int // Number of recently dropped transactions to remember along with the drop reasons
+}
+
+// PriorityMethod is a contract method, calls of which are in the priority lane.
+type PriorityMethod struct {
+	Contract common.Address
+	Selector hexutil.Bytes // 4-byte ABI method selector
+}
+
+// priorityMethod returns the priority lane entry of the contract method with the given signature.
+func priorityMethod(contract common.Address, signature string) PriorityMethod {
+	return PriorityMethod{
+		Contract: contract,
+		Selector: crypto.Keccak256([]byte(signature))[:4],
+	}
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -167,6 +207,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	Resnapshot: 10 * time.Minute,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -175,7 +217,19 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue: 32,
 	GlobalQueue:  256,
 
+	// only the staking operations are prioritized, not an arbitrary call of SFC
+	PriorityMethods: []PriorityMethod{
+		priorityMethod(sfc.ContractAddress, "delegate(uint256)"),
+		priorityMethod(sfc.ContractAddress, "undelegate(uint256,uint256,uint256)"),
+		priorityMethod(sfc.ContractAddress, "claimRewards(uint256)"),
+	},
+	PrioritySlots: 256,
+
 	Lifetime: 3 * time.Hour,
+
+	PrivateLifetime: 25,
+
+	DropsIndex: 4096,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -186,6 +240,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.Resnapshot < time.Second {
+		log.Warn("Sanitizing invalid txpool snapshot time", "provided", conf.Resnapshot, "updated", time.Second)
+		conf.Resnapshot = time.Second
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
 		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
@@ -214,6 +272,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.PrivateLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool private lifetime", "provided", conf.PrivateLifetime, "updated", DefaultTxPoolConfig.PrivateLifetime)
+		conf.PrivateLifetime = DefaultTxPoolConfig.PrivateLifetime
+	}
+	if conf.DropsIndex < 1 {
+		log.Warn("Sanitizing invalid txpool drops index", "provided", conf.DropsIndex, "updated", DefaultTxPoolConfig.DropsIndex)
+		conf.DropsIndex = DefaultTxPoolConfig.DropsIndex
+	}
 	return conf
 }
 
@@ -230,6 +296,7 @@ type TxPool struct {
 	chain       stateReader
 	gasPrice    *big.Int
 	txFeed      notify.Feed
+	dropFeed    notify.Feed
 	scope       notify.SubscriptionScope
 	signer      types.Signer
 	mu          sync.RWMutex
@@ -239,12 +306,17 @@ type TxPool struct {
 	istanbul bool // Fork indicator whether we are in the istanbul stage.
 	eip2718  bool // Fork indicator whether we are using EIP-2718 type transactions.
 
+	sponsorship bool // Upgrade indicator whether sponsored transactions are accepted.
+
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *txJournal  // Journal of local transaction to back up to disk
+	locals   *accountSet                    // Set of local transaction to exempt from eviction rules
+	priority map[priorityMethodKey]struct{} // Contract methods of the priority lane, immutable
+	journal  *txJournal                     // Journal of local transaction to back up to disk
+
+	snapshot *txSnapshot // Snapshot of all transactions to back up to disk
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -252,6 +324,11 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
+	drops    *lru.Cache   // Recently dropped transactions with the drop reasons
+	newDrops []*DroppedTx // Drops which subscribers weren't notified about yet
+	// Transactions of the blocks applied by the current reset, nil if they aren't known
+	includedTxs map[common.Hash]struct{}
+
 	chainHeadCh     chan ChainHeadNotify
 	chainHeadSub    notify.Subscription
 	reqResetCh      chan *txpoolResetRequest
@@ -295,7 +372,18 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
+	pool.priority = make(map[priorityMethodKey]struct{}, len(config.PriorityMethods))
+	for _, method := range config.PriorityMethods {
+		if len(method.Selector) != 4 {
+			log.Warn("Ignoring invalid txpool priority method", "contract", method.Contract, "selector", method.Selector)
+			continue
+		}
+		key := priorityMethodKey{contract: method.Contract}
+		copy(key.selector[:], method.Selector)
+		pool.priority[key] = struct{}{}
+	}
 	pool.priced = newTxPricedList(pool.all)
+	pool.drops, _ = lru.New(config.DropsIndex)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -313,6 +401,14 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain state
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If snapshotting is enabled, load remote transactions from disk
+	if config.Snapshot != "" {
+		pool.snapshot = newTxSnapshot(config.Snapshot)
+
+		if err := pool.snapshot.load(pool.addSnapshotTxs); err != nil {
+			log.Warn("Failed to load transaction pool snapshot", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeNewBlock(pool.chainHeadCh)
@@ -331,15 +427,17 @@ func (pool *TxPool) loop() {
 	var (
 		prevPending, prevQueued, prevStales int
 		// Start the stats reporting and transaction eviction tickers
-		report  = time.NewTicker(statsReportInterval)
-		evict   = time.NewTicker(evictionInterval)
-		journal = time.NewTicker(pool.config.Rejournal)
+		report   = time.NewTicker(statsReportInterval)
+		evict    = time.NewTicker(evictionInterval)
+		journal  = time.NewTicker(pool.config.Rejournal)
+		snapshot = time.NewTicker(pool.config.Resnapshot)
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
 	defer journal.Stop()
+	defer snapshot.Stop()
 
 	for {
 		// zilionixx-specific gas price updates
@@ -394,12 +492,14 @@ func (pool *TxPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
-						pool.removeTx(tx.Hash(), true)
+						pool.removeTx(tx.Hash(), true, DropExpired)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			drops := pool.takeDrops()
 			pool.mu.Unlock()
+			pool.sendDrops(drops)
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
@@ -410,6 +510,14 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+
+		// Handle transactions snapshot regeneration
+		case <-snapshot.C:
+			if pool.snapshot != nil {
+				if err := pool.snapshot.save(pool.snapshotTxs()); err != nil {
+					log.Warn("Failed to save txpool snapshot", "err", err)
+				}
+			}
 		}
 	}
 }
@@ -426,9 +534,20 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.snapshot != nil {
+		if err := pool.snapshot.save(pool.snapshotTxs()); err != nil {
+			log.Warn("Failed to save txpool snapshot", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 }
 
+// SubscribeDroppedTxsNotify registers a subscription of DroppedTxsNotify and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDroppedTxsNotify(ch chan<- DroppedTxsNotify) notify.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // SubscribeNewTxsNotify registers a subscription of NewTxsNotify and
 // starts sending event to the given channel.
 func (pool *TxPool) SubscribeNewTxsNotify(ch chan<- NewTxsNotify) notify.Subscription {
@@ -451,12 +570,14 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 
 func (pool *TxPool) SetGasPriceWithCap(price, cap *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
-
 	pool.gasPrice = price
 	for _, tx := range pool.priced.Cap(cap) {
-		pool.removeTx(tx.Hash(), false)
+		pool.removeTx(tx.Hash(), false, DropUnderpriced)
 	}
+	drops := pool.takeDrops()
+	pool.mu.Unlock()
+
+	pool.sendDrops(drops)
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
@@ -550,15 +671,69 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr := range pool.locals.accounts {
 		if pending := pool.pending[addr]; pending != nil {
-			txs[addr] = append(txs[addr], pending.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(pending.Flatten())...)
 		}
 		if queued := pool.queue[addr]; queued != nil {
-			txs[addr] = append(txs[addr], queued.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(queued.Flatten())...)
+		}
+		if len(txs[addr]) == 0 {
+			delete(txs, addr)
 		}
 	}
 	return txs
 }
 
+// public filters out private transactions, which must not be persisted,
+// as they would be gossiped publicly after a restart.
+func (pool *TxPool) public(txs types.Transactions) types.Transactions {
+	res := make(types.Transactions, 0, len(txs))
+	for _, tx := range txs {
+		if !pool.all.IsPrivate(tx.Hash()) {
+			res = append(res, tx)
+		}
+	}
+	return res
+}
+
+// snapshotTxs retrieves all pending and queued public transactions along with their arrival times.
+func (pool *TxPool) snapshotTxs() []snapshotTx {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	txs := make([]snapshotTx, 0, pool.all.Count())
+	add := func(lists map[common.Address]*txList) {
+		for _, list := range lists {
+			for _, tx := range pool.public(list.Flatten()) {
+				stx := snapshotTx{Tx: tx}
+				if arrival, ok := pool.all.Time(tx.Hash()); ok {
+					stx.Time = uint64(arrival.UnixNano())
+				}
+				txs = append(txs, stx)
+			}
+		}
+	}
+	add(pool.pending)
+	add(pool.queue)
+	return txs
+}
+
+// addSnapshotTxs enqueues a batch of snapshotted transactions as remote ones,
+// restoring their arrival times. Zero times are unknown, so the current time is kept.
+func (pool *TxPool) addSnapshotTxs(txs []*types.Transaction, times []time.Time) []error {
+	errs := pool.addTxs(txs, false, true)
+	for i, err := range errs {
+		if err == nil && !times[i].IsZero() {
+			pool.all.SetTime(txs[i].Hash(), times[i])
+		}
+	}
+	return errs
+}
+
+// ArrivalTime returns the time when the transaction was first seen by the pool.
+func (pool *TxPool) ArrivalTime(hash common.Hash) (time.Time, bool) {
+	return pool.all.Time(hash)
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
@@ -593,9 +768,29 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetNonce(from) > tx.Nonce() {
 		return ErrNonceTooLow
 	}
+	// Sponsor should have enough funds to cover GP * GL of a sponsored transaction
+	if inter.IsSponsored(tx) {
+		if !pool.sponsorship {
+			return ErrSponsorshipNotActive
+		}
+		sponsor, err := inter.TxSponsor(tx, from)
+		if err != nil {
+			return inter.ErrInvalidSponsor
+		}
+		// other txs of the sponsor are accounted too, except the one which would be replaced
+		committed := pool.all.SponsorCost(sponsor)
+		if old := pool.overlapping(from, tx.Nonce()); old != nil {
+			if oldSponsor, ok := pool.all.Sponsor(old.Hash()); ok && oldSponsor == sponsor {
+				committed.Sub(committed, gasCost(old))
+			}
+		}
+		if pool.sponsorBalance(sponsor).Cmp(committed.Add(committed, gasCost(tx))) < 0 {
+			return ErrInsufficientSponsorFunds
+		}
+	}
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
-	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
+	// cost == V + GP * GL, or V if the transaction is sponsored
+	if pool.currentState.GetBalance(from).Cmp(senderCost(tx)) < 0 {
 		return ErrInsufficientFunds
 	}
 	// Ensure the transaction has more gas than the basic tx fee.
@@ -663,7 +858,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxMeter.Mark(1)
-			pool.removeTx(tx.Hash(), false)
+			pool.removeTx(tx.Hash(), false, DropUnderpriced)
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
@@ -677,11 +872,13 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		}
 		// New transaction is better, replace old one
 		if old != nil {
+			pool.recordDrop(old, DropReplaced)
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
 		}
 		pool.all.Add(tx, isLocal)
+		pool.trackSponsor(tx, from)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
@@ -696,6 +893,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	if err != nil {
 		return false, err
 	}
+	pool.trackSponsor(tx, from)
 	// Mark local addresses and journal local transactions
 	if local && !pool.locals.contains(from) {
 		log.Info("Setting new local account", "address", from)
@@ -728,6 +926,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
+		pool.recordDrop(old, DropReplaced)
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
@@ -755,7 +954,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 // deemed to have been sent from a local account.
 func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	// Only journal if it's enabled and the transaction is local
-	if pool.journal == nil || !pool.locals.contains(from) {
+	if pool.journal == nil || !pool.locals.contains(from) || pool.all.IsPrivate(tx.Hash()) {
 		return
 	}
 	if err := pool.journal.insert(tx); err != nil {
@@ -777,6 +976,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
+		pool.recordDrop(tx, DropReplaced)
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
@@ -784,6 +984,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
+		pool.recordDrop(old, DropReplaced)
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
@@ -844,6 +1045,74 @@ func (pool *TxPool) AddRemote(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// AddPrivate enqueues a single local transaction into the pool if it is valid, marking
+// it as private. Private transactions aren't journaled or gossiped publicly, and are
+// dropped if not included within PrivateLifetime blocks.
+//
+// This method is used to add private transactions from the RPC API.
+func (pool *TxPool) AddPrivate(tx *types.Transaction) error {
+	return pool.addPrivateTxs([]*types.Transaction{tx}, !pool.config.NoLocals, true)[0]
+}
+
+// AddPrivateRemotes enqueues a batch of private transactions received from the p2p network.
+func (pool *TxPool) AddPrivateRemotes(txs []*types.Transaction) []error {
+	return pool.addPrivateTxs(txs, false, false)
+}
+
+// addPrivateTxs marks the unknown transactions as private before adding them,
+// so that the private flag is set by the time subsystems are notified.
+func (pool *TxPool) addPrivateTxs(txs []*types.Transaction, local, sync bool) []error {
+	deadline := pool.chain.CurrentBlock().NumberU64() + pool.config.PrivateLifetime
+	marked := make([]bool, len(txs))
+	for i, tx := range txs {
+		marked[i] = pool.all.MarkPrivate(tx.Hash(), deadline)
+	}
+	errs := pool.addTxs(txs, local, sync)
+	for i, err := range errs {
+		if err != nil && marked[i] {
+			pool.all.UnmarkPrivate(txs[i].Hash())
+		}
+	}
+	return errs
+}
+
+// IsPrivate returns true if the transaction must be forwarded only to validators.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	return pool.all.IsPrivate(hash)
+}
+
+// priorityMethodKey identifies a contract method of the priority lane.
+type priorityMethodKey struct {
+	contract common.Address
+	selector [4]byte
+}
+
+// IsPriority returns true if the transaction belongs to the priority lane,
+// i.e. it calls one of the configured contract methods.
+func (pool *TxPool) IsPriority(tx *types.Transaction) bool {
+	if tx.To() == nil || len(tx.Data()) < 4 {
+		return false
+	}
+	key := priorityMethodKey{contract: *tx.To()}
+	copy(key.selector[:], tx.Data())
+	_, ok := pool.priority[key]
+	return ok
+}
+
+// priorityCount returns number of the priority lane transactions in the list.
+func (pool *TxPool) priorityCount(list *txList) uint64 {
+	if len(pool.priority) == 0 {
+		return 0
+	}
+	count := uint64(0)
+	for _, tx := range list.txs.items {
+		if pool.IsPriority(tx) {
+			count++
+		}
+	}
+	return count
+}
+
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 	// Filter out known ones without obtaining the pool lock or recovering signatures
@@ -876,7 +1145,9 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	drops := pool.takeDrops()
 	pool.mu.Unlock()
+	pool.sendDrops(drops)
 
 	var nilSlot = 0
 	for _, err := range newErrs {
@@ -949,14 +1220,15 @@ func (pool *TxPool) OnlyNotExisting(hashes []common.Hash) []common.Hash {
 }
 
 // removeTx removes a single transaction from the queue, moving all subsequent
-// transactions back to the future queue.
-func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
+// transactions back to the future queue. The drop reason is recorded.
+func (pool *TxPool) removeTx(hash common.Hash, outofbound bool, reason TxDropReason) {
 	// Fetch the transaction we wish to delete
 	tx := pool.all.Get(hash)
 	if tx == nil {
 		return
 	}
 	addr, _ := types.Sender(pool.signer, tx) // already validated during insertion
+	pool.recordDrop(tx, reason)
 
 	// Remove it from the list of known transactions
 	pool.all.Remove(hash)
@@ -998,6 +1270,20 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 	}
 }
 
+// dropExpiredPrivate removes private transactions whose lifetime ended before the given block.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) dropExpiredPrivate(number uint64) {
+	for _, hash := range pool.all.ExpiredPrivate(number) {
+		if pool.all.Get(hash) != nil {
+			log.Trace("Dropping expired private transaction", "hash", hash)
+			pool.removeTx(hash, true, DropPrivateExpired)
+		} else {
+			pool.all.UnmarkPrivate(hash)
+		}
+	}
+}
+
 // requestReset requests a pool reset to the new head block.
 // The returned channel is closed when the reset has occurred.
 func (pool *TxPool) requestReset(oldHead *EvmHeader, newHead *EvmHeader) chan struct{} {
@@ -1137,6 +1423,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.dropUnpayableSponsored()
 	}
 	// Ensure pool.queue and pool.pending sizes stay within the configured limits.
 	pool.truncatePending()
@@ -1147,8 +1434,13 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		highestPending := list.LastElement()
 		pool.pendingNonces.set(addr, highestPending.Nonce()+1)
 	}
+	drops := pool.takeDrops()
+	pool.includedTxs = nil
 	pool.mu.Unlock()
 
+	// Notify subsystems for dropped transactions
+	pool.sendDrops(drops)
+
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
 		addr, _ := types.Sender(pool.signer, tx)
@@ -1234,8 +1526,13 @@ func (pool *TxPool) reset(oldHead, newHead *EvmHeader) {
 					}
 				}
 				reinject = types.TxDifference(discarded, included)
+				pool.includedTxs = txHashSet(included)
 			}
 		}
+	} else if oldHead != nil {
+		if add := pool.chain.GetBlock(newHead.Hash, newHead.Number.Uint64()); add != nil {
+			pool.includedTxs = txHashSet(add.Transactions)
+		}
 	}
 	// Initialize the internal state to the current head
 	if newHead == nil {
@@ -1255,10 +1552,14 @@ func (pool *TxPool) reset(oldHead, newHead *EvmHeader) {
 	senderCacher.recover(pool.signer, reinject)
 	pool.addTxsLocked(reinject, false)
 
+	// Drop private transactions which weren't included in time
+	pool.dropExpiredPrivate(newHead.Number.Uint64())
+
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
 	pool.eip2718 = pool.chainconfig.IsBerlin(next)
+	pool.sponsorship = pool.chain.Upgrades().Sponsorship
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1278,6 +1579,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		forwards := list.Forward(pool.currentState.GetNonce(addr))
 		for _, tx := range forwards {
 			hash := tx.Hash()
+			pool.recordStale(tx)
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
@@ -1285,6 +1587,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
+			pool.recordDrop(tx, DropUnexecutable)
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
@@ -1307,6 +1610,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			caps = list.Cap(int(pool.config.AccountQueue))
 			for _, tx := range caps {
 				hash := tx.Hash()
+				pool.recordDrop(tx, DropQueueOverflow)
 				pool.all.Remove(hash)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
@@ -1332,10 +1636,23 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 // equal number for all for accounts with many pending transactions.
 func (pool *TxPool) truncatePending() {
 	pending := uint64(0)
-	for _, list := range pool.pending {
+	prioritized := uint64(0)
+	priorityOnly := make(map[common.Address]bool)
+	for addr, list := range pool.pending {
 		pending += uint64(list.Len())
+		count := pool.priorityCount(list)
+		prioritized += count
+		priorityOnly[addr] = count == uint64(list.Len())
+	}
+	// The priority lane transactions occupy own slots, the excess is counted against the global slots
+	globalSlots := pool.config.GlobalSlots
+	laneWithinBudget := prioritized <= pool.config.PrioritySlots
+	if laneWithinBudget {
+		globalSlots += prioritized
+	} else {
+		globalSlots += pool.config.PrioritySlots
 	}
-	if pending <= pool.config.GlobalSlots {
+	if pending <= globalSlots {
 		return
 	}
 
@@ -1343,14 +1660,17 @@ func (pool *TxPool) truncatePending() {
 	// Assemble a spam order to penalize large transactors first
 	spammers := prque.New(nil)
 	for addr, list := range pool.pending {
-		// Only evict transactions from high rollers
+		// Only evict transactions from high rollers, accounts using only the priority lane are exempt while it's within the budget
+		if laneWithinBudget && priorityOnly[addr] {
+			continue
+		}
 		if !pool.locals.contains(addr) && uint64(list.Len()) > pool.config.AccountSlots {
 			spammers.Push(addr, int64(list.Len()))
 		}
 	}
 	// Gradually drop transactions from offenders
 	offenders := []common.Address{}
-	for pending > pool.config.GlobalSlots && !spammers.Empty() {
+	for pending > globalSlots && !spammers.Empty() {
 		// Retrieve the next offender if not local address
 		offender, _ := spammers.Pop()
 		offenders = append(offenders, offender.(common.Address))
@@ -1361,7 +1681,7 @@ func (pool *TxPool) truncatePending() {
 			threshold := pool.pending[offender.(common.Address)].Len()
 
 			// Iteratively reduce all offenders until below limit or threshold reached
-			for pending > pool.config.GlobalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
+			for pending > globalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
 				for i := 0; i < len(offenders)-1; i++ {
 					list := pool.pending[offenders[i]]
 
@@ -1369,6 +1689,7 @@ func (pool *TxPool) truncatePending() {
 					for _, tx := range caps {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
+						pool.recordDrop(tx, DropPendingOverflow)
 						pool.all.Remove(hash)
 
 						// Update the account nonce to the dropped transaction
@@ -1387,8 +1708,8 @@ func (pool *TxPool) truncatePending() {
 	}
 
 	// If still above threshold, reduce to limit or min allowance
-	if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-		for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+	if pending > globalSlots && len(offenders) > 0 {
+		for pending > globalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
 			for _, addr := range offenders {
 				list := pool.pending[addr]
 
@@ -1396,6 +1717,7 @@ func (pool *TxPool) truncatePending() {
 				for _, tx := range caps {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
+					pool.recordDrop(tx, DropPendingOverflow)
 					pool.all.Remove(hash)
 
 					// Update the account nonce to the dropped transaction
@@ -1443,7 +1765,7 @@ func (pool *TxPool) truncateQueue() {
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
-				pool.removeTx(tx.Hash(), true)
+				pool.removeTx(tx.Hash(), true, DropQueueOverflow)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1452,7 +1774,7 @@ func (pool *TxPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
-			pool.removeTx(txs[i].Hash(), true)
+			pool.removeTx(txs[i].Hash(), true, DropQueueOverflow)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1471,6 +1793,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		olds := list.Forward(nonce)
 		for _, tx := range olds {
 			hash := tx.Hash()
+			pool.recordStale(tx)
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
@@ -1479,6 +1802,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
+			pool.recordDrop(tx, DropUnexecutable)
 			pool.all.Remove(hash)
 		}
 		pool.priced.Removed(len(olds) + len(drops))
@@ -1617,6 +1941,11 @@ type txLookup struct {
 	lock    sync.RWMutex
 	locals  map[common.Hash]*types.Transaction
 	remotes map[common.Hash]*types.Transaction
+	times   map[common.Hash]time.Time
+	private map[common.Hash]uint64 // deadline block of private transactions
+
+	sponsors     map[common.Hash]common.Address // sponsors of the sponsored transactions
+	sponsorCosts map[common.Address]*big.Int    // total GP * GL of the sponsored transactions per sponsor
 }
 
 // newTxLookup returns a new txLookup structure.
@@ -1624,6 +1953,11 @@ func newTxLookup() *txLookup {
 	return &txLookup{
 		locals:  make(map[common.Hash]*types.Transaction),
 		remotes: make(map[common.Hash]*types.Transaction),
+		times:   make(map[common.Hash]time.Time),
+		private: make(map[common.Hash]uint64),
+
+		sponsors:     make(map[common.Hash]common.Address),
+		sponsorCosts: make(map[common.Address]*big.Int),
 	}
 }
 
@@ -1639,6 +1973,9 @@ func (t *txLookup) SampleHashes(max int) []common.Hash {
 		if len(res) >= max {
 			return false
 		}
+		if _, ok := t.private[key]; ok {
+			return true
+		}
 		if skip > 0 {
 			skip--
 			return true
@@ -1766,6 +2103,26 @@ func (t *txLookup) Add(tx *types.Transaction, local bool) {
 	} else {
 		t.remotes[tx.Hash()] = tx
 	}
+	t.times[tx.Hash()] = time.Now()
+}
+
+// Time returns the time when a transaction was added to the lookup.
+func (t *txLookup) Time(hash common.Hash) (time.Time, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	added, ok := t.times[hash]
+	return added, ok
+}
+
+// SetTime overrides the time when a transaction was added to the lookup.
+func (t *txLookup) SetTime(hash common.Hash, added time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.times[hash]; ok {
+		t.times[hash] = added
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1786,6 +2143,116 @@ func (t *txLookup) Remove(hash common.Hash) {
 
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
+	delete(t.times, hash)
+	delete(t.private, hash)
+
+	if sponsor, ok := t.sponsors[hash]; ok {
+		cost := t.sponsorCosts[sponsor]
+		cost.Sub(cost, gasCost(tx))
+		if cost.Sign() <= 0 {
+			delete(t.sponsorCosts, sponsor)
+		}
+		delete(t.sponsors, hash)
+	}
+}
+
+// SetSponsor records the sponsor of a known transaction, which gas cost is committed by the sponsor.
+func (t *txLookup) SetSponsor(hash common.Hash, sponsor common.Address) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tx := t.locals[hash]
+	if tx == nil {
+		tx = t.remotes[hash]
+	}
+	if _, ok := t.sponsors[hash]; ok || tx == nil {
+		return
+	}
+	t.sponsors[hash] = sponsor
+	if t.sponsorCosts[sponsor] == nil {
+		t.sponsorCosts[sponsor] = new(big.Int)
+	}
+	t.sponsorCosts[sponsor].Add(t.sponsorCosts[sponsor], gasCost(tx))
+}
+
+// Sponsor returns the recorded sponsor of a transaction.
+func (t *txLookup) Sponsor(hash common.Hash) (common.Address, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	sponsor, ok := t.sponsors[hash]
+	return sponsor, ok
+}
+
+// SponsorCost returns the total gas cost of the transactions sponsored by the sponsor.
+func (t *txLookup) SponsorCost(sponsor common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if cost := t.sponsorCosts[sponsor]; cost != nil {
+		return new(big.Int).Set(cost)
+	}
+	return new(big.Int)
+}
+
+// Sponsors returns the sponsors of the known transactions, along with their transactions.
+func (t *txLookup) Sponsors() map[common.Address]types.Transactions {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	res := make(map[common.Address]types.Transactions, len(t.sponsorCosts))
+	for hash, sponsor := range t.sponsors {
+		tx := t.locals[hash]
+		if tx == nil {
+			tx = t.remotes[hash]
+		}
+		res[sponsor] = append(res[sponsor], tx)
+	}
+	return res
+}
+
+// MarkPrivate marks a not yet known transaction as private until the deadline block.
+// Returns false if the transaction is already known or marked.
+func (t *txLookup) MarkPrivate(hash common.Hash, deadline uint64) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.private[hash]; ok || t.locals[hash] != nil || t.remotes[hash] != nil {
+		return false
+	}
+	t.private[hash] = deadline
+	return true
+}
+
+// UnmarkPrivate removes the private mark of a transaction.
+func (t *txLookup) UnmarkPrivate(hash common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.private, hash)
+}
+
+// IsPrivate returns true if the transaction is marked as private.
+func (t *txLookup) IsPrivate(hash common.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	_, ok := t.private[hash]
+	return ok
+}
+
+// ExpiredPrivate returns the private transactions whose deadline is before the given block.
+func (t *txLookup) ExpiredPrivate(number uint64) []common.Hash {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var expired []common.Hash
+	for hash, deadline := range t.private {
+		if deadline < number {
+			expired = append(expired, hash)
+		}
+	}
+	return expired
 }
 
 // RemoteToLocals migrates the transactions belongs to the given locals to locals
@@ -17,6 +17,7 @@
 package evmcore
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -24,9 +25,11 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -34,6 +37,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/zilionixx/go-zilionixx/gossip/contract/sfc100"
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+	"github.com/zilionixx/go-zilionixx/zilionixx/genesis/sfc"
 )
 
 // testTxPoolConfig is a transaction pool configuration without stateful disk
@@ -72,6 +80,18 @@ func (bc *testBlockChain) GetBlock(hash common.Hash, number uint64) *EvmBlock {
 	return bc.CurrentBlock()
 }
 
+// includingBlockChain is a testBlockChain whose blocks contain the given transactions
+type includingBlockChain struct {
+	*testBlockChain
+	txs types.Transactions
+}
+
+func (bc *includingBlockChain) GetBlock(hash common.Hash, number uint64) *EvmBlock {
+	block := bc.CurrentBlock()
+	block.Transactions = bc.txs
+	return block
+}
+
 func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
 	return bc.statedb, nil
 }
@@ -100,6 +120,10 @@ func (bc *testBlockChain) Config() *params.ChainConfig {
 	return nil
 }
 
+func (bc *testBlockChain) Upgrades() zilionixx.Upgrades {
+	return zilionixx.Upgrades{}
+}
+
 func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
 	return pricedTransaction(nonce, gaslimit, big.NewInt(1), key)
 }
@@ -391,7 +415,7 @@ func TestTransactionChainFork(t *testing.T) {
 	if _, err := pool.add(tx, false); err != nil {
 		t.Error("didn't expect error", err)
 	}
-	pool.removeTx(tx.Hash(), true)
+	pool.removeTx(tx.Hash(), true, DropUnknown)
 
 	// reset the pool's internal state
 	resetState()
@@ -1139,6 +1163,328 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that the transactions of the priority lane have own slots and
+// aren't evicted by the pending limits enforcement.
+func TestTransactionPriorityLane(t *testing.T) {
+	t.Parallel()
+
+	// Create the pool to test the limit enforcement with
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	lane := common.Address{0xfc}
+	config := testTxPoolConfig
+	config.GlobalSlots = config.AccountSlots * 10
+	config.PriorityMethods = []PriorityMethod{priorityMethod(lane, "stake()")}
+	config.PrioritySlots = config.AccountSlots * 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	// Create a number of test accounts and fund them
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	staker, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(staker.PublicKey), big.NewInt(1000000))
+
+	// Generate a batch of transactions exceeding the global slots, and a batch of the priority lane
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := 0; j < int(config.GlobalSlots)/len(keys)*2; j++ {
+			txs = append(txs, transaction(uint64(j), 100000, key))
+		}
+	}
+	for j := 0; j < int(config.PrioritySlots); j++ {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(j), lane, big.NewInt(0), 100000, big.NewInt(1), config.PriorityMethods[0].Selector), types.HomesteadSigner{}, staker)
+		if !pool.IsPriority(tx) {
+			t.Fatalf("call of the lane method isn't in the priority lane")
+		}
+		txs = append(txs, tx)
+	}
+	if pool.IsPriority(txs[0]) {
+		t.Fatalf("transaction to other address is in the priority lane")
+	}
+	// other calls of the lane contract aren't prioritized
+	for _, data := range [][]byte{nil, {0x01, 0x02, 0x03, 0x04}, config.PriorityMethods[0].Selector[:3]} {
+		tx, _ := types.SignTx(types.NewTransaction(0, lane, big.NewInt(0), 100000, big.NewInt(1), data), types.HomesteadSigner{}, staker)
+		if pool.IsPriority(tx) {
+			t.Fatalf("call %x of the lane contract is in the priority lane", data)
+		}
+	}
+	pool.AddRemotesSync(txs)
+
+	pending := 0
+	for _, list := range pool.pending {
+		pending += list.Len()
+	}
+	if pending > int(config.GlobalSlots+config.PrioritySlots) {
+		t.Fatalf("total pending transactions overflow allowance: %d > %d", pending, config.GlobalSlots+config.PrioritySlots)
+	}
+	if pending < int(config.GlobalSlots) {
+		t.Fatalf("global slots are occupied by the priority lane: %d < %d", pending, config.GlobalSlots)
+	}
+	if list := pool.pending[crypto.PubkeyToAddress(staker.PublicKey)]; list == nil || list.Len() != int(config.PrioritySlots) {
+		t.Fatalf("priority lane transactions evicted")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the default priority lane consists of the staking methods of SFC.
+func TestDefaultPriorityMethods(t *testing.T) {
+	sfcAbi, err := abi.JSON(strings.NewReader(sfc100.ContractABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"delegate", "undelegate", "claimRewards"} {
+		found := false
+		for _, method := range DefaultTxPoolConfig.PriorityMethods {
+			if method.Contract == sfc.ContractAddress && bytes.Equal(method.Selector, sfcAbi.Methods[name].ID) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("SFC method %s isn't in the priority lane", name)
+		}
+	}
+	if len(DefaultTxPoolConfig.PriorityMethods) != 3 {
+		t.Errorf("unexpected number of the priority methods: %d", len(DefaultTxPoolConfig.PriorityMethods))
+	}
+}
+
+// Tests that the account status reports queued nonces, nonce gaps and the minimum
+// prices which are accepted as replacements of pending transactions.
+func TestTransactionAccountStatus(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(1000000000))
+
+	pool.AddRemotesSync([]*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), key),
+		pricedTransaction(1, 100000, big.NewInt(100), key),
+		transaction(3, 100000, key),
+		transaction(4, 100000, key),
+		transaction(7, 100000, key),
+	})
+
+	status := pool.AccountStatus(from)
+	if status.StateNonce != 0 {
+		t.Errorf("state nonce mismatch: have %d, want %d", status.StateNonce, 0)
+	}
+	if status.PendingNonce != 2 {
+		t.Errorf("pending nonce mismatch: have %d, want %d", status.PendingNonce, 2)
+	}
+	if want := []uint64{3, 4, 7}; fmt.Sprint(status.Queued) != fmt.Sprint(want) {
+		t.Errorf("queued nonces mismatch: have %v, want %v", status.Queued, want)
+	}
+	if want := []NonceGap{{2, 2}, {5, 6}}; fmt.Sprint(status.Gaps) != fmt.Sprint(want) {
+		t.Errorf("nonce gaps mismatch: have %v, want %v", status.Gaps, want)
+	}
+	if len(status.Pending) != 2 {
+		t.Fatalf("pending transactions mismatch: have %d, want %d", len(status.Pending), 2)
+	}
+	for i, want := range []int64{2, 100 * (100 + int64(testTxPoolConfig.PriceBump)) / 100} {
+		r := status.Pending[i]
+		if r.MinPrice.Int64() != want {
+			t.Errorf("replacement price of tx %d mismatch: have %v, want %d", i, r.MinPrice, want)
+		}
+		below := new(big.Int).Sub(r.MinPrice, common.Big1)
+		if err := pool.addRemoteSync(pricedTransaction(r.Tx.Nonce(), 100001, below, key)); err != ErrReplaceUnderpriced {
+			t.Errorf("replacement of tx %d below the price error mismatch: have %v, want %v", i, err, ErrReplaceUnderpriced)
+		}
+		if err := pool.addRemoteSync(pricedTransaction(r.Tx.Nonce(), 100001, r.MinPrice, key)); err != nil {
+			t.Errorf("replacement of tx %d at the price rejected: %v", i, err)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// sponsorshipChain is a testBlockChain after the Sponsorship upgrade
+type sponsorshipChain struct {
+	*testBlockChain
+}
+
+func (bc sponsorshipChain) Upgrades() zilionixx.Upgrades {
+	return zilionixx.Upgrades{Berlin: true, Sponsorship: true}
+}
+
+func sponsoredTransaction(nonce uint64, value *big.Int, key, sponsor *ecdsa.PrivateKey) *types.Transaction {
+	signer := types.NewEIP2930Signer(params.TestChainConfig.ChainID)
+	data := &types.AccessListTx{
+		ChainID:  params.TestChainConfig.ChainID,
+		Nonce:    nonce,
+		GasPrice: big.NewInt(1),
+		Gas:      100000,
+		To:       &common.Address{},
+		Value:    value,
+	}
+	sig, _ := crypto.Sign(inter.SponsorHash(types.NewTx(data), crypto.PubkeyToAddress(key.PublicKey)).Bytes(), sponsor)
+	sponsored, _ := inter.WithSponsorSignature(data, sig)
+	return types.MustSignNewTx(key, signer, sponsored)
+}
+
+// Tests that sponsored transactions are accepted only after the upgrade, and that
+// gas is checked against the sponsor's balance instead of the sender's one.
+func TestTransactionSponsored(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	key, _ := crypto.GenerateKey()
+	sponsor, _ := crypto.GenerateKey()
+	poor, _ := crypto.GenerateKey()
+
+	// sponsored transactions are rejected before the upgrade
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(100))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(sponsor.PublicKey), big.NewInt(1000000))
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(100), key, sponsor)); err != ErrSponsorshipNotActive {
+		t.Fatalf("sponsored transaction error mismatch: have %v, want %v", err, ErrSponsorshipNotActive)
+	}
+	pool.Stop()
+
+	statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain = &testBlockChain{statedb, 1000000, new(event.Feed)}
+	pool = NewTxPool(testTxPoolConfig, params.TestChainConfig, sponsorshipChain{blockchain})
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(100))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(sponsor.PublicKey), big.NewInt(1000000))
+
+	// the sponsor has to cover gas * price
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(100), key, poor)); err != ErrInsufficientSponsorFunds {
+		t.Fatalf("sponsored transaction error mismatch: have %v, want %v", err, ErrInsufficientSponsorFunds)
+	}
+	// the sender has to cover only the value
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(101), key, sponsor)); err != ErrInsufficientFunds {
+		t.Fatalf("sponsored transaction error mismatch: have %v, want %v", err, ErrInsufficientFunds)
+	}
+	tx := sponsoredTransaction(0, big.NewInt(100), key, sponsor)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add sponsored transaction: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	// the list isn't truncated by the sender's balance, which is lower than the gas cost
+	<-pool.requestReset(nil, nil)
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched after reset: have %d, want %d", pending, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the gas cost of all the pool transactions of a sponsor is checked against
+// the sponsor's balance, and that unpayable sponsored transactions are evicted on reset.
+func TestTransactionSponsorCommittedCost(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, sponsorshipChain{blockchain})
+	defer pool.Stop()
+
+	sponsor, _ := crypto.GenerateKey()
+	sponsorAddr := crypto.PubkeyToAddress(sponsor.PublicKey)
+	pool.currentState.AddBalance(sponsorAddr, big.NewInt(250000))
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+
+	// every transaction costs 100000
+	first := sponsoredTransaction(0, big.NewInt(0), keys[0], sponsor)
+	second := sponsoredTransaction(0, big.NewInt(0), keys[1], sponsor)
+	for _, tx := range []*types.Transaction{first, second} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add sponsored transaction: %v", err)
+		}
+	}
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(0), keys[2], sponsor)); err != ErrInsufficientSponsorFunds {
+		t.Fatalf("sponsored transaction error mismatch: have %v, want %v", err, ErrInsufficientSponsorFunds)
+	}
+	if cost := pool.all.SponsorCost(sponsorAddr); cost.Cmp(big.NewInt(200000)) != 0 {
+		t.Fatalf("sponsor cost mismatched: have %v, want %v", cost, 200000)
+	}
+
+	// the latest transaction is evicted once the sponsor can't pay for both
+	pool.all.SetTime(first.Hash(), time.Now().Add(-time.Minute))
+	pool.mu.Lock()
+	pool.currentState.SetBalance(sponsorAddr, big.NewInt(150000))
+	pool.mu.Unlock()
+	<-pool.requestReset(nil, nil)
+
+	if !pool.Has(first.Hash()) || pool.Has(second.Hash()) {
+		t.Fatalf("unpayable sponsored transaction wasn't evicted")
+	}
+	if drop, ok := pool.Dropped(second.Hash()); !ok || drop.Reason != DropUnexecutable {
+		t.Fatalf("unpayable sponsored transaction drop isn't recorded")
+	}
+	if cost := pool.all.SponsorCost(sponsorAddr); cost.Cmp(big.NewInt(100000)) != 0 {
+		t.Fatalf("sponsor cost mismatched: have %v, want %v", cost, 100000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the costs of the sponsor's own pending transactions are subtracted from
+// the sponsor's balance before the sponsored transactions are accounted.
+func TestTransactionSponsorOwnCost(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, sponsorshipChain{blockchain})
+	defer pool.Stop()
+
+	sponsor, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(sponsor.PublicKey), big.NewInt(250000))
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+
+	// own transactions of the sponsor cost 100100, sponsored ones cost 100000
+	if err := pool.addRemoteSync(transaction(0, 100000, sponsor)); err != nil {
+		t.Fatalf("failed to add sponsor's transaction: %v", err)
+	}
+	sponsored := sponsoredTransaction(0, big.NewInt(0), keys[0], sponsor)
+	if err := pool.addRemoteSync(sponsored); err != nil {
+		t.Fatalf("failed to add sponsored transaction: %v", err)
+	}
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(0), keys[1], sponsor)); err != ErrInsufficientSponsorFunds {
+		t.Fatalf("sponsored transaction error mismatch: have %v, want %v", err, ErrInsufficientSponsorFunds)
+	}
+
+	// the sponsored transaction is evicted once the sponsor's own transactions leave not enough funds
+	if err := pool.addRemoteSync(transaction(1, 100000, sponsor)); err != nil {
+		t.Fatalf("failed to add sponsor's transaction: %v", err)
+	}
+	<-pool.requestReset(nil, nil)
+	if pool.Has(sponsored.Hash()) {
+		t.Fatalf("unpayable sponsored transaction wasn't evicted")
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the limit on transaction size is enforced correctly.
 // This test verifies every transaction having allowed size
 // is added to the pool, and longer transactions are rejected.
@@ -1889,6 +2235,236 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// TestTransactionSnapshot tests that remote transactions survive restarts
+// with their arrival times, and are revalidated on load.
+func TestTransactionSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Create a temporary file for the snapshot
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary snapshot: %v", err)
+	}
+	snapshot := file.Name()
+	defer os.Remove(snapshot)
+	file.Close()
+	os.Remove(snapshot)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.NoLocals = true
+	config.Snapshot = snapshot
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+	remote, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	// Add two pending and one queued remote transactions
+	txs := types.Transactions{
+		pricedTransaction(0, 100000, big.NewInt(1), remote),
+		pricedTransaction(1, 100000, big.NewInt(1), remote),
+		pricedTransaction(3, 100000, big.NewInt(1), remote),
+	}
+	for _, tx := range txs {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add remote transaction: %v", err)
+		}
+	}
+	arrival, ok := pool.ArrivalTime(txs[1].Hash())
+	if !ok {
+		t.Fatalf("arrival time is unknown")
+	}
+	pool.Stop()
+
+	// Bump the nonce, so that the first transaction becomes stale
+	statedb.SetNonce(crypto.PubkeyToAddress(remote.PublicKey), 1)
+	blockchain = &testBlockChain{statedb, 1000000, new(event.Feed)}
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	pending, queued := pool.Stats()
+	if pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	if restored, _ := pool.ArrivalTime(txs[1].Hash()); !restored.Equal(arrival) {
+		t.Fatalf("arrival time mismatched: have %v, want %v", restored, arrival)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// TestTransactionSnapshotUnknownTime tests that transactions with unknown arrival
+// times are snapshotted without a time and restored with the current one.
+func TestTransactionSnapshotUnknownTime(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	tx := transaction(0, 100000, key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	pool.all.lock.Lock()
+	delete(pool.all.times, tx.Hash())
+	pool.all.lock.Unlock()
+
+	txs := pool.snapshotTxs()
+	if len(txs) != 1 {
+		t.Fatalf("snapshotted transactions mismatched: have %d, want %d", len(txs), 1)
+	}
+	if txs[0].Time != 0 {
+		t.Fatalf("unknown arrival time snapshotted: have %d, want 0", txs[0].Time)
+	}
+
+	pool.removeTx(tx.Hash(), true, DropUnknown)
+	before := time.Now()
+	if errs := pool.addSnapshotTxs(types.Transactions{tx}, []time.Time{{}}); errs[0] != nil {
+		t.Fatalf("failed to restore snapshotted transaction: %v", errs[0])
+	}
+	if arrival, ok := pool.ArrivalTime(tx.Hash()); !ok || arrival.Before(before) {
+		t.Fatalf("arrival time mismatched: have %v, want after %v", arrival, before)
+	}
+}
+
+// TestTransactionPrivate tests that private transactions are excluded from gossip
+// samples and journaling, and are dropped after their lifetime.
+func TestTransactionPrivate(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.PrivateLifetime = 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	public := pricedTransaction(0, 100000, big.NewInt(1), key)
+	private := pricedTransaction(1, 100000, big.NewInt(1), key)
+	if err := pool.AddLocal(public); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := pool.AddPrivate(private); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if pool.IsPrivate(public.Hash()) || !pool.IsPrivate(private.Hash()) {
+		t.Fatalf("private flags mismatched")
+	}
+	// Already known transactions cannot be re-marked
+	if err := pool.AddPrivate(public); err != ErrAlreadyKnown {
+		t.Fatalf("adding known transaction error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if pool.IsPrivate(public.Hash()) {
+		t.Fatalf("known public transaction marked as private")
+	}
+	if hashes := pool.SampleHashes(10); len(hashes) != 1 || hashes[0] != public.Hash() {
+		t.Fatalf("sampled hashes mismatched: have %v, want %v", hashes, []common.Hash{public.Hash()})
+	}
+	pool.mu.RLock()
+	journaled := pool.local()
+	pool.mu.RUnlock()
+	if txs := journaled[crypto.PubkeyToAddress(key.PublicKey)]; len(txs) != 1 || txs[0].Hash() != public.Hash() {
+		t.Fatalf("journaled transactions mismatched: have %d", len(txs))
+	}
+
+	// Private transaction is kept until the lifetime ends
+	<-pool.requestReset(nil, &EvmHeader{Number: big.NewInt(3)})
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	<-pool.requestReset(nil, &EvmHeader{Number: big.NewInt(4)})
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if pool.Has(private.Hash()) || pool.IsPrivate(private.Hash()) {
+		t.Fatalf("expired private transaction wasn't dropped")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// TestTransactionDropReasons tests that the reasons of dropped transactions are
+// recorded, reported by status queries and sent to subscribers.
+func TestTransactionDropReasons(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxsNotify, 10)
+	sub := pool.SubscribeDroppedTxsNotify(drops)
+	defer sub.Unsubscribe()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(addr, big.NewInt(1000000000))
+
+	replaced := pricedTransaction(0, 100000, big.NewInt(1), key)
+	replacing := pricedTransaction(0, 100000, big.NewInt(2), key)
+	stale := pricedTransaction(1, 100000, big.NewInt(1), key)
+	included := pricedTransaction(2, 100000, big.NewInt(1), key)
+	for _, tx := range []*types.Transaction{replaced, replacing, stale, included} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if status, drop := pool.TxStatus(replaced.Hash()); status != TxStatusDropped || drop.Reason != DropReplaced {
+		t.Fatalf("replaced transaction status mismatched: have %v", status)
+	}
+	if status, _ := pool.TxStatus(replacing.Hash()); status != TxStatusPending {
+		t.Fatalf("replacing transaction status mismatched: have %v, want %v", status, TxStatusPending)
+	}
+	// Transactions become stale once the nonce is used in the chain,
+	// but the one included into the new block isn't dropped
+	pool.mu.Lock()
+	pool.currentState.SetNonce(addr, 3)
+	pool.chain = &includingBlockChain{pool.chain.(*testBlockChain), types.Transactions{included}}
+	pool.mu.Unlock()
+	<-pool.requestReset(&EvmHeader{Number: big.NewInt(1)}, &EvmHeader{Number: big.NewInt(2)})
+
+	if status, drop := pool.TxStatus(stale.Hash()); status != TxStatusDropped || drop.Reason != DropNonceTooLow {
+		t.Fatalf("stale transaction status mismatched: have %v", status)
+	}
+	if _, ok := pool.Dropped(included.Hash()); ok {
+		t.Fatalf("included transaction is recorded as dropped")
+	}
+	if status, _ := pool.TxStatus(common.Hash{}); status != TxStatusUnknown {
+		t.Fatalf("unknown transaction status mismatched: have %v, want %v", status, TxStatusUnknown)
+	}
+
+	want := map[common.Hash]TxDropReason{
+		replaced.Hash():  DropReplaced,
+		replacing.Hash(): DropNonceTooLow,
+		stale.Hash():     DropNonceTooLow,
+	}
+	for len(want) > 0 {
+		select {
+		case ev := <-drops:
+			for _, drop := range ev.Drops {
+				if reason, ok := want[drop.Tx.Hash()]; !ok || reason != drop.Reason {
+					t.Fatalf("unexpected drop notification: %x %v", drop.Tx.Hash(), drop.Reason)
+				}
+				delete(want, drop.Tx.Hash())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing drop notifications: %d", len(want))
+		}
+	}
+}
+
 // TestTransactionStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestTransactionStatusCheck(t *testing.T) {
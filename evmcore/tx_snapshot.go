@@ -0,0 +1,114 @@
+package evmcore
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// snapshotTx is a snapshotted transaction along with its arrival time
+type snapshotTx struct {
+	Tx   *types.Transaction
+	Time uint64 // unix nanoseconds, zero if unknown
+}
+
+// txSnapshot is a full dump of pending and queued transactions with the aim of
+// allowing remote transactions to survive node restarts. Unlike the journal, it
+// isn't appended on every transaction, but regenerated periodically and on stop.
+type txSnapshot struct {
+	path string // Filesystem path to store the transactions at
+}
+
+// newTxSnapshot creates a new transaction snapshot
+func newTxSnapshot(path string) *txSnapshot {
+	return &txSnapshot{
+		path: path,
+	}
+}
+
+// load parses a transaction snapshot from disk, loading its contents into
+// the specified pool. The transactions are revalidated by add.
+func (snapshot *txSnapshot) load(add func([]*types.Transaction, []time.Time) []error) error {
+	// Skip the parsing if the snapshot file doesn't exist at all
+	if _, err := os.Stat(snapshot.path); os.IsNotExist(err) {
+		return nil
+	}
+	input, err := os.Open(snapshot.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	total, dropped := 0, 0
+
+	var (
+		failure error
+		batch   types.Transactions
+		times   []time.Time
+	)
+	loadBatch := func() {
+		for _, err := range add(batch, times) {
+			if err != nil {
+				log.Trace("Failed to add snapshotted transaction", "err", err)
+				dropped++
+			}
+		}
+		batch, times = batch[:0], times[:0]
+	}
+	for {
+		// Parse the next transaction and terminate on error
+		stx := new(snapshotTx)
+		if err = stream.Decode(stx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			if batch.Len() > 0 {
+				loadBatch()
+			}
+			break
+		}
+		total++
+
+		batch = append(batch, stx.Tx)
+		var arrival time.Time
+		if stx.Time != 0 {
+			arrival = time.Unix(0, int64(stx.Time))
+		}
+		times = append(times, arrival)
+		if batch.Len() > 1024 {
+			loadBatch()
+		}
+	}
+	log.Info("Loaded transaction pool snapshot", "transactions", total, "dropped", dropped)
+
+	return failure
+}
+
+// save regenerates the snapshot with the given transactions
+func (snapshot *txSnapshot) save(txs []snapshotTx) error {
+	replacement, err := os.OpenFile(snapshot.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for i := range txs {
+		if err = rlp.Encode(replacement, &txs[i]); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	if err = replacement.Close(); err != nil {
+		return err
+	}
+	// Replace the snapshot with the newly generated one
+	if err = os.Rename(snapshot.path+".new", snapshot.path); err != nil {
+		return err
+	}
+	log.Debug("Regenerated transaction pool snapshot", "transactions", len(txs))
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package gossip
+
+// startAncientsMover starts the background job which moves blocks, receipts and events
+// of old epochs from the DB into the freezer.
+func (s *Service) startAncientsMover() {
+	if !s.store.ancientsMoving() {
+		return
+	}
+	s.startPeriodicJob(s.store.cfg.Freezer.Period, s.moveAncients)
+}
+
+// moveAncients moves the old data in batches. Data is copied under the read lock,
+// and deleted from the DB under the write lock after the freezer is synced.
+func (s *Service) moveAncients() {
+	cfg := s.store.cfg.Freezer
+	for {
+		s.engineMu.RLock()
+		epoch := s.store.GetEpoch()
+		if epoch <= cfg.Epochs {
+			s.engineMu.RUnlock()
+			return
+		}
+		blocks, events, err := s.store.freezeAncients(epoch-cfg.Epochs, cfg.BatchSize)
+		s.engineMu.RUnlock()
+		if err != nil {
+			s.Log.Error("Failed to move ancient data into the freezer", "err", err)
+			return
+		}
+		if blocks == 0 && events == 0 {
+			return
+		}
+
+		s.engineMu.Lock()
+		s.store.deleteFrozenAncients()
+		s.engineMu.Unlock()
+		s.Log.Info("Moved ancient data into the freezer", "blocks", blocks, "events", events)
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
@@ -1,8 +1,18 @@
 package gossip
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/gossip/emitter"
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
+	"github.com/zilionixx/go-zilionixx/valkeystore"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum-like information.
@@ -35,3 +45,90 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return hexutil.Uint64(api.s.store.GetRules().EvmChainConfig().ChainID.Uint64())
 }
+
+// PrivateEmitterAPI provides an API to inspect the events emitter.
+type PrivateEmitterAPI struct {
+	s *Service
+}
+
+// NewPrivateEmitterAPI creates a new emitter API.
+func NewPrivateEmitterAPI(s *Service) *PrivateEmitterAPI {
+	return &PrivateEmitterAPI{s}
+}
+
+// Status returns the current emitter intervals, sync status, gas power, offline validators,
+// outstanding challenges and the latest emit/skip decisions with reasons.
+// If decisionsNum isn't specified, all the remembered decisions are returned.
+func (api *PrivateEmitterAPI) Status(decisionsNum *int) emitter.Status {
+	n := 0
+	if decisionsNum != nil {
+		n = *decisionsNum
+	}
+	return api.s.emitter.Status(n)
+}
+
+// PrivateValidatorAPI provides an API to switch the node between observer and validator modes without a restart.
+type PrivateValidatorAPI struct {
+	s        *Service
+	keystore valkeystore.KeystoreI
+}
+
+// NewPrivateValidatorAPI creates a new validator API.
+func NewPrivateValidatorAPI(s *Service, keystore valkeystore.KeystoreI) *PrivateValidatorAPI {
+	return &PrivateValidatorAPI{s, keystore}
+}
+
+// SetKey unlocks the validator key and switches events emission to the given validator ID and key.
+// Emission is resumed only after the doublesign protection period.
+func (api *PrivateValidatorAPI) SetKey(validatorID hexutil.Uint, pubkeyStr string, password string) error {
+	if validatorID == 0 {
+		return errors.New("validator ID cannot be zero, use validator_stop to switch to the observer mode")
+	}
+	pubkey, err := validatorpk.FromString(pubkeyStr)
+	if err != nil {
+		return err
+	}
+	es := api.s.store.GetEpochState()
+	if profile, ok := es.ValidatorProfiles[idx.ValidatorID(validatorID)]; ok && !bytes.Equal(profile.PubKey.Bytes(), pubkey.Bytes()) {
+		return fmt.Errorf("validator %d has a different pubkey %s in epoch %d", validatorID, profile.PubKey.String(), es.Epoch)
+	}
+	if !api.keystore.Unlocked(pubkey) {
+		err = api.keystore.Unlock(pubkey, password)
+		if err != nil && err != valkeystore.ErrAlreadyUnlocked {
+			return err
+		}
+	}
+	return api.s.SwitchValidator(emitter.ValidatorConfig{
+		ID:     idx.ValidatorID(validatorID),
+		PubKey: pubkey,
+	})
+}
+
+// UnlockKey unlocks a validator key without switching to it.
+// It's used to prepare a rotated key, which the emitter switches to at the epoch where validator profiles reflect it.
+func (api *PrivateValidatorAPI) UnlockKey(pubkeyStr string, password string) error {
+	pubkey, err := validatorpk.FromString(pubkeyStr)
+	if err != nil {
+		return err
+	}
+	err = api.keystore.Unlock(pubkey, password)
+	if err == valkeystore.ErrAlreadyUnlocked {
+		return nil
+	}
+	return err
+}
+
+// PublishGasPriceVote publishes the gas price vote in the next self-event.
+func (api *PrivateValidatorAPI) PublishGasPriceVote(price hexutil.Big) error {
+	return api.s.PublishExtraRecord(inter.NewGasPriceVoteRecord(price.ToInt()))
+}
+
+// PublishOracleValue publishes the value of an oracle feed in the next self-event.
+func (api *PrivateValidatorAPI) PublishOracleValue(feed string, value hexutil.Big) error {
+	return api.s.PublishExtraRecord(inter.NewOracleValueRecord(feed, value.ToInt()))
+}
+
+// Stop drains the events emission and switches the node to the observer mode.
+func (api *PrivateValidatorAPI) Stop() error {
+	return api.s.SwitchValidator(emitter.ValidatorConfig{})
+}
@@ -147,6 +147,7 @@ func (s *Store) applyEpoch1Genesis(blockProc BlockProc, g zilionixx.Genesis) (er
 		txListener.OnNewLog(l)
 		sfcapi.OnNewLog(s.sfcapi, l)
 	}, es.Rules)
+	s.SetBlockRules(blockCtx.Idx, es.Epoch, es.Rules)
 
 	// Execute genesis-internal transactions
 	genesisInternalTxs := blockProc.GenesisTxTransactor.PopInternalTxs(blockCtx, bs, es, sealing, statedb)
@@ -24,8 +24,10 @@ func New() *EVMModule {
 
 func (p *EVMModule) Start(block blockproc.BlockCtx, statedb *state.StateDB, reader evmcore.DummyChain, onNewLog func(*types.Log), net zilionixx.Rules) blockproc.EVMProcessor {
 	var prevBlockHash common.Hash
+	var prevHeader *evmcore.EvmHeader
 	if block.Idx != 0 {
-		prevBlockHash = reader.GetHeader(common.Hash{}, uint64(block.Idx-1)).Hash
+		prevHeader = reader.GetHeader(common.Hash{}, uint64(block.Idx-1))
+		prevBlockHash = prevHeader.Hash
 	}
 	return &zilionixxEVMProcessor{
 		block:         block,
@@ -35,6 +37,7 @@ func (p *EVMModule) Start(block blockproc.BlockCtx, statedb *state.StateDB, read
 		net:           net,
 		blockIdx:      utils.U64toBig(uint64(block.Idx)),
 		prevBlockHash: prevBlockHash,
+		baseFee:       evmcore.CalcBaseFee(net, prevHeader, block.Time),
 	}
 }
 
@@ -47,6 +50,7 @@ type zilionixxEVMProcessor struct {
 
 	blockIdx      *big.Int
 	prevBlockHash common.Hash
+	baseFee       *big.Int
 
 	gasUsed uint64
 
@@ -65,13 +69,14 @@ func (p *zilionixxEVMProcessor) evmBlockWith(txs types.Transactions) *evmcore.Ev
 		Coinbase:   common.Address{},
 		GasLimit:   math.MaxUint64,
 		GasUsed:    p.gasUsed,
+		BaseFee:    p.baseFee,
 	}
 
 	return evmcore.NewEvmBlock(h, txs)
 }
 
 func (p *zilionixxEVMProcessor) Execute(txs types.Transactions, internal bool) types.Receipts {
-	evmProcessor := evmcore.NewStateProcessor(p.net.EvmChainConfig(), p.reader)
+	evmProcessor := evmcore.NewStateProcessor(p.net.EvmChainConfig(), p.reader, p.net.Upgrades)
 
 	// Process txs
 	evmBlock := p.evmBlockWith(txs)
@@ -0,0 +1,52 @@
+package gossip
+
+import "time"
+
+// bloomIndexerPeriod is an interval between runs of the bloom-bits indexer
+const bloomIndexerPeriod = time.Minute
+
+// startBloomIndexer starts the background job which adds the complete sections of blocks
+// into the bloom-bits index of logs.
+func (s *Service) startBloomIndexer() {
+	if !s.config.TxIndex {
+		return
+	}
+	s.startPeriodicJob(bloomIndexerPeriod, s.indexBloomSections)
+}
+
+// indexBloomSections indexes the complete sections one by one. Blooms are collected under the read lock,
+// and the section is written under the write lock.
+func (s *Service) indexBloomSections() {
+	index := s.store.evm.EvmBlooms()
+	for {
+		sections, err := index.Sections()
+		if err != nil {
+			s.Log.Error("Failed to read bloom-bits index", "err", err)
+			return
+		}
+
+		s.engineMu.RLock()
+		head := uint64(s.store.GetLatestBlockIndex())
+		if (sections+1)*index.SectionSize() > head+1 {
+			s.engineMu.RUnlock()
+			return
+		}
+		blooms := s.store.sectionBlooms(sections)
+		s.engineMu.RUnlock()
+
+		s.engineMu.Lock()
+		err = index.AddSection(sections, blooms)
+		s.engineMu.Unlock()
+		if err != nil {
+			s.Log.Error("Failed to add bloom-bits section", "section", sections, "err", err)
+			return
+		}
+		s.Log.Info("Indexed bloom-bits section", "section", sections)
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
@@ -151,6 +151,7 @@ func consensusCallbackBeginBlockFn(
 					sfcapi.OnNewLog(store.sfcapi, l)
 				}
 				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, onNewLogAll, es.Rules)
+				store.SetBlockRules(blockCtx.Idx, es.Epoch, es.Rules)
 
 				// Execute pre-internal transactions
 				preInternalTxs := blockProc.PreTxTransactor.PopInternalTxs(blockCtx, bs, es, sealing, statedb)
@@ -28,12 +28,10 @@ func (s *Service) buildEvent(e *inter.MutableEventPayload, onIndexed func()) err
 	// set some unique ID
 	e.SetID(s.uniqueEventIDs.sample())
 
-	// node version
-	if e.Seq() <= 1 && len(s.config.Emitter.VersionToPublish) > 0 {
-		version := []byte("v-" + s.config.Emitter.VersionToPublish)
-		if uint32(len(version)) <= s.store.GetRules().Dag.MaxExtraData {
-			e.SetExtra(version)
-		}
+	// node version and validator's metadata
+	extra := s.buildExtra(e.Seq(), s.store.GetRules().Dag.MaxExtraData)
+	if len(extra) != 0 {
+		e.SetExtra(extra)
 	}
 
 	// set PrevEpochHash
@@ -169,6 +167,9 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 	}
 
 	s.emitter.OnEventConnected(e)
+	if v := s.identity.Validator(); v != 0 && e.Creator() == v {
+		s.onSelfEventProcessed(e)
+	}
 
 	if newEpoch != oldEpoch {
 		// reset dag indexer
@@ -14,6 +14,7 @@ import (
 	"github.com/zilionixx/zilion-base/inter/idx"
 	"github.com/zilionixx/zilion-base/utils/cachescale"
 
+	"github.com/zilionixx/go-zilionixx/ethapi"
 	"github.com/zilionixx/go-zilionixx/eventcheck/heavycheck"
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/gossip/blockproc/verwatcher"
@@ -60,7 +61,8 @@ type (
 
 		FilterAPI filters.Config
 
-		TxIndex bool // Whether to enable indexing transactions and receipts or not
+		TxIndex       bool   // Whether to enable indexing transactions and receipts or not
+		TxLookupLimit uint64 // Number of recent blocks to maintain transactions index for (0 = entire chain)
 
 		// Protocol options
 		Protocol ProtocolConfig
@@ -79,6 +81,10 @@ type (
 		// send-transction variants. The unit is ether.
 		RPCTxFeeCap float64 `toml:",omitempty"`
 
+		// TxSubmitLimits are rate limits of transaction submission over RPC,
+		// per sender address and per client origin.
+		TxSubmitLimits ethapi.TxSubmitLimitsConfig
+
 		// allows only for EIP155 transactions.
 		AllowUnprotectedTxs bool
 
@@ -96,11 +102,38 @@ type (
 		BlocksSize uint
 	}
 
+	// FreezerConfig is a config for the flat-file store of ancient blocks, events and receipts.
+	FreezerConfig struct {
+		// Dir is a directory of the freezer. The freezer is disabled if empty.
+		Dir string
+		// Epochs is a number of the last sealed epochs which are kept in the DB.
+		// Blocks, events and receipts of older epochs are moved into the freezer. Zero disables moving.
+		Epochs idx.Epoch
+		// Period is an interval between runs of the moving job.
+		Period time.Duration
+		// BatchSize is a max number of blocks and events which are moved at once.
+		BatchSize int
+	}
+
+	// EventsPrunerConfig is a config for pruning of historical events.
+	EventsPrunerConfig struct {
+		// Epochs is a number of the last sealed epochs whose events are kept. Zero disables pruning.
+		Epochs idx.Epoch
+		// Period is an interval between runs of the pruner.
+		Period time.Duration
+		// BatchSize is a max number of events which are pruned at once.
+		BatchSize int
+	}
+
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
 		// EVM is EVM store config
-		EVM                 evmstore.StoreConfig
+		EVM evmstore.StoreConfig
+		// Freezer is a config for ancient data
+		Freezer FreezerConfig
+		// EventsPruner is a config for pruning of historical events
+		EventsPruner        EventsPrunerConfig
 		MaxNonFlushedSize   int
 		MaxNonFlushedPeriod time.Duration
 	}
@@ -183,8 +216,9 @@ func DefaultConfig(scale cachescale.Func) Config {
 		},
 		RPCLogsBloom: true,
 
-		RPCGasCap:   25000000,
-		RPCTxFeeCap: 100, // 100 ZNX
+		RPCGasCap:      25000000,
+		RPCTxFeeCap:    100, // 100 ZNX
+		TxSubmitLimits: ethapi.DefaultTxSubmitLimitsConfig(),
 	}
 	cfg.Protocol.Processor.EventsBufferLimit.Num = idx.Event(cfg.Protocol.StreamLeecher.Session.ParallelChunksDownload)*cfg.Protocol.StreamLeecher.Session.DefaultChunkSize.Num + softLimitItems
 	cfg.Protocol.Processor.EventsBufferLimit.Size = uint64(cfg.Protocol.StreamLeecher.Session.ParallelChunksDownload)*cfg.Protocol.StreamLeecher.Session.DefaultChunkSize.Size + 8*opt.MiB
@@ -197,6 +231,9 @@ func DefaultConfig(scale cachescale.Func) Config {
 }
 
 func (c *Config) Validate() error {
+	if err := c.Emitter.TxSelection.Validate(); err != nil {
+		return err
+	}
 	if c.Protocol.StreamLeecher.Session.DefaultChunkSize.Num > hardLimitItems-1 {
 		return fmt.Errorf("DefaultChunkSize.Num has to be at not greater than %d", hardLimitItems-1)
 	}
@@ -241,6 +278,8 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			BlocksSize: scale.U(512 * opt.KiB),
 		},
 		EVM:                 evmstore.DefaultStoreConfig(scale),
+		Freezer:             DefaultFreezerConfig(),
+		EventsPruner:        DefaultEventsPrunerConfig(),
 		MaxNonFlushedSize:   17*opt.MiB + scale.I(5*opt.MiB),
 		MaxNonFlushedPeriod: 30 * time.Minute,
 	}
@@ -256,11 +295,29 @@ func LiteStoreConfig() StoreConfig {
 			BlocksSize: 50 * opt.KiB,
 		},
 		EVM:                 evmstore.LiteStoreConfig(),
+		Freezer:             DefaultFreezerConfig(),
+		EventsPruner:        DefaultEventsPrunerConfig(),
 		MaxNonFlushedSize:   800 * opt.KiB,
 		MaxNonFlushedPeriod: 30 * time.Minute,
 	}
 }
 
+// DefaultFreezerConfig returns the freezer config without a directory and with disabled moving.
+func DefaultFreezerConfig() FreezerConfig {
+	return FreezerConfig{
+		Period:    time.Minute,
+		BatchSize: 1000,
+	}
+}
+
+// DefaultEventsPrunerConfig returns the config with disabled pruning of events.
+func DefaultEventsPrunerConfig() EventsPrunerConfig {
+	return EventsPrunerConfig{
+		Period:    time.Minute,
+		BatchSize: 1000,
+	}
+}
+
 func DefaultPeerCacheConfig(scale cachescale.Func) PeerCacheConfig {
 	return PeerCacheConfig{
 		MaxKnownTxs:    24576*3/4 + scale.I(24576/4),
@@ -34,6 +34,21 @@ func (p *dummyTxPool) AddRemotes(txs []*types.Transaction) []error {
 	return make([]error, len(txs))
 }
 
+// AddPrivateRemotes appends a batch of transactions to the pool, same as AddRemotes
+func (p *dummyTxPool) AddPrivateRemotes(txs []*types.Transaction) []error {
+	return p.AddRemotes(txs)
+}
+
+// IsPriority returns false, as the dummy pool has no priority lane
+func (p *dummyTxPool) IsPriority(*types.Transaction) bool {
+	return false
+}
+
+// IsPrivate returns false, as the dummy pool doesn't track private transactions
+func (p *dummyTxPool) IsPrivate(common.Hash) bool {
+	return false
+}
+
 // Pending returns all the transactions known to the pool
 func (p *dummyTxPool) Pending() (map[common.Address]types.Transactions, error) {
 	p.lock.RLock()
@@ -1,6 +1,7 @@
 package emitter
 
 import (
+	"math/big"
 	"math/rand"
 	"time"
 
@@ -33,6 +34,8 @@ type PrevEmittedEventFile struct {
 // Config is the configuration of events emitter.
 type Config struct {
 	VersionToPublish string
+	// GasPriceVote is published in the first self-event of every epoch, if set
+	GasPriceVote *big.Int `toml:",omitempty"`
 
 	Validator ValidatorConfig
 
@@ -40,6 +43,8 @@ type Config struct {
 
 	MaxTxsPerAddress int
 
+	TxSelection TxSelectionConfig
+
 	MaxParents idx.Event
 
 	// thresholds on GasLeft
@@ -67,6 +72,10 @@ func DefaultConfig() Config {
 
 		MaxTxsPerAddress: TxTurnNonces,
 
+		TxSelection: TxSelectionConfig{
+			Policy: PriceTxSelection,
+		},
+
 		MaxParents: 0,
 
 		LimitedTpsThreshold: zilionixx.DefaultEventGas * 120,
@@ -64,6 +64,7 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 					"power", e.GasPowerLeft().String(),
 					"selfParentPower", selfParent.GasPowerLeft().String(),
 					"stake%", 100*float64(em.validators.Get(e.Creator()))/float64(em.validators.TotalWeight()))
+				em.skip("gas power is below EmergencyThreshold and decreasing")
 				return false
 			}
 		}
@@ -91,6 +92,7 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 			factor := float64(e.GasPowerLeft().Min()) / float64(threshold)
 			adjustedEmitInterval := time.Duration(maxT - (maxT-minT)*factor)
 			if passedTime < adjustedEmitInterval {
+				em.skip("gas power is low, emitting is slowed down")
 				return false
 			}
 		}
@@ -100,21 +102,25 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 		if passedTime < em.intervals.Max &&
 			em.idle() &&
 			!eTxs {
+			em.skip("no txs to confirm or originate")
 			return false
 		}
 	}
 	// Emitting is controlled by the efficiency metric
 	{
 		if passedTime < em.intervals.Min {
+			em.skip("Min emit interval hasn't passed")
 			return false
 		}
 		if adjustedPassedTime < em.intervals.Min &&
 			!em.idle() {
+			em.skip("metric-adjusted Min emit interval hasn't passed")
 			return false
 		}
 		if adjustedPassedIdleTime < em.intervals.Confirming &&
 			!em.idle() &&
 			!eTxs {
+			em.skip("metric-adjusted Confirming emit interval hasn't passed")
 			return false
 		}
 	}
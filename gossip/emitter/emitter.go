@@ -1,6 +1,7 @@
 package emitter
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"os"
@@ -69,18 +70,27 @@ type Emitter struct {
 	done chan struct{}
 	wg   sync.WaitGroup
 
+	switchMu sync.Mutex
+	// keyRotationPending is true while the rotated validator key isn't unlocked yet
+	keyRotationPending bool
+
 	maxParents idx.Event
 
 	cache struct {
-		sortedTxs *types.TransactionsByPriceAndNonce
+		sortedTxs TxsIterator
 		poolTime  time.Time
 		poolBlock idx.Block
 		poolCount int
 	}
 
+	txSelector TxSelector
+
 	emittedEventFile *os.File
 	busyRate         *rate.Gauge
 
+	decisions *decisionsLog
+	status    statusSnapshot
+
 	logger.Periodic
 }
 
@@ -95,14 +105,24 @@ func NewEmitter(
 	config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(r)
 
 	txTime, _ := lru.New(TxTimeBufferSize)
-	return &Emitter{
+	em := &Emitter{
 		config:        config,
 		world:         world,
 		originatedTxs: originatedtxs.New(SenderCountBufferSize),
 		txTime:        txTime,
 		intervals:     config.EmitIntervals,
+		decisions:     newDecisionsLog(DecisionsBufferSize),
 		Periodic:      logger.Periodic{Instance: logger.MakeInstance()},
 	}
+	em.txSelector = MakeTxSelector(config.TxSelection, world.TxSigner, em.getTxTime, world.TxPool.IsPriority)
+	return em
+}
+
+// SetTxSelector replaces the policy of choosing transactions to originate.
+// Should be called before Start.
+func (em *Emitter) SetTxSelector(selector TxSelector) {
+	em.txSelector = selector
+	em.cache.sortedTxs = nil
 }
 
 // init emitter without starting events emission
@@ -110,6 +130,12 @@ func (em *Emitter) init() {
 	em.syncStatus.startup = time.Now()
 	em.syncStatus.lastConnected = time.Now()
 	em.syncStatus.p2pSynced = time.Now()
+	em.status.setSync(SyncStatus{
+		Reason:        "not checked yet",
+		Startup:       em.syncStatus.startup,
+		LastConnected: em.syncStatus.lastConnected,
+		P2PSynced:     em.syncStatus.p2pSynced,
+	})
 	validators, epoch := em.world.GetEpochValidators()
 	em.OnNewEpoch(validators, epoch)
 
@@ -119,6 +145,17 @@ func (em *Emitter) init() {
 	em.busyRate = rate.NewGauge()
 }
 
+// deinit emitter after events emission is stopped
+func (em *Emitter) deinit() {
+	em.busyRate.Stop()
+	if em.emittedEventFile != nil {
+		if err := em.emittedEventFile.Close(); err != nil {
+			em.Log.Warn("Failed to close event file", "file", em.config.PrevEmittedEventFile.Path, "err", err)
+		}
+		em.emittedEventFile = nil
+	}
+}
+
 // Start starts event emission.
 func (em *Emitter) Start() {
 	if em.config.Validator.ID == 0 {
@@ -128,16 +165,20 @@ func (em *Emitter) Start() {
 	if em.done != nil {
 		return
 	}
+	em.world.Lock()
 	em.init()
+	em.world.Unlock()
 	em.done = make(chan struct{})
+	em.status.setRunning(true)
 
 	newTxsCh := make(chan evmcore.NewTxsNotify)
-	em.world.TxPool.SubscribeNewTxsNotify(newTxsCh)
+	newTxsSub := em.world.TxPool.SubscribeNewTxsNotify(newTxsCh)
 
 	done := em.done
 	em.wg.Add(1)
 	go func() {
 		defer em.wg.Done()
+		defer newTxsSub.Unsubscribe()
 		tick := 11 * time.Millisecond
 		timer := time.NewTimer(tick)
 		defer timer.Stop()
@@ -161,10 +202,39 @@ func (em *Emitter) Stop() {
 		return
 	}
 
+	em.status.setRunning(false)
 	close(em.done)
 	em.done = nil
 	em.wg.Wait()
-	em.busyRate.Stop()
+	em.deinit()
+}
+
+// SetValidator switches the emitter to another validator ID or key, or to the observer mode if ID is zero.
+// Events emission is stopped and drained before the switch, and restarted afterwards.
+// The doublesign protection is applied to the new validator as if the node was just started.
+func (em *Emitter) SetValidator(validator ValidatorConfig, prevEmittedEventFile PrevEmittedEventFile) {
+	em.switchMu.Lock()
+	defer em.switchMu.Unlock()
+
+	em.Stop()
+
+	em.world.Lock()
+	if em.config.Validator.ID != validator.ID || !bytes.Equal(em.config.Validator.PubKey.Bytes(), validator.PubKey.Bytes()) {
+		em.syncStatus.becameValidator = time.Now()
+	}
+	em.config.Validator = validator
+	em.config.PrevEmittedEventFile = prevEmittedEventFile
+	em.cache.sortedTxs = nil
+	em.world.Unlock()
+
+	em.Start()
+}
+
+// GetValidator returns the current validator config
+func (em *Emitter) GetValidator() ValidatorConfig {
+	em.world.Lock()
+	defer em.world.Unlock()
+	return em.config.Validator
 }
 
 func (em *Emitter) tick() {
@@ -193,7 +263,7 @@ func (em *Emitter) tick() {
 	}
 }
 
-func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
+func (em *Emitter) getSortedTxs() TxsIterator {
 	// Short circuit if pool wasn't updated since the cache was built
 	poolCount := em.world.TxPool.Count()
 	if em.cache.sortedTxs != nil &&
@@ -214,7 +284,7 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 			pendingTxs[from] = txs[:em.config.MaxTxsPerAddress]
 		}
 	}
-	sortedTxs := types.NewTransactionsByPriceAndNonce(em.world.TxSigner, pendingTxs)
+	sortedTxs := em.txSelector.Sort(pendingTxs)
 	em.cache.sortedTxs = sortedTxs
 	em.cache.poolCount = poolCount
 	em.cache.poolBlock = em.world.GetLatestBlockIndex()
@@ -230,11 +300,16 @@ func (em *Emitter) EmitEvent() *inter.EventPayload {
 	sortedTxs := em.getSortedTxs()
 
 	if em.world.IsBusy() {
+		em.skip("node is busy")
 		return nil
 	}
 	em.world.Lock()
 	defer em.world.Unlock()
 
+	if em.keyRotationPending {
+		em.switchRotatedKey()
+	}
+
 	start := time.Now()
 
 	e := em.createEvent(sortedTxs)
@@ -246,6 +321,7 @@ func (em *Emitter) EmitEvent() *inter.EventPayload {
 	err := em.world.Process(e)
 	if err != nil {
 		em.Log.Error("Self-event connection failed", "err", err.Error())
+		em.skip("self-event connection failed: " + err.Error())
 		return nil
 	}
 	// write event ID to avoid doublesigning in future after a crash
@@ -255,6 +331,7 @@ func (em *Emitter) EmitEvent() *inter.EventPayload {
 
 	em.prevEmittedAtTime = time.Now() // record time after connecting, to add the event processing time"
 	em.prevEmittedAtBlock = em.world.GetLatestBlockIndex()
+	em.emitted(e.ID())
 	em.Log.Info("New event emitted", "id", e.ID(), "parents", len(e.Parents()), "by", e.Creator(),
 		"frame", e.Frame(), "txs", e.Txs().Len(), "age", common.PrettyDuration(0), "t", common.PrettyDuration(time.Since(start)))
 
@@ -282,8 +359,9 @@ func (em *Emitter) loadPrevEmitTime() time.Time {
 }
 
 // createEvent is not safe for concurrent use.
-func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *inter.EventPayload {
+func (em *Emitter) createEvent(sortedTxs TxsIterator) *inter.EventPayload {
 	if !em.isValidator() {
+		em.skip("not a validator in current epoch")
 		return nil
 	}
 
@@ -302,6 +380,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 	// Find parents
 	selfParent, parents, ok := em.chooseParents(em.epoch, em.config.Validator.ID)
 	if !ok {
+		em.skip("doublesign is detected")
 		return nil
 	}
 
@@ -316,6 +395,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 		if parentHeaders[i].Creator() == em.config.Validator.ID && i != 0 {
 			// there're 2 heads from me, i.e. due to a fork, chooseParents could have found multiple self-parents
 			em.Periodic.Error(5*time.Second, "I've created a fork, events emitting isn't allowed", "creator", em.config.Validator.ID)
+			em.skip("fork is detected")
 			return nil
 		}
 		maxLamport = idx.MaxLamport(maxLamport, parent.Lamport())
@@ -353,6 +433,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 		} else {
 			em.Log.Warn("Dropped event while emitting", "err", err)
 		}
+		em.skip("event building failed: " + err.Error())
 		return nil
 	}
 
@@ -375,11 +456,14 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 
 	// calc Merkle root
 	mutEvent.SetTxHash(hash.Hash(types.DeriveSha(mutEvent.Txs(), new(trie.Trie))))
+	// the compact encoding of sponsored txs isn't supported by nodes before the upgrade
+	mutEvent.SetCompactSponsoredTxs(em.world.GetRules().Upgrades.Sponsorship)
 
 	// sign
 	bSig, err := em.world.Signer.Sign(em.config.Validator.PubKey, mutEvent.HashToSign().Bytes())
 	if err != nil {
 		em.Periodic.Error(time.Second, "Failed to sign event", "err", err)
+		em.skip("signing failed: " + err.Error())
 		return nil
 	}
 	var sig inter.Signature
@@ -392,6 +476,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 	// check
 	if err := em.world.Check(event, parentHeaders); err != nil {
 		em.Periodic.Error(time.Second, "Emitted incorrect event", "err", err)
+		em.skip("emitted event is incorrect: " + err.Error())
 		return nil
 	}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/zilionixx/go-zilionixx/gossip/emitter/mock"
 	"github.com/zilionixx/go-zilionixx/integration/makegenesis"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
 	"github.com/zilionixx/go-zilionixx/vecmt"
 )
 
@@ -76,6 +77,9 @@ func TestEmitter(t *testing.T) {
 			Return(inter.Timestamp(uint64(time.Now().UnixNano()))).
 			AnyTimes()
 
+		external.EXPECT().GetEpochPubKeys().
+			Return(map[idx.ValidatorID]validatorpk.PubKey{}, idx.Epoch(1))
+
 		em.init()
 	})
 
@@ -86,6 +90,8 @@ func TestEmitter(t *testing.T) {
 		external.EXPECT().IsBusy().
 			Return(true).
 			AnyTimes()
+		txPool.EXPECT().ArrivalTime(tx.Hash()).
+			Return(time.Time{}, false)
 
 		_, ok := em.txTime.Get(tx.Hash())
 		require.False(ok)
@@ -99,9 +105,50 @@ func TestEmitter(t *testing.T) {
 		require.True(ok)
 		require.True(got.After(before))
 		require.True(got.Before(after))
+
+		// arrival time of a restored tx is preserved
+		restored := types.NewTransaction(2, common.Address{}, big.NewInt(1), 1, big.NewInt(1), nil)
+		arrival := before.Add(-time.Hour)
+		txPool.EXPECT().ArrivalTime(restored.Hash()).
+			Return(arrival, true)
+		em.memorizeTxTimes(types.Transactions{restored})
+		cached, ok = em.txTime.Get(restored.Hash())
+		require.True(ok)
+		require.Equal(arrival, cached.(time.Time))
 	})
 
 	t.Run("tick", func(t *testing.T) {
 		em.tick()
 	})
+
+	t.Run("switchRotatedKey", func(t *testing.T) {
+		require := require.New(t)
+
+		rotated := gValidators[1].PubKey
+		external.EXPECT().GetEpochPubKeys().
+			Return(map[idx.ValidatorID]validatorpk.PubKey{cfg.Validator.ID: rotated}, idx.Epoch(2)).
+			Times(2)
+
+		// the switch stays pending while the new key is locked
+		signer.EXPECT().Unlocked(rotated).
+			Return(false)
+		em.switchRotatedKey()
+		require.Equal(cfg.Validator.PubKey, em.GetValidator().PubKey)
+		require.True(em.keyRotationPending)
+
+		signer.EXPECT().Unlocked(rotated).
+			Return(true)
+		external.EXPECT().OnValidatorKeyRotated(rotated)
+		em.switchRotatedKey()
+		require.Equal(rotated, em.GetValidator().PubKey)
+		require.False(em.keyRotationPending)
+	})
+
+	t.Run("SetValidator", func(t *testing.T) {
+		require := require.New(t)
+
+		em.SetValidator(ValidatorConfig{}, PrevEmittedEventFile{})
+		require.Equal(idx.ValidatorID(0), em.GetValidator().ID)
+		require.False(em.Status(0).Running)
+	})
 }
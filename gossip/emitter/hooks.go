@@ -28,6 +28,7 @@ func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
 	if !em.isValidator() {
 		return
 	}
+	em.switchRotatedKey()
 	// update myValidatorID
 	em.prevEmittedAtTime = em.loadPrevEmitTime()
 
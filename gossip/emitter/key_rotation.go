@@ -0,0 +1,30 @@
+package emitter
+
+import (
+	"bytes"
+	"time"
+)
+
+// switchRotatedKey switches the emitter to a new validator key if the key was rotated on chain.
+// The new key takes effect at the epoch where validator profiles reflect it, so the old key keeps signing
+// events until then. The new key has to be present and unlocked in the validator keystore, otherwise
+// the switch stays pending and is retried before each emitted event.
+func (em *Emitter) switchRotatedKey() {
+	pubkeys, epoch := em.world.GetEpochPubKeys()
+	pubkey, ok := pubkeys[em.config.Validator.ID]
+	if !ok || bytes.Equal(pubkey.Bytes(), em.config.Validator.PubKey.Bytes()) {
+		em.keyRotationPending = false
+		return
+	}
+	if !em.world.Signer.Unlocked(pubkey) {
+		em.keyRotationPending = true
+		em.Periodic.Error(time.Minute, "Validator key was rotated, but the new key isn't unlocked. Unlock it with validator_unlockKey or validator_setKey",
+			"validator", em.config.Validator.ID, "epoch", epoch, "pubkey", pubkey.String(), "prev", em.config.Validator.PubKey.String())
+		return
+	}
+	em.keyRotationPending = false
+	em.Log.Info("Switched to the rotated validator key", "validator", em.config.Validator.ID, "epoch", epoch,
+		"pubkey", pubkey.String(), "prev", em.config.Validator.PubKey.String())
+	em.config.Validator.PubKey = pubkey
+	em.world.OnValidatorKeyRotated(pubkey)
+}
@@ -19,6 +19,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	big "math/big"
 	reflect "reflect"
+	time "time"
 )
 
 // MockExternal is a mock of External interface
@@ -56,6 +57,18 @@ func (mr *MockExternalMockRecorder) Broadcast(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Broadcast", reflect.TypeOf((*MockExternal)(nil).Broadcast), arg0)
 }
 
+// OnValidatorKeyRotated mocks base method
+func (m *MockExternal) OnValidatorKeyRotated(arg0 validatorpk.PubKey) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnValidatorKeyRotated", arg0)
+}
+
+// OnValidatorKeyRotated indicates an expected call of OnValidatorKeyRotated
+func (mr *MockExternalMockRecorder) OnValidatorKeyRotated(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnValidatorKeyRotated", reflect.TypeOf((*MockExternal)(nil).OnValidatorKeyRotated), arg0)
+}
+
 // Build mocks base method
 func (m *MockExternal) Build(arg0 *inter.MutableEventPayload, arg1 func()) error {
 	m.ctrl.T.Helper()
@@ -141,6 +154,21 @@ func (mr *MockExternalMockRecorder) GetEventPayload(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventPayload", reflect.TypeOf((*MockExternal)(nil).GetEventPayload), arg0)
 }
 
+// GetEpochPubKeys mocks base method
+func (m *MockExternal) GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochPubKeys")
+	ret0, _ := ret[0].(map[idx.ValidatorID]validatorpk.PubKey)
+	ret1, _ := ret[1].(idx.Epoch)
+	return ret0, ret1
+}
+
+// GetEpochPubKeys indicates an expected call of GetEpochPubKeys
+func (mr *MockExternalMockRecorder) GetEpochPubKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochPubKeys", reflect.TypeOf((*MockExternal)(nil).GetEpochPubKeys))
+}
+
 // GetGenesisTime mocks base method
 func (m *MockExternal) GetGenesisTime() inter.Timestamp {
 	m.ctrl.T.Helper()
@@ -328,6 +356,21 @@ func (m *MockTxPool) EXPECT() *MockTxPoolMockRecorder {
 	return m.recorder
 }
 
+// ArrivalTime mocks base method
+func (m *MockTxPool) ArrivalTime(arg0 common.Hash) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArrivalTime", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// ArrivalTime indicates an expected call of ArrivalTime
+func (mr *MockTxPoolMockRecorder) ArrivalTime(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArrivalTime", reflect.TypeOf((*MockTxPool)(nil).ArrivalTime), arg0)
+}
+
 // Count mocks base method
 func (m *MockTxPool) Count() int {
 	m.ctrl.T.Helper()
@@ -356,6 +399,20 @@ func (mr *MockTxPoolMockRecorder) Has(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockTxPool)(nil).Has), arg0)
 }
 
+// IsPriority mocks base method
+func (m *MockTxPool) IsPriority(arg0 *types.Transaction) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPriority", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPriority indicates an expected call of IsPriority
+func (mr *MockTxPoolMockRecorder) IsPriority(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPriority", reflect.TypeOf((*MockTxPool)(nil).IsPriority), arg0)
+}
+
 // Pending mocks base method
 func (m *MockTxPool) Pending() (map[common.Address]types.Transactions, error) {
 	m.ctrl.T.Helper()
@@ -519,3 +576,17 @@ func (mr *MockSignerMockRecorder) Sign(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSigner)(nil).Sign), arg0, arg1)
 }
+
+// Unlocked mocks base method
+func (m *MockSigner) Unlocked(arg0 validatorpk.PubKey) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlocked", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Unlocked indicates an expected call of Unlocked
+func (mr *MockSignerMockRecorder) Unlocked(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlocked", reflect.TypeOf((*MockSigner)(nil).Unlocked), arg0)
+}
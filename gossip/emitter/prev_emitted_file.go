@@ -1,14 +1,21 @@
 package emitter
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
 )
 
+// PrevEmittedEventFilePath returns the default path of the file with the last emitted event ID of a validator
+func PrevEmittedEventFilePath(dir string, validatorID idx.ValidatorID) string {
+	return filepath.Join(dir, fmt.Sprintf("last-%d", validatorID))
+}
+
 func openEventFile(path string, isSyncMode bool) *os.File {
 	const dirPerm = 0700
 	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
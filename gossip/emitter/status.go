@@ -0,0 +1,229 @@
+package emitter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+)
+
+const (
+	DecisionsBufferSize = 100
+)
+
+// Decision is an emit or skip decision made by the emitter.
+// Consecutive skips with the same reason are collapsed into one record.
+type Decision struct {
+	Emitted bool        `json:"emitted"`
+	Event   *hash.Event `json:"event,omitempty"`
+	Reason  string      `json:"reason,omitempty"`
+	First   time.Time   `json:"first"`
+	Last    time.Time   `json:"last"`
+	Count   uint64      `json:"count"`
+}
+
+// decisionsLog is a bounded log of the latest decisions, safe for concurrent use
+type decisionsLog struct {
+	mu    sync.Mutex
+	items []Decision
+	size  int
+}
+
+func newDecisionsLog(size int) *decisionsLog {
+	return &decisionsLog{
+		items: make([]Decision, 0, size),
+		size:  size,
+	}
+}
+
+func (l *decisionsLog) add(emitted bool, id *hash.Event, reason string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if n := len(l.items); n != 0 && !emitted {
+		last := &l.items[n-1]
+		if !last.Emitted && last.Reason == reason {
+			last.Last = now
+			last.Count++
+			return
+		}
+	}
+	if len(l.items) >= l.size {
+		copy(l.items, l.items[1:])
+		l.items = l.items[:len(l.items)-1]
+	}
+	l.items = append(l.items, Decision{
+		Emitted: emitted,
+		Event:   id,
+		Reason:  reason,
+		First:   now,
+		Last:    now,
+		Count:   1,
+	})
+}
+
+// latest returns up to n latest decisions, newest first
+func (l *decisionsLog) latest(n int) []Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n <= 0 || n > len(l.items) {
+		n = len(l.items)
+	}
+	res := make([]Decision, 0, n)
+	for i := len(l.items) - 1; i >= len(l.items)-n; i-- {
+		res = append(res, l.items[i])
+	}
+	return res
+}
+
+// skip records a reason why an event wasn't emitted
+func (em *Emitter) skip(reason string) {
+	em.decisions.add(false, nil, reason)
+}
+
+// emitted records an emitted event
+func (em *Emitter) emitted(id hash.Event) {
+	em.decisions.add(true, &id, "")
+}
+
+// ChallengeStatus is a deadline when a validator should emit an event.
+type ChallengeStatus struct {
+	Validator idx.ValidatorID `json:"validator"`
+	Deadline  time.Time       `json:"deadline"`
+}
+
+// SyncStatus describes whether the emitter is allowed to emit by the doublesign protection.
+type SyncStatus struct {
+	Synced        bool          `json:"synced"`
+	Wait          time.Duration `json:"wait"`
+	Reason        string        `json:"reason,omitempty"`
+	PeersNum      int           `json:"peersNum"`
+	Startup       time.Time     `json:"startup"`
+	LastConnected time.Time     `json:"lastConnected"`
+	P2PSynced     time.Time     `json:"p2pSynced"`
+}
+
+// GasPowerStatus describes gas power left of the latest self-event and the emitter thresholds.
+type GasPowerStatus struct {
+	Left                []uint64 `json:"left"`
+	LimitedTpsThreshold uint64   `json:"limitedTpsThreshold"`
+	NoTxsThreshold      uint64   `json:"noTxsThreshold"`
+	EmergencyThreshold  uint64   `json:"emergencyThreshold"`
+}
+
+// Status is a snapshot of the emitter state, intended for operators.
+type Status struct {
+	Validator          idx.ValidatorID   `json:"validator"`
+	IsValidator        bool              `json:"isValidator"`
+	Running            bool              `json:"running"`
+	Epoch              idx.Epoch         `json:"epoch"`
+	Intervals          EmitIntervals     `json:"intervals"`
+	PrevEmittedAtTime  time.Time         `json:"prevEmittedAtTime"`
+	PrevEmittedAtBlock idx.Block         `json:"prevEmittedAtBlock"`
+	PendingGas         uint64            `json:"pendingGas"`
+	Sync               SyncStatus        `json:"sync"`
+	GasPower           GasPowerStatus    `json:"gasPower"`
+	OfflineValidators  []idx.ValidatorID `json:"offlineValidators"`
+	Challenges         []ChallengeStatus `json:"challenges"`
+	Decisions          []Decision        `json:"decisions"`
+}
+
+// statusSnapshot is a part of the emitter status which is written by the emitting routine and Start/Stop.
+// It's guarded by its own mutex, so Status doesn't need to inspect the emitting routine state.
+type statusSnapshot struct {
+	mu      sync.Mutex
+	running bool
+	sync    SyncStatus
+}
+
+func (s *statusSnapshot) setRunning(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = running
+}
+
+func (s *statusSnapshot) setSync(sync SyncStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sync = sync
+}
+
+func (s *statusSnapshot) get() (running bool, sync SyncStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running, s.sync
+}
+
+// snapshotSyncStatus records the latest result of the doublesign protection check
+func (em *Emitter) snapshotSyncStatus(wait time.Duration, syncErr error) {
+	s := SyncStatus{
+		Synced:        syncErr == nil,
+		Wait:          wait,
+		PeersNum:      em.world.PeersNum(),
+		Startup:       em.syncStatus.startup,
+		LastConnected: em.syncStatus.lastConnected,
+		P2PSynced:     em.syncStatus.p2pSynced,
+	}
+	if syncErr != nil {
+		s.Reason = syncErr.Error()
+	}
+	em.status.setSync(s)
+}
+
+// Status returns the current emitter state and up to decisionsNum latest emit/skip decisions.
+// The sync status is the result of the latest check made by the emitter, it's not re-checked.
+// Safe for concurrent use.
+func (em *Emitter) Status(decisionsNum int) Status {
+	s := Status{
+		GasPower: GasPowerStatus{
+			LimitedTpsThreshold: em.config.LimitedTpsThreshold,
+			NoTxsThreshold:      em.config.NoTxsThreshold,
+			EmergencyThreshold:  em.config.EmergencyThreshold,
+		},
+		OfflineValidators: []idx.ValidatorID{},
+		Challenges:        []ChallengeStatus{},
+		Decisions:         em.decisions.latest(decisionsNum),
+	}
+	s.Running, s.Sync = em.status.get()
+
+	em.world.Lock()
+	defer em.world.Unlock()
+
+	s.Validator = em.config.Validator.ID
+	if !s.Running || em.validators == nil {
+		return s
+	}
+	s.IsValidator = em.isValidator()
+	s.Epoch = em.epoch
+	s.Intervals = em.intervals
+	s.PrevEmittedAtTime = em.prevEmittedAtTime
+	s.PrevEmittedAtBlock = em.prevEmittedAtBlock
+	s.PendingGas = em.pendingGas
+
+	if prevID := em.world.GetLastEvent(em.epoch, em.config.Validator.ID); prevID != nil {
+		if prev := em.world.GetEvent(*prevID); prev != nil {
+			gasPowerLeft := prev.GasPowerLeft()
+			s.GasPower.Left = gasPowerLeft.Gas[:]
+		}
+	}
+
+	for vid := range em.offlineValidators {
+		s.OfflineValidators = append(s.OfflineValidators, vid)
+	}
+	sort.Slice(s.OfflineValidators, func(i, j int) bool {
+		return s.OfflineValidators[i] < s.OfflineValidators[j]
+	})
+	for vid, deadline := range em.challenges {
+		s.Challenges = append(s.Challenges, ChallengeStatus{
+			Validator: vid,
+			Deadline:  deadline,
+		})
+	}
+	sort.Slice(s.Challenges, func(i, j int) bool {
+		return s.Challenges[i].Validator < s.Challenges[j].Validator
+	})
+
+	return s
+}
@@ -0,0 +1,59 @@
+package emitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/hash"
+)
+
+func TestDecisionsLog(t *testing.T) {
+	require := require.New(t)
+
+	l := newDecisionsLog(3)
+	require.Empty(l.latest(0))
+
+	l.add(false, nil, "a")
+	l.add(false, nil, "a")
+	l.add(false, nil, "b")
+	got := l.latest(0)
+	require.Len(got, 2)
+	require.Equal("b", got[0].Reason)
+	require.Equal(uint64(1), got[0].Count)
+	require.Equal("a", got[1].Reason)
+	require.Equal(uint64(2), got[1].Count)
+
+	id := hash.FakeEvent()
+	l.add(true, &id, "")
+	l.add(false, nil, "a")
+	got = l.latest(0)
+	require.Len(got, 3)
+	require.Equal("a", got[0].Reason)
+	require.True(got[1].Emitted)
+	require.Equal(id, *got[1].Event)
+	require.Equal("b", got[2].Reason)
+
+	got = l.latest(1)
+	require.Len(got, 1)
+	require.Equal("a", got[0].Reason)
+}
+
+func TestStatusSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	var s statusSnapshot
+	running, sync := s.get()
+	require.False(running)
+	require.False(sync.Synced)
+
+	s.setRunning(true)
+	s.setSync(SyncStatus{Reason: "not synced", PeersNum: 2})
+	running, sync = s.get()
+	require.True(running)
+	require.Equal("not synced", sync.Reason)
+	require.Equal(2, sync.PeersNum)
+
+	s.setRunning(false)
+	running, _ = s.get()
+	require.False(running)
+}
@@ -66,9 +66,11 @@ func (em *Emitter) isSyncedToEmit() (time.Duration, error) {
 }
 
 func (em *Emitter) logSyncStatus(wait time.Duration, syncErr error) bool {
+	em.snapshotSyncStatus(wait, syncErr)
 	if syncErr == nil {
 		return true
 	}
+	em.skip("not synced to emit: " + syncErr.Error())
 
 	if wait == 0 {
 		em.Periodic.Info(7*time.Second, "Emitting is paused", "reason", syncErr)
@@ -0,0 +1,373 @@
+package emitter
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// PriceTxSelection orders transactions by gas price and nonce
+	PriceTxSelection = "price"
+	// FifoTxSelection orders transactions by arrival time and nonce
+	FifoTxSelection = "fifo"
+)
+
+// TxsIterator is an ordered set of transactions to originate.
+// Transactions of each sender are returned in the nonce order.
+type TxsIterator interface {
+	// Peek returns the next transaction to originate, or nil if there are none left.
+	Peek() *types.Transaction
+	// Shift replaces the current transaction with the next one from the same sender.
+	Shift()
+	// Pop removes the current transaction, *not* replacing it with the next one from the same sender.
+	// It should be used when a transaction cannot be originated, so the following ones won't be executable either.
+	Pop()
+	// Copy returns an independent copy of the iterator.
+	Copy() TxsIterator
+}
+
+// TxSelector is a policy of choosing transactions to originate in a new event.
+// The mandatory checks (epoch rules, gas price limit, gas power, originated txs and
+// validators' round-robin turns) are applied by the emitter on top of the policy.
+type TxSelector interface {
+	// Sort returns an iterator over the pending transactions.
+	// Transactions of each sender are sorted by nonce. The map is reowned by the selector.
+	Sort(pending map[common.Address]types.Transactions) TxsIterator
+}
+
+// TxSelectionConfig is the configuration of transactions selection policy.
+type TxSelectionConfig struct {
+	// Policy is either "price" (default) or "fifo"
+	Policy string
+	// MaxTxsPerSenderPerEvent caps number of txs of a single sender in one event, 0 means no cap
+	MaxTxsPerSenderPerEvent int
+}
+
+// Validate checks the config
+func (c TxSelectionConfig) Validate() error {
+	switch c.Policy {
+	case "", PriceTxSelection, FifoTxSelection:
+	default:
+		return fmt.Errorf("unknown txs selection policy %q", c.Policy)
+	}
+	if c.MaxTxsPerSenderPerEvent < 0 {
+		return fmt.Errorf("MaxTxsPerSenderPerEvent cannot be negative")
+	}
+	return nil
+}
+
+// MakeTxSelector creates a selector according to the config.
+// txTime should return an arrival time of a transaction.
+// isPriority should return true for transactions of the priority lane, may be nil.
+func MakeTxSelector(cfg TxSelectionConfig, signer types.Signer, txTime func(common.Hash) time.Time, isPriority func(*types.Transaction) bool) TxSelector {
+	var selector TxSelector
+	if cfg.Policy == FifoTxSelection {
+		selector = NewFifoTxSelector(txTime)
+	} else {
+		selector = NewPriceTxSelector(signer)
+	}
+	if isPriority != nil {
+		selector = NewPriorityTxSelector(selector, signer, isPriority)
+	}
+	if cfg.MaxTxsPerSenderPerEvent > 0 {
+		selector = NewFairTxSelector(selector, signer, cfg.MaxTxsPerSenderPerEvent)
+	}
+	return selector
+}
+
+/*
+ * Price-and-nonce ordering
+ */
+
+type priceTxSelector struct {
+	signer types.Signer
+}
+
+// NewPriceTxSelector returns a selector which orders transactions by gas price and nonce.
+// It is the default policy.
+func NewPriceTxSelector(signer types.Signer) TxSelector {
+	return &priceTxSelector{signer}
+}
+
+func (s *priceTxSelector) Sort(pending map[common.Address]types.Transactions) TxsIterator {
+	return priceTxs{types.NewTransactionsByPriceAndNonce(s.signer, pending)}
+}
+
+type priceTxs struct {
+	*types.TransactionsByPriceAndNonce
+}
+
+func (t priceTxs) Copy() TxsIterator {
+	return priceTxs{t.TransactionsByPriceAndNonce.Copy()}
+}
+
+/*
+ * FIFO ordering
+ */
+
+type fifoTxSelector struct {
+	txTime func(common.Hash) time.Time
+}
+
+// NewFifoTxSelector returns a selector which orders transactions by arrival time and nonce.
+func NewFifoTxSelector(txTime func(common.Hash) time.Time) TxSelector {
+	return &fifoTxSelector{txTime}
+}
+
+type timedTx struct {
+	tx     *types.Transaction
+	sender common.Address
+	time   time.Time
+}
+
+type txsByTime []timedTx
+
+func (s txsByTime) Len() int { return len(s) }
+func (s txsByTime) Less(i, j int) bool {
+	if !s[i].time.Equal(s[j].time) {
+		return s[i].time.Before(s[j].time)
+	}
+	// tie-breaker to make the order deterministic
+	return bytes.Compare(s[i].tx.Hash().Bytes(), s[j].tx.Hash().Bytes()) < 0
+}
+func (s txsByTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *txsByTime) Push(x interface{}) {
+	*s = append(*s, x.(timedTx))
+}
+
+func (s *txsByTime) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[0 : n-1]
+	return x
+}
+
+func (s *fifoTxSelector) Sort(pending map[common.Address]types.Transactions) TxsIterator {
+	heads := make(txsByTime, 0, len(pending))
+	for from, txs := range pending {
+		if len(txs) == 0 {
+			delete(pending, from)
+			continue
+		}
+		heads = append(heads, timedTx{
+			tx:     txs[0],
+			sender: from,
+			time:   s.txTime(txs[0].Hash()),
+		})
+		pending[from] = txs[1:]
+	}
+	heap.Init(&heads)
+	return &fifoTxs{
+		txs:    pending,
+		heads:  heads,
+		txTime: s.txTime,
+	}
+}
+
+type fifoTxs struct {
+	txs    map[common.Address]types.Transactions
+	heads  txsByTime
+	txTime func(common.Hash) time.Time
+}
+
+func (t *fifoTxs) Peek() *types.Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].tx
+}
+
+func (t *fifoTxs) Shift() {
+	sender := t.heads[0].sender
+	if txs := t.txs[sender]; len(txs) > 0 {
+		// a tx cannot be originated before its predecessor, so it inherits the predecessor's time if it arrived earlier
+		txTime := t.txTime(txs[0].Hash())
+		if txTime.Before(t.heads[0].time) {
+			txTime = t.heads[0].time
+		}
+		t.heads[0], t.txs[sender] = timedTx{txs[0], sender, txTime}, txs[1:]
+		heap.Fix(&t.heads, 0)
+		return
+	}
+	heap.Pop(&t.heads)
+}
+
+func (t *fifoTxs) Pop() {
+	heap.Pop(&t.heads)
+}
+
+func (t *fifoTxs) Copy() TxsIterator {
+	txsCopy := make(map[common.Address]types.Transactions, len(t.txs))
+	for k, v := range t.txs {
+		txsCopy[k] = v
+	}
+	return &fifoTxs{
+		txs:    txsCopy,
+		heads:  append(make(txsByTime, 0, len(t.heads)), t.heads...),
+		txTime: t.txTime,
+	}
+}
+
+/*
+ * Per-sender fairness cap
+ */
+
+type fairTxSelector struct {
+	TxSelector
+	signer types.Signer
+	limit  int
+}
+
+// NewFairTxSelector wraps a selector, allowing no more than limit transactions of a single sender per event.
+func NewFairTxSelector(selector TxSelector, signer types.Signer, limit int) TxSelector {
+	return &fairTxSelector{
+		TxSelector: selector,
+		signer:     signer,
+		limit:      limit,
+	}
+}
+
+func (s *fairTxSelector) Sort(pending map[common.Address]types.Transactions) TxsIterator {
+	return &fairTxs{
+		TxsIterator: s.TxSelector.Sort(pending),
+		signer:      s.signer,
+		limit:       s.limit,
+		shifted:     make(map[common.Address]int),
+	}
+}
+
+type fairTxs struct {
+	TxsIterator
+	signer  types.Signer
+	limit   int
+	shifted map[common.Address]int
+}
+
+func (t *fairTxs) Shift() {
+	sender, _ := types.Sender(t.signer, t.Peek())
+	t.shifted[sender]++
+	if t.shifted[sender] >= t.limit {
+		t.TxsIterator.Pop()
+		return
+	}
+	t.TxsIterator.Shift()
+}
+
+func (t *fairTxs) Copy() TxsIterator {
+	shifted := make(map[common.Address]int, len(t.shifted))
+	for k, v := range t.shifted {
+		shifted[k] = v
+	}
+	return &fairTxs{
+		TxsIterator: t.TxsIterator.Copy(),
+		signer:      t.signer,
+		limit:       t.limit,
+		shifted:     shifted,
+	}
+}
+
+/*
+ * Priority lane
+ */
+
+type priorityTxSelector struct {
+	TxSelector
+	signer     types.Signer
+	isPriority func(*types.Transaction) bool
+}
+
+// NewPriorityTxSelector wraps a selector, originating transactions of the priority lane first.
+// Only the leading priority transactions of each sender are prioritized, the rest follow in the wrapped
+// selector's order after all the prioritized ones.
+func NewPriorityTxSelector(selector TxSelector, signer types.Signer, isPriority func(*types.Transaction) bool) TxSelector {
+	return &priorityTxSelector{
+		TxSelector: selector,
+		signer:     signer,
+		isPriority: isPriority,
+	}
+}
+
+func (s *priorityTxSelector) Sort(pending map[common.Address]types.Transactions) TxsIterator {
+	prioritized := make(map[common.Address]types.Transactions)
+	for from, txs := range pending {
+		lead := 0
+		for lead < len(txs) && s.isPriority(txs[lead]) {
+			lead++
+		}
+		if lead == 0 {
+			continue
+		}
+		prioritized[from] = txs[:lead]
+		if lead == len(txs) {
+			delete(pending, from)
+		} else {
+			pending[from] = txs[lead:]
+		}
+	}
+	return &priorityTxs{
+		prioritized: s.TxSelector.Sort(prioritized),
+		rest:        s.TxSelector.Sort(pending),
+		signer:      s.signer,
+		popped:      make(map[common.Address]bool),
+	}
+}
+
+type priorityTxs struct {
+	prioritized TxsIterator
+	rest        TxsIterator
+	signer      types.Signer
+	// senders whose prioritized transactions were popped, so the rest of their transactions cannot be originated
+	popped map[common.Address]bool
+}
+
+func (t *priorityTxs) Peek() *types.Transaction {
+	if tx := t.prioritized.Peek(); tx != nil {
+		return tx
+	}
+	for tx := t.rest.Peek(); tx != nil; tx = t.rest.Peek() {
+		sender, _ := types.Sender(t.signer, tx)
+		if !t.popped[sender] {
+			return tx
+		}
+		t.rest.Pop()
+	}
+	return nil
+}
+
+func (t *priorityTxs) Shift() {
+	if t.prioritized.Peek() != nil {
+		t.prioritized.Shift()
+		return
+	}
+	t.rest.Shift()
+}
+
+func (t *priorityTxs) Pop() {
+	if tx := t.prioritized.Peek(); tx != nil {
+		sender, _ := types.Sender(t.signer, tx)
+		t.popped[sender] = true
+		t.prioritized.Pop()
+		return
+	}
+	t.rest.Pop()
+}
+
+func (t *priorityTxs) Copy() TxsIterator {
+	popped := make(map[common.Address]bool, len(t.popped))
+	for k, v := range t.popped {
+		popped[k] = v
+	}
+	return &priorityTxs{
+		prioritized: t.prioritized.Copy(),
+		rest:        t.rest.Copy(),
+		signer:      t.signer,
+		popped:      popped,
+	}
+}
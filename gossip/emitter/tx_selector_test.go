@@ -0,0 +1,183 @@
+package emitter
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type testTxs struct {
+	signer  types.Signer
+	pending map[common.Address]types.Transactions
+	times   map[common.Hash]time.Time
+}
+
+// makeTestTxs generates txsPerSender txs for each sender, with a gas price
+// growing with sender index and arrival time decreasing with sender index
+func makeTestTxs(t testing.TB, senders, txsPerSender int) *testTxs {
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	res := &testTxs{
+		signer:  signer,
+		pending: make(map[common.Address]types.Transactions, senders),
+		times:   make(map[common.Hash]time.Time, senders*txsPerSender),
+	}
+	start := time.Now()
+	for s := 0; s < senders; s++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		for n := 0; n < txsPerSender; n++ {
+			tx := signTestTx(t, signer, key, uint64(n), int64(s+1))
+			res.pending[addr] = append(res.pending[addr], tx)
+			res.times[tx.Hash()] = start.Add(time.Duration(senders-s)*time.Second + time.Duration(n)*time.Millisecond)
+		}
+	}
+	return res
+}
+
+func signTestTx(t testing.TB, signer types.Signer, key *ecdsa.PrivateKey, nonce uint64, price int64) *types.Transaction {
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(price), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func (tt *testTxs) txTime(h common.Hash) time.Time {
+	return tt.times[h]
+}
+
+func (tt *testTxs) copyPending() map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions, len(tt.pending))
+	for addr, txs := range tt.pending {
+		pending[addr] = txs
+	}
+	return pending
+}
+
+func drainTxs(it TxsIterator) types.Transactions {
+	var res types.Transactions
+	for tx := it.Peek(); tx != nil; tx = it.Peek() {
+		res = append(res, tx)
+		it.Shift()
+	}
+	return res
+}
+
+func requireNonceOrder(t *testing.T, signer types.Signer, txs types.Transactions) {
+	next := make(map[common.Address]uint64)
+	for _, tx := range txs {
+		sender, _ := types.Sender(signer, tx)
+		require.Equal(t, next[sender], tx.Nonce())
+		next[sender]++
+	}
+}
+
+func TestPriceTxSelector(t *testing.T) {
+	tt := makeTestTxs(t, 5, 3)
+	got := drainTxs(NewPriceTxSelector(tt.signer).Sort(tt.copyPending()))
+	require.Len(t, got, 15)
+	requireNonceOrder(t, tt.signer, got)
+	for i := 1; i < len(got); i++ {
+		require.True(t, got[i-1].GasPrice().Cmp(got[i].GasPrice()) >= 0)
+	}
+}
+
+func TestFifoTxSelector(t *testing.T) {
+	tt := makeTestTxs(t, 5, 3)
+	it := NewFifoTxSelector(tt.txTime).Sort(tt.copyPending())
+	cp := it.Copy()
+	got := drainTxs(it)
+	require.Len(t, got, 15)
+	requireNonceOrder(t, tt.signer, got)
+	for i := 1; i < len(got); i++ {
+		require.False(t, tt.txTime(got[i-1].Hash()).After(tt.txTime(got[i].Hash())))
+	}
+	// the copy isn't affected
+	require.Equal(t, got, drainTxs(cp))
+
+	// a sender is skipped after Pop
+	it = NewFifoTxSelector(tt.txTime).Sort(tt.copyPending())
+	first, _ := types.Sender(tt.signer, it.Peek())
+	it.Pop()
+	for _, tx := range drainTxs(it) {
+		sender, _ := types.Sender(tt.signer, tx)
+		require.NotEqual(t, first, sender)
+	}
+}
+
+func TestFairTxSelector(t *testing.T) {
+	tt := makeTestTxs(t, 5, 3)
+	for _, selector := range []TxSelector{
+		NewPriceTxSelector(tt.signer),
+		NewFifoTxSelector(tt.txTime),
+	} {
+		got := drainTxs(NewFairTxSelector(selector, tt.signer, 2).Sort(tt.copyPending()))
+		require.Len(t, got, 10)
+		requireNonceOrder(t, tt.signer, got)
+	}
+}
+
+func TestPriorityTxSelector(t *testing.T) {
+	tt := makeTestTxs(t, 5, 3)
+	// all txs of the cheapest sender and the first tx of the next one are in the lane
+	isPriority := func(tx *types.Transaction) bool {
+		return tx.GasPrice().Int64() == 1 || (tx.GasPrice().Int64() == 2 && tx.Nonce() == 0)
+	}
+	it := NewPriorityTxSelector(NewPriceTxSelector(tt.signer), tt.signer, isPriority).Sort(tt.copyPending())
+	cp := it.Copy()
+	got := drainTxs(it)
+	require.Len(t, got, 15)
+	requireNonceOrder(t, tt.signer, got)
+	for i, tx := range got {
+		require.Equal(t, i < 4, isPriority(tx), i)
+	}
+	// the copy isn't affected
+	require.Equal(t, got, drainTxs(cp))
+
+	// the rest of sender's txs is skipped after Pop of a prioritized one
+	it = NewPriorityTxSelector(NewPriceTxSelector(tt.signer), tt.signer, isPriority).Sort(tt.copyPending())
+	first, _ := types.Sender(tt.signer, it.Peek())
+	it.Pop()
+	got = drainTxs(it)
+	require.Len(t, got, 12)
+	for _, tx := range got {
+		sender, _ := types.Sender(tt.signer, tx)
+		require.NotEqual(t, first, sender)
+	}
+}
+
+func benchmarkTxSelector(b *testing.B, makeSelector func(tt *testTxs) TxSelector) {
+	tt := makeTestTxs(b, 500, 10)
+	selector := makeSelector(tt)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drainTxs(selector.Sort(tt.copyPending()))
+	}
+}
+
+func BenchmarkPriceTxSelector(b *testing.B) {
+	benchmarkTxSelector(b, func(tt *testTxs) TxSelector {
+		return NewPriceTxSelector(tt.signer)
+	})
+}
+
+func BenchmarkFifoTxSelector(b *testing.B) {
+	benchmarkTxSelector(b, func(tt *testTxs) TxSelector {
+		return NewFifoTxSelector(tt.txTime)
+	})
+}
+
+func BenchmarkFairPriceTxSelector(b *testing.B) {
+	benchmarkTxSelector(b, func(tt *testTxs) TxSelector {
+		return NewFairTxSelector(NewPriceTxSelector(tt.signer), tt.signer, 2)
+	})
+}
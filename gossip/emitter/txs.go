@@ -112,7 +112,11 @@ func (em *Emitter) memorizeTxTimes(txs types.Transactions) {
 	for _, tx := range txs {
 		_, ok := em.txTime.Get(tx.Hash())
 		if !ok {
-			em.txTime.Add(tx.Hash(), now)
+			arrival, ok := em.world.TxPool.ArrivalTime(tx.Hash())
+			if !ok || arrival.After(now) {
+				arrival = now
+			}
+			em.txTime.Add(tx.Hash(), arrival)
 		}
 	}
 }
@@ -129,8 +133,12 @@ func (em *Emitter) getTxTime(txHash common.Hash) time.Time {
 	txTimeI, ok := em.txTime.Get(txHash)
 	if !ok {
 		now := time.Now()
-		em.txTime.Add(txHash, now)
-		return now
+		arrival, ok := em.world.TxPool.ArrivalTime(txHash)
+		if !ok || arrival.After(now) {
+			arrival = now
+		}
+		em.txTime.Add(txHash, arrival)
+		return arrival
 	}
 	return txTimeI.(time.Time)
 }
@@ -150,13 +158,13 @@ func (em *Emitter) isMyTxTurn(txHash common.Hash, sender common.Address, account
 	return validators.GetID(idx.Validator(rounds[roundIndex])) == me
 }
 
-func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce) {
+func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted TxsIterator) {
 	maxGasUsed := em.maxGasPowerToUse(e)
 	if maxGasUsed <= e.GasPowerUsed() {
 		return
 	}
 
-	// sort transactions by price and nonce
+	// transactions are ordered by the TxSelector policy
 	rules := em.world.GetRules()
 	softGasPriceLimit := em.world.GetRecommendedGasPrice()
 	for tx := sorted.Peek(); tx != nil; tx = sorted.Peek() {
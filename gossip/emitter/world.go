@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
 	"github.com/zilionixx/go-zilionixx/valkeystore"
 	"github.com/zilionixx/go-zilionixx/vecmt"
 )
@@ -32,6 +34,8 @@ type (
 		Check(e *inter.EventPayload, parents inter.Events) error
 		Process(*inter.EventPayload) error
 		Broadcast(*inter.EventPayload)
+		// OnValidatorKeyRotated is called when the emitter switches to a rotated validator key
+		OnValidatorKeyRotated(validatorpk.PubKey)
 		Build(*inter.MutableEventPayload, func()) error
 		DagIndex() *vecmt.Index
 
@@ -57,6 +61,7 @@ type (
 type Reader interface {
 	GetLatestBlockIndex() idx.Block
 	GetEpochValidators() (*pos.Validators, idx.Epoch)
+	GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch)
 	GetEvent(hash.Event) *inter.Event
 	GetEventPayload(hash.Event) *inter.EventPayload
 	GetLastEvent(epoch idx.Epoch, from idx.ValidatorID) *hash.Event
@@ -80,4 +85,11 @@ type TxPool interface {
 
 	// Count returns the total number of transactions
 	Count() int
+
+	// ArrivalTime returns the time when the transaction was first seen by the pool,
+	// which may be earlier than the node start if the transaction was restored.
+	ArrivalTime(hash common.Hash) (time.Time, bool)
+
+	// IsPriority returns true if the transaction belongs to the priority lane.
+	IsPriority(tx *types.Transaction) bool
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
 	"github.com/zilionixx/go-zilionixx/utils/wgmutex"
 	"github.com/zilionixx/go-zilionixx/valkeystore"
 	"github.com/zilionixx/go-zilionixx/vecmt"
@@ -40,6 +41,12 @@ func (ew *emitterWorld) Broadcast(emitted *inter.EventPayload) {
 	ew.s.feed.newEmittedEvent.Send(emitted)
 }
 
+func (ew *emitterWorld) OnValidatorKeyRotated(pubkey validatorpk.PubKey) {
+	// called under the engine lock, which guards the validator config of the service
+	ew.s.config.Emitter.Validator.PubKey = pubkey
+	ew.s.Log.Info("Validator key is rotated", "id", ew.s.config.Emitter.Validator.ID, "pubkey", pubkey.String())
+}
+
 func (ew *emitterWorld) Build(e *inter.MutableEventPayload, onIndexed func()) error {
 	return ew.s.buildEvent(e, onIndexed)
 }
@@ -67,6 +74,16 @@ func (ew *emitterWorld) GetHeads(epoch idx.Epoch) hash.Events {
 func (ew *emitterWorld) GetLastEvent(epoch idx.Epoch, from idx.ValidatorID) *hash.Event {
 	return ew.Store.GetLastEvent(epoch, from)
 }
+
+func (ew *emitterWorld) GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch) {
+	es := ew.Store.GetEpochState()
+	pubkeys := make(map[idx.ValidatorID]validatorpk.PubKey, len(es.ValidatorProfiles))
+	for id, profile := range es.ValidatorProfiles {
+		pubkeys[id] = profile.PubKey
+	}
+	return pubkeys, es.Epoch
+}
+
 func (ew *emitterWorld) GetRecommendedGasPrice() *big.Int {
 	return ew.s.GetEvmStateReader().RecommendedMinGasPrice()
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/zilionixx/zilion-base/inter/idx"
 	"github.com/zilionixx/zilion-base/inter/pos"
 
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
 	"github.com/zilionixx/go-zilionixx/ethapi"
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/gossip/blockproc"
@@ -31,6 +32,7 @@ import (
 	"github.com/zilionixx/go-zilionixx/inter/drivertype"
 	"github.com/zilionixx/go-zilionixx/topicsdb"
 	"github.com/zilionixx/go-zilionixx/tracing"
+	"github.com/zilionixx/go-zilionixx/utils/dbstats"
 )
 
 // EthAPIBackend implements ethapi.Backend.
@@ -39,6 +41,7 @@ type EthAPIBackend struct {
 	svc                 *Service
 	state               *EvmStateReader
 	allowUnprotectedTxs bool
+	txSubmitLimiter     *ethapi.TxSubmitLimiter
 }
 
 // ChainConfig returns the active chain configuration.
@@ -46,6 +49,18 @@ func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
 	return b.svc.store.GetRules().EvmChainConfig()
 }
 
+func (b *EthAPIBackend) Upgrades() zilionixx.Upgrades {
+	return b.svc.store.GetRules().Upgrades
+}
+
+// BlockUpgrades returns the upgrades which were applied to the block.
+func (b *EthAPIBackend) BlockUpgrades(number uint64) zilionixx.Upgrades {
+	if rules := b.svc.store.GetBlockRules(idx.Block(number)); rules != nil {
+		return rules.Upgrades
+	}
+	return b.Upgrades()
+}
+
 func (b *EthAPIBackend) CurrentBlock() *evmcore.EvmBlock {
 	return b.state.CurrentBlock()
 }
@@ -319,6 +334,14 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return err
 }
 
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction) error {
+	err := b.svc.txpool.AddPrivate(signedTx)
+	if err == nil {
+		tracing.StartTx(signedTx.Hash(), "EthAPIBackend.SendPrivateTx()")
+	}
+	return err
+}
+
 func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) notify.Subscription {
 	return b.svc.feed.SubscribeNewLogs(ch)
 }
@@ -347,6 +370,25 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 	return b.svc.txpool.Get(hash)
 }
 
+// TxIndexTail returns the first block whose transactions are indexed,
+// and true if transactions of the older blocks are removed from the index.
+func (b *EthAPIBackend) TxIndexTail() (uint64, bool) {
+	tail := b.svc.store.GetTxIndexTail()
+	if tail == nil {
+		return 0, false
+	}
+	return uint64(*tail), true
+}
+
+// TxPruned returns the block of the transaction and true if the transaction is removed from the index.
+func (b *EthAPIBackend) TxPruned(txHash common.Hash) (uint64, bool) {
+	block := b.svc.store.GetTxPruned(txHash)
+	if block == nil {
+		return 0, false
+	}
+	return uint64(*block), true
+}
+
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
 	if !b.svc.config.TxIndex {
 		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
@@ -362,6 +404,14 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 		tx = b.svc.store.evm.GetTx(txHash)
 	} else {
 		event := b.svc.store.GetEventPayload(position.Event)
+		if event == nil {
+			// the event is pruned, its transactions are kept separately
+			tx = b.svc.store.getPrunedEventTx(txHash)
+			if tx == nil {
+				return nil, 0, 0, fmt.Errorf("transaction's event is not found, event=%s, txid=%s", position.Event.String(), txHash.String())
+			}
+			return tx, uint64(position.Block), uint64(position.BlockOffset), nil
+		}
 		if position.EventOffset > uint32(event.Txs().Len()) {
 			return nil, 0, 0, fmt.Errorf("transactions index is corrupted (offset is larger than number of txs in event), event=%s, txid=%s, block=%d, offset=%d, txs_num=%d",
 				position.Event.String(),
@@ -388,6 +438,18 @@ func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.svc.txpool.Content()
 }
 
+func (b *EthAPIBackend) TxPoolTxStatus(hash common.Hash) (evmcore.TxStatus, *evmcore.DroppedTx) {
+	return b.svc.txpool.TxStatus(hash)
+}
+
+func (b *EthAPIBackend) TxPoolAccountStatus(addr common.Address) evmcore.AccountStatus {
+	return b.svc.txpool.AccountStatus(addr)
+}
+
+func (b *EthAPIBackend) SubscribeDroppedTxsNotify(ch chan<- evmcore.DroppedTxsNotify) notify.Subscription {
+	return b.svc.txpool.SubscribeDroppedTxsNotify(ch)
+}
+
 func (b *EthAPIBackend) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) notify.Subscription {
 	return b.svc.txpool.SubscribeNewTxsNotify(ch)
 }
@@ -418,6 +480,16 @@ func (b *EthAPIBackend) ChainDb() ethdb.Database {
 	return b.svc.store.evm.EvmTable()
 }
 
+// DBStats returns the keys statistics of the tables of the gossip and consensus databases.
+func (b *EthAPIBackend) DBStats(ctx context.Context) ([]dbstats.Stats, error) {
+	return b.svc.store.DBStats(ctx)
+}
+
+// DBCompact compacts the table of the gossip or consensus database, or the whole database if the table is empty.
+func (b *EthAPIBackend) DBCompact(db, table string) error {
+	return b.svc.store.CompactDB(db, table)
+}
+
 func (b *EthAPIBackend) AccountManager() *accounts.Manager {
 	return b.svc.AccountManager()
 }
@@ -438,10 +510,18 @@ func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.svc.config.RPCTxFeeCap
 }
 
+func (b *EthAPIBackend) TxSubmitLimiter() *ethapi.TxSubmitLimiter {
+	return b.txSubmitLimiter
+}
+
 func (b *EthAPIBackend) EvmLogIndex() *topicsdb.Index {
 	return b.svc.store.evm.EvmLogs()
 }
 
+func (b *EthAPIBackend) EvmBloomIndex() *bloomsdb.Index {
+	return b.svc.store.evm.EvmBlooms()
+}
+
 // CurrentEpoch returns current epoch number.
 func (b *EthAPIBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
 	return b.svc.store.GetEpoch()
@@ -0,0 +1,34 @@
+package gossip
+
+// startEventsPruner starts the background job which prunes events of old epochs.
+func (s *Service) startEventsPruner() {
+	if !s.store.eventsPruning() {
+		return
+	}
+	s.startPeriodicJob(s.store.cfg.EventsPruner.Period, s.pruneEvents)
+}
+
+// pruneEvents prunes the historical events in batches under the engine lock.
+func (s *Service) pruneEvents() {
+	cfg := s.store.cfg.EventsPruner
+	for {
+		s.engineMu.Lock()
+		epoch := s.store.GetEpoch()
+		if epoch <= cfg.Epochs {
+			s.engineMu.Unlock()
+			return
+		}
+		pruned := s.store.pruneEvents(epoch-cfg.Epochs, cfg.BatchSize)
+		s.engineMu.Unlock()
+		if pruned == 0 {
+			return
+		}
+		s.Log.Info("Pruned historical events", "num", pruned, "before epoch", epoch-cfg.Epochs)
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/gossip/gasprice"
 	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
 )
 
 var (
@@ -48,6 +49,9 @@ func (r *EvmStateReader) RecommendedMinGasPrice() *big.Int {
 	est.Div(est, big4)
 
 	min := r.MinGasPrice()
+	if baseFee := r.CurrentHeader().BaseFee; baseFee != nil && baseFee.Cmp(min) > 0 {
+		min = baseFee
+	}
 	if min.Cmp(est) > 0 {
 		return min
 	}
@@ -126,7 +130,13 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 		for _, id := range block.Events {
 			e := r.store.GetEventPayload(id)
 			if e == nil {
-				log.Crit("Block event not found", "event", id.String())
+				// the event may be pruned, then only its transactions are kept
+				txs, ok := r.store.getPrunedEventTxs(id)
+				if !ok {
+					log.Crit("Block event not found", "event", id.String())
+					continue
+				}
+				transactions = append(transactions, txs...)
 				continue
 			}
 			transactions = append(transactions, e.Txs()...)
@@ -138,10 +148,20 @@ func (r *EvmStateReader) getBlock(h hash.Event, n idx.Block, readTxs bool) *evmc
 	}
 
 	var prev hash.Event
+	var prevHeader *evmcore.EvmHeader
 	if n != 0 {
-		prev = r.store.GetBlock(n - 1).Atropos
+		prevBlock := r.store.GetBlock(n - 1)
+		prev = prevBlock.Atropos
+		prevHeader = evmcore.ToEvmHeader(prevBlock, n-1, hash.Event{})
 	}
 	evmHeader := evmcore.ToEvmHeader(block, n, prev)
+	// the base fee is derived according to the rules which were applied to the block
+	rules := r.store.GetBlockRules(n)
+	if rules == nil {
+		current := r.store.GetRules()
+		rules = &current
+	}
+	evmHeader.BaseFee = evmcore.CalcBaseFee(*rules, prevHeader, block.Time)
 
 	var evmBlock *evmcore.EvmBlock
 	if readTxs {
@@ -164,3 +184,7 @@ func (r *EvmStateReader) StateAt(root common.Hash) (*state.StateDB, error) {
 func (r *EvmStateReader) TxExists(txid common.Hash) bool {
 	return r.store.EvmStore().GetTxPosition(txid) != nil
 }
+
+func (r *EvmStateReader) Upgrades() zilionixx.Upgrades {
+	return r.store.GetRules().Upgrades
+}
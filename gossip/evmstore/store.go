@@ -17,6 +17,8 @@ import (
 	"github.com/zilionixx/zilion-base/kvdb/table"
 	"github.com/zilionixx/zilion-base/utils/wlru"
 
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
+	"github.com/zilionixx/go-zilionixx/gossip/freezer"
 	"github.com/zilionixx/go-zilionixx/logger"
 	"github.com/zilionixx/go-zilionixx/topicsdb"
 	"github.com/zilionixx/go-zilionixx/utils/adapters/kvdb2ethdb"
@@ -35,11 +37,14 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
-
-		Evm      ethdb.Database
-		EvmState state.Database
-		EvmLogs  *topicsdb.Index
-		Snaps    *snapshot.Tree
+		TxIndexTail kvdb.Store `table:"T"`
+		PrunedTxs   kvdb.Store `table:"U"`
+
+		Evm       ethdb.Database
+		EvmState  state.Database
+		EvmLogs   *topicsdb.Index
+		EvmBlooms *bloomsdb.Index
+		Snaps     *snapshot.Tree
 	}
 
 	cache struct {
@@ -52,6 +57,9 @@ type Store struct {
 		Inc sync.Mutex
 	}
 
+	// ancientReceipts is a freezer table of receipts which are moved out of the DB, or nil
+	ancientReceipts *freezer.Table
+
 	rlp rlpstore.Helper
 
 	snaps *snapshot.Tree // Snapshot tree for fast trie leaf access
@@ -77,6 +85,7 @@ func NewStore(mainDB kvdb.Store, cfg StoreConfig) *Store {
 		Preimages: cfg.EnablePreimageRecording,
 	})
 	s.table.EvmLogs = topicsdb.New(table.New(s.mainDB, []byte("L")))
+	s.table.EvmBlooms = bloomsdb.New(table.New(s.mainDB, []byte("F")), bloomsdb.DefaultSectionSize)
 
 	s.initCache()
 
@@ -142,6 +151,10 @@ func (s *Store) EvmLogs() *topicsdb.Index {
 	return s.table.EvmLogs
 }
 
+func (s *Store) EvmBlooms() *bloomsdb.Index {
+	return s.table.EvmBlooms
+}
+
 /*
  * Utils:
  */
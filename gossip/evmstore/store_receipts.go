@@ -8,6 +8,8 @@ import (
 	"github.com/zilionixx/zilion-base/inter/idx"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/zilionixx/go-zilionixx/gossip/freezer"
 )
 
 // SetReceipts stores transaction receipts.
@@ -49,16 +51,16 @@ func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 		}
 	}
 
-	buf, err := s.table.Receipts.Get(n.Bytes())
-	if err != nil {
-		s.Log.Crit("Failed to get key-value", "err", err)
-	}
+	buf := s.GetRawReceiptsRLP(n)
 	if buf == nil {
+		buf = s.getAncientReceiptsRLP(n)
+	}
+	if len(buf) == 0 {
 		return nil
 	}
 
 	var receiptsStorage *[]*types.ReceiptForStorage
-	err = rlp.DecodeBytes(buf, &receiptsStorage)
+	err := rlp.DecodeBytes(buf, &receiptsStorage)
 	if err != nil {
 		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
 	}
@@ -78,3 +80,35 @@ func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 
 	return receipts
 }
+
+// GetRawReceiptsRLP returns RLP of transaction receipts which are stored in the DB.
+func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
+	buf, err := s.table.Receipts.Get(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return buf
+}
+
+// DelReceipts deletes transaction receipts from the DB.
+func (s *Store) DelReceipts(n idx.Block) {
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// SetAncientReceipts sets the freezer table of receipts which are moved out of the DB.
+func (s *Store) SetAncientReceipts(t *freezer.Table) {
+	s.ancientReceipts = t
+}
+
+func (s *Store) getAncientReceiptsRLP(n idx.Block) rlp.RawValue {
+	if s.ancientReceipts == nil || !s.ancientReceipts.Has(uint64(n)) {
+		return nil
+	}
+	buf, err := s.ancientReceipts.Get(uint64(n))
+	if err != nil {
+		s.Log.Crit("Failed to read ancient receipts", "err", err)
+	}
+	return buf
+}
@@ -43,3 +43,55 @@ func (s *Store) GetTxPosition(txid common.Hash) *TxPosition {
 
 	return txPosition
 }
+
+// DelTxPosition deletes transaction position from the index.
+func (s *Store) DelTxPosition(txid common.Hash) {
+	if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.TxPositions.Remove(txid.String())
+}
+
+// SetTxIndexTail stores the first block whose transactions are indexed.
+func (s *Store) SetTxIndexTail(n idx.Block) {
+	if err := s.table.TxIndexTail.Put([]byte("t"), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetTxIndexTail returns the first block whose transactions are indexed,
+// or nil if transactions weren't unindexed.
+func (s *Store) GetTxIndexTail() *idx.Block {
+	buf, err := s.table.TxIndexTail.Get([]byte("t"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return nil
+	}
+	n := idx.BytesToBlock(buf)
+	return &n
+}
+
+// SetTxPruned marks the transaction of the block as removed from the index.
+// Only the block number is kept, which is smaller than the position.
+func (s *Store) SetTxPruned(txid common.Hash, block idx.Block) {
+	if err := s.table.PrunedTxs.Put(txid.Bytes(), block.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetTxPruned returns the block of the transaction if it was removed from the index, or nil otherwise.
+func (s *Store) GetTxPruned(txid common.Hash) *idx.Block {
+	buf, err := s.table.PrunedTxs.Get(txid.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return nil
+	}
+	n := idx.BytesToBlock(buf)
+	return &n
+}
@@ -0,0 +1,95 @@
+package gossip
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+// extraDataQueue holds metadata records waiting to be published in the next self-event
+type extraDataQueue struct {
+	mu      sync.Mutex
+	pending inter.ExtraData
+}
+
+// push queues the record, replacing a not published record with the same type and key
+func (q *extraDataQueue) push(r inter.ExtraRecord) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending.Set(r)
+}
+
+func (q *extraDataQueue) records() []inter.ExtraRecord {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append(make([]inter.ExtraRecord, 0, len(q.pending.Records)), q.pending.Records...)
+}
+
+// remove deletes the published records, unless they were replaced in the meantime
+func (q *extraDataQueue) remove(published []inter.ExtraRecord) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, p := range published {
+		for i, r := range q.pending.Records {
+			if r.Type == p.Type && bytes.Equal(r.Key, p.Key) && bytes.Equal(r.Value, p.Value) {
+				q.pending.Records = append(q.pending.Records[:i], q.pending.Records[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// PublishExtraRecord queues a metadata record to be published in the next self-event
+func (s *Service) PublishExtraRecord(r inter.ExtraRecord) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	s.extraQueue.push(r)
+	return nil
+}
+
+// buildExtra returns the typed extra data for a new event.
+// The node version and gas price vote are published in the first event of the epoch.
+// Queued records which don't fit into maxSize are left for the next events.
+// Queued records stay in the queue until the event is processed, see onSelfEventProcessed.
+func (s *Service) buildExtra(seq idx.Event, maxSize uint32) []byte {
+	var (
+		extra inter.ExtraData
+		raw   []byte
+	)
+	add := func(r inter.ExtraRecord) bool {
+		candidate := inter.ExtraData{Records: append([]inter.ExtraRecord{}, extra.Records...)}
+		candidate.Set(r)
+		b, err := candidate.MarshalBinary()
+		if err != nil || uint32(len(b)) > maxSize {
+			return false
+		}
+		extra, raw = candidate, b
+		return true
+	}
+
+	if seq <= 1 {
+		if len(s.config.Emitter.VersionToPublish) > 0 {
+			add(inter.NewSoftwareVersionRecord("v-" + s.config.Emitter.VersionToPublish))
+		}
+		if s.config.Emitter.GasPriceVote != nil {
+			add(inter.NewGasPriceVoteRecord(s.config.Emitter.GasPriceVote))
+		}
+	}
+	for _, r := range s.extraQueue.records() {
+		add(r)
+	}
+	return raw
+}
+
+// onSelfEventProcessed dequeues the metadata records which are published in the processed self-event
+func (s *Service) onSelfEventProcessed(e *inter.EventPayload) {
+	extra, err := inter.ParseExtra(e.Extra())
+	if extra == nil || err != nil {
+		return
+	}
+	s.extraQueue.remove(extra.Records)
+}
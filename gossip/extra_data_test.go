@@ -0,0 +1,39 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+func TestExtraQueueDequeuedAfterProcessing(t *testing.T) {
+	require := require.New(t)
+
+	s := &Service{}
+	oracle := inter.NewOracleValueRecord("feed", big.NewInt(1))
+	require.NoError(s.PublishExtraRecord(oracle))
+
+	// records stay queued if the built event isn't processed
+	raw := s.buildExtra(2, 1000)
+	require.NotEmpty(raw)
+	require.Equal(raw, s.buildExtra(2, 1000))
+	require.Len(s.extraQueue.records(), 1)
+
+	// records are dequeued after the event is processed
+	e := inter.MutableEventPayload{}
+	e.SetExtra(raw)
+	s.onSelfEventProcessed(e.Build())
+	require.Empty(s.extraQueue.records())
+	require.Empty(s.buildExtra(2, 1000))
+
+	// a record replaced in the meantime stays queued
+	require.NoError(s.PublishExtraRecord(oracle))
+	raw = s.buildExtra(2, 1000)
+	require.NoError(s.PublishExtraRecord(inter.NewOracleValueRecord("feed", big.NewInt(2))))
+	e.SetExtra(raw)
+	s.onSelfEventProcessed(e.Build())
+	require.Len(s.extraQueue.records(), 1)
+}
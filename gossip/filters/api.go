@@ -32,6 +32,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/zilionixx/go-zilionixx/evmcore"
 )
 
 var (
@@ -55,12 +57,15 @@ type Config struct {
 	IndexedLogsBlockRangeLimit idx.Block
 	// Block range limit for logs search (unindexed).
 	UnindexedLogsBlockRangeLimit idx.Block
+	// Max number of logs returned by a logs search, and max size of a logs page (0 = unlimited).
+	LogsMaxResults int
 }
 
 func DefaultConfig() Config {
 	return Config{
 		IndexedLogsBlockRangeLimit:   999999999999999999,
 		UnindexedLogsBlockRangeLimit: 100,
+		LogsMaxResults:               10000,
 	}
 }
 
@@ -182,6 +187,48 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// RPCDroppedTx is a notification about a transaction dropped from the transaction pool
+type RPCDroppedTx struct {
+	Hash   common.Hash `json:"hash"`
+	Reason string      `json:"reason"`
+}
+
+// NewDroppedTransactions creates a subscription that is triggered each time a transaction
+// is dropped from the transaction pool without being included, along with the drop reason.
+func (api *PublicFilterAPI) NewDroppedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		drops := make(chan []*evmcore.DroppedTx, 128)
+		droppedTxSub := api.events.SubscribeDroppedTxs(drops)
+
+		for {
+			select {
+			case batch := <-drops:
+				for _, d := range batch {
+					_ = notifier.Notify(rpcSub.ID, &RPCDroppedTx{
+						Hash:   d.Tx.Hash(),
+						Reason: d.Reason.String(),
+					})
+				}
+			case <-rpcSub.Err():
+				droppedTxSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				droppedTxSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
@@ -338,23 +385,7 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
 func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
-	var filter *Filter
-	if crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, api.config, *crit.BlockHash, crit.Addresses, crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics)
-	}
+	filter := newCriteriaFilter(api.backend, api.config, crit)
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
@@ -363,6 +394,25 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	return returnLogs(logs), err
 }
 
+// newCriteriaFilter constructs a single-shot filter of the criteria.
+func newCriteriaFilter(backend Backend, cfg Config, crit FilterCriteria) *Filter {
+	if crit.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		return NewBlockFilter(backend, cfg, *crit.BlockHash, crit.Addresses, crit.Topics)
+	}
+	// Convert the RPC block numbers into internal representations
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	// Construct the range filter
+	return NewRangeFilter(backend, cfg, begin, end, crit.Addresses, crit.Topics)
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_uninstallfilter
@@ -393,23 +443,7 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 		return nil, fmt.Errorf("filter not found")
 	}
 
-	var filter *Filter
-	if f.crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, api.config, *f.crit.BlockHash, f.crit.Addresses, f.crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if f.crit.FromBlock != nil {
-			begin = f.crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if f.crit.ToBlock != nil {
-			end = f.crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, api.config, begin, end, f.crit.Addresses, f.crit.Topics)
-	}
+	filter := newCriteriaFilter(api.backend, api.config, f.crit)
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
@@ -0,0 +1,67 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/zilionixx/go-zilionixx/topicsdb"
+)
+
+// LogsPage is a page of logs, with the cursor of the next page.
+type LogsPage struct {
+	Logs   []*types.Log   `json:"logs"`
+	Cursor *hexutil.Bytes `json:"cursor"` // nil if there are no more logs
+}
+
+// PublicLogsAPI offers the paginated logs search.
+type PublicLogsAPI struct {
+	config  Config
+	backend Backend
+}
+
+// NewPublicLogsAPI returns a new PublicLogsAPI instance.
+func NewPublicLogsAPI(backend Backend, cfg Config) *PublicLogsAPI {
+	return &PublicLogsAPI{
+		config:  cfg,
+		backend: backend,
+	}
+}
+
+// GetLogsPage returns at most limit logs matching the given argument, which follow the cursor
+// (the first page is returned if the cursor is empty). Logs are ordered by block, transaction hash and log index.
+// The returned cursor is opaque, it's passed to get the next page.
+func (api *PublicLogsAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, cursor *hexutil.Bytes, limit int) (*LogsPage, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	if api.config.LogsMaxResults != 0 && limit > api.config.LogsMaxResults {
+		return nil, fmt.Errorf("limit is too large, the max is %d", api.config.LogsMaxResults)
+	}
+	var after *topicsdb.ID
+	if cursor != nil && len(*cursor) != 0 {
+		if len(*cursor) != len(topicsdb.ID{}) {
+			return nil, errors.New("invalid cursor")
+		}
+		after = &topicsdb.ID{}
+		copy(after[:], *cursor)
+	}
+
+	filter := newCriteriaFilter(api.backend, api.config, crit)
+	logs, next, err := filter.LogsPage(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LogsPage{
+		Logs: returnLogs(logs),
+	}
+	if next != nil {
+		b := hexutil.Bytes(next.Bytes())
+		page.Cursor = &b
+	}
+	return page, nil
+}
@@ -17,10 +17,12 @@
 package filters
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -31,10 +33,14 @@ import (
 	"github.com/zilionixx/zilion-base/hash"
 	"github.com/zilionixx/zilion-base/inter/idx"
 
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/topicsdb"
 )
 
+// errPageFull stops a search of logs once the page is full
+var errPageFull = errors.New("page is full")
+
 type Backend interface {
 	ChainDb() ethdb.Database
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*evmcore.EvmHeader, error)
@@ -45,8 +51,10 @@ type Backend interface {
 	SubscribeNewBlockEvent(ch chan<- evmcore.ChainHeadNotify) notify.Subscription
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) notify.Subscription
 	SubscribeLogsEvent(ch chan<- []*types.Log) notify.Subscription
+	SubscribeDroppedTxsNotify(ch chan<- evmcore.DroppedTxsNotify) notify.Subscription
 
 	EvmLogIndex() *topicsdb.Index
+	EvmBloomIndex() *bloomsdb.Index
 }
 
 // Filter can be used to retrieve and filter logs.
@@ -109,23 +117,17 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
-		return f.blockLogs(ctx, header.Hash)
+		logs, err := f.blockLogs(ctx, header.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return logs, f.checkResultsLimit(len(logs))
 	}
 	// Figure out the limits of the filter range
-	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
-	if header == nil {
+	begin, end, ok := f.blocksRange(ctx)
+	if !ok {
 		return nil, nil
 	}
-	head := idx.Block(header.Number.Uint64())
-
-	begin := idx.Block(f.begin)
-	if f.begin < 0 {
-		begin = head
-	}
-	end := idx.Block(f.end)
-	if f.end < 0 {
-		end = head
-	}
 	if begin > end {
 		return []*types.Log{}, nil
 	}
@@ -137,12 +139,72 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 }
 
-// indexedLogs returns the logs matching the filter criteria based on topics index.
-func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
-	if end-begin > f.config.IndexedLogsBlockRangeLimit {
-		return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+// LogsPage returns at most limit logs which follow the cursor (nil cursor is the filter start),
+// ordered by their topicsdb IDs. The returned cursor is the ID of the last log if the page is full,
+// and it's nil if there are no more logs.
+func (f *Filter) LogsPage(ctx context.Context, cursor *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID, error) {
+	if f.block != common.Hash(hash.Zero) {
+		header, err := f.backend.HeaderByHash(ctx, f.block)
+		if err != nil {
+			return nil, nil, err
+		}
+		if header == nil {
+			return nil, nil, errors.New("unknown block")
+		}
+		logs, err := f.blockLogs(ctx, header.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		logs, next := pageLogs(logs, cursor, limit)
+		return logs, next, nil
+	}
+	begin, end, ok := f.blocksRange(ctx)
+	if !ok {
+		return nil, nil, nil
+	}
+	if cursor != nil && idx.Block(cursor.BlockNumber()) > begin {
+		begin = idx.Block(cursor.BlockNumber())
+	}
+	if begin > end {
+		return []*types.Log{}, nil, nil
+	}
+
+	if isEmpty(f.topics) && len(f.addresses) == 0 {
+		return f.unindexedLogsPage(ctx, begin, end, cursor, limit)
+	} else {
+		return f.indexedLogsPage(ctx, begin, end, cursor, limit)
+	}
+}
+
+// blocksRange returns the limits of the filter range, or false if there are no blocks.
+func (f *Filter) blocksRange(ctx context.Context) (begin, end idx.Block, ok bool) {
+	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil {
+		return
+	}
+	head := idx.Block(header.Number.Uint64())
+
+	begin = idx.Block(f.begin)
+	if f.begin < 0 {
+		begin = head
+	}
+	end = idx.Block(f.end)
+	if f.end < 0 {
+		end = head
 	}
+	return begin, end, true
+}
 
+// checkResultsLimit returns an error if the number of found logs exceeds the limit.
+func (f *Filter) checkResultsLimit(found int) error {
+	if f.config.LogsMaxResults != 0 && found > f.config.LogsMaxResults {
+		return fmt.Errorf("query returned more than %d results, narrow the blocks range or use zilionixx_getLogsPage", f.config.LogsMaxResults)
+	}
+	return nil
+}
+
+// pattern returns the topics index pattern of the filter criteria.
+func (f *Filter) pattern() [][]common.Hash {
 	addresses := make([]common.Hash, len(f.addresses))
 	for i, addr := range f.addresses {
 		addresses[i] = addr.Hash()
@@ -151,10 +213,104 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 	pattern := make([][]common.Hash, 1, len(f.topics)+1)
 	pattern[0] = addresses
 	pattern = append(pattern, f.topics...)
+	return pattern
+}
+
+// checkRangeLimit counts the blocks searched in the topics index against the range limit.
+// The blocks matched by the bloom-bits index are counted too, so the limit can't be
+// bypassed by a criteria which matches most of the indexed blocks.
+func (f *Filter) checkRangeLimit(searched *idx.Block, from, to idx.Block) error {
+	*searched += to - from + 1
+	if *searched > f.config.IndexedLogsBlockRangeLimit+1 {
+		return fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+	}
+	return nil
+}
+
+// indexedLogs returns the logs matching the filter criteria based on topics index.
+// Blocks which are covered by the bloom-bits index are pruned by blooms first,
+// so only the matched ones are searched in the topics index.
+func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
+	var (
+		pattern  = f.pattern()
+		logs     []*types.Log
+		tooMany  error
+		searched idx.Block
+	)
+	find := func(from, to idx.Block) error {
+		if err := f.checkRangeLimit(&searched, from, to); err != nil {
+			return err
+		}
+		err := f.backend.EvmLogIndex().ForEachInBlocks(ctx, from, to, pattern, func(l *types.Log) bool {
+			logs = append(logs, l)
+			tooMany = f.checkResultsLimit(len(logs))
+			return tooMany == nil
+		})
+		if err != nil {
+			return err
+		}
+		return tooMany
+	}
 
-	logs, err := f.backend.EvmLogIndex().FindInBlocks(ctx, begin, end, pattern)
+	if blooms := f.backend.EvmBloomIndex(); blooms != nil {
+		next, err := blooms.Match(ctx, begin, end, f.addresses, f.topics, find)
+		if err != nil {
+			return nil, err
+		}
+		if next > end {
+			return logs, nil
+		}
+		begin = next
+	}
 
-	return logs, err
+	if err := find(begin, end); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// indexedLogsPage returns a page of the logs matching the filter criteria based on topics index.
+func (f *Filter) indexedLogsPage(ctx context.Context, begin, end idx.Block, cursor *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID, error) {
+	var (
+		pattern  = f.pattern()
+		logs     []*types.Log
+		next     *topicsdb.ID
+		searched idx.Block
+	)
+	find := func(from, to idx.Block) error {
+		if err := f.checkRangeLimit(&searched, from, to); err != nil {
+			return err
+		}
+		var (
+			found []*types.Log
+			err   error
+		)
+		found, next, err = f.backend.EvmLogIndex().FindInBlocksPage(ctx, from, to, pattern, cursor, limit-len(logs))
+		logs = append(logs, found...)
+		if err == nil && next != nil {
+			return errPageFull
+		}
+		return err
+	}
+
+	if blooms := f.backend.EvmBloomIndex(); blooms != nil {
+		nextBlock, err := blooms.Match(ctx, begin, end, f.addresses, f.topics, find)
+		if err == errPageFull {
+			return logs, next, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if nextBlock > end {
+			return logs, nil, nil
+		}
+		begin = nextBlock
+	}
+
+	if err := find(begin, end); err != nil && err != errPageFull {
+		return nil, nil, err
+	}
+	return logs, next, nil
 }
 
 // indexedLogs returns the logs matching the filter criteria based on raw block
@@ -183,6 +339,43 @@ func (f *Filter) unindexedLogs(ctx context.Context, begin, end idx.Block) (logs
 			return
 		}
 		logs = append(logs, found...)
+		err = f.checkResultsLimit(len(logs))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// unindexedLogsPage returns a page of the logs matching the filter criteria based on raw block iteration.
+func (f *Filter) unindexedLogsPage(ctx context.Context, begin, end idx.Block, cursor *topicsdb.ID, limit int) (logs []*types.Log, next *topicsdb.ID, err error) {
+	if end-begin > f.config.UnindexedLogsBlockRangeLimit {
+		return nil, nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.UnindexedLogsBlockRangeLimit)
+	}
+
+	var (
+		header *evmcore.EvmHeader
+		found  []*types.Log
+	)
+	for n := begin; n <= end; n++ {
+		err = ctx.Err()
+		if err != nil {
+			return
+		}
+
+		header, err = f.backend.HeaderByNumber(ctx, rpc.BlockNumber(n))
+		if header == nil || err != nil {
+			return
+		}
+		found, err = f.blockLogs(ctx, header.Hash)
+		if err != nil {
+			return
+		}
+		found, next = pageLogs(found, cursor, limit-len(logs))
+		logs = append(logs, found...)
+		if next != nil {
+			return
+		}
 	}
 	return
 }
@@ -219,6 +412,36 @@ func (f *Filter) blockLogs(ctx context.Context, header common.Hash) ([]*types.Lo
 	return nil, nil
 }
 
+// pageLogs returns at most limit logs which follow the cursor, ordered by their topicsdb IDs,
+// and the ID of the last log if the page is full.
+func pageLogs(logs []*types.Log, cursor *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID) {
+	ids := make(map[*types.Log]topicsdb.ID, len(logs))
+	for _, l := range logs {
+		ids[l] = topicsdb.NewID(l.BlockNumber, l.TxHash, l.Index)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		a, b := ids[logs[i]], ids[logs[j]]
+		return bytes.Compare(a.Bytes(), b.Bytes()) < 0
+	})
+
+	page := make([]*types.Log, 0, len(logs))
+	for _, l := range logs {
+		if len(page) >= limit {
+			break
+		}
+		id := ids[l]
+		if cursor != nil && bytes.Compare(id.Bytes(), cursor.Bytes()) <= 0 {
+			continue
+		}
+		page = append(page, l)
+	}
+	if len(page) == 0 || len(page) < limit {
+		return page, nil
+	}
+	last := ids[page[len(page)-1]]
+	return page, &last
+}
+
 func includes(addresses []common.Address, a common.Address) bool {
 	for _, addr := range addresses {
 		if addr == a {
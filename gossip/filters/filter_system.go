@@ -53,6 +53,9 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// DroppedTransactionsSubscription queries transactions dropped from the
+	// transaction pool along with the drop reasons
+	DroppedTransactionsSubscription
 	// LastIndexSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -66,6 +69,8 @@ const (
 	logsChanSize = 10
 	// blocksChanSize is the size of channel listening to BlocksEvent.
 	blocksChanSize = 10
+	// dropsChanSize is the size of channel listening to DroppedTxsNotify.
+	dropsChanSize = 128
 )
 
 var (
@@ -80,6 +85,7 @@ type subscription struct {
 	logs      chan []*types.Log
 	hashes    chan []common.Hash
 	headers   chan *types.Header
+	drops     chan []*evmcore.DroppedTx
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -93,13 +99,15 @@ type EventSystem struct {
 	txsSub    notify.Subscription // Subscription for new transaction notify
 	logsSub   notify.Subscription // Subscription for new log notify
 	blocksSub notify.Subscription // Subscription for new chain notify
+	dropsSub  notify.Subscription // Subscription for dropped transactions notify
 
 	// Channels
-	install   chan *subscription           // install filter for event notification
-	uninstall chan *subscription           // remove filter for event notification
-	txsCh     chan core.NewTxsEvent        // Channel to receive new transactions notify
-	logsCh    chan []*types.Log            // Channel to receive new log notify
-	blocksCh  chan evmcore.ChainHeadNotify // Channel to receive new chain notify
+	install   chan *subscription            // install filter for event notification
+	uninstall chan *subscription            // remove filter for event notification
+	txsCh     chan core.NewTxsEvent         // Channel to receive new transactions notify
+	logsCh    chan []*types.Log             // Channel to receive new log notify
+	blocksCh  chan evmcore.ChainHeadNotify  // Channel to receive new chain notify
+	dropsCh   chan evmcore.DroppedTxsNotify // Channel to receive dropped transactions notify
 }
 
 // NewEventSystem creates a new manager that listens for event on the given chans,
@@ -115,15 +123,17 @@ func NewEventSystem(backend Backend) *EventSystem {
 		blocksCh:  make(chan evmcore.ChainHeadNotify, blocksChanSize),
 		txsCh:     make(chan core.NewTxsEvent, txChanSize),
 		logsCh:    make(chan []*types.Log, logsChanSize),
+		dropsCh:   make(chan evmcore.DroppedTxsNotify, dropsChanSize),
 	}
 
 	// Subscribe events
 	m.blocksSub = m.backend.SubscribeNewBlockEvent(m.blocksCh)
 	m.txsSub = m.backend.SubscribeNewTxsEvent(m.txsCh)
 	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
+	m.dropsSub = m.backend.SubscribeDroppedTxsNotify(m.dropsCh)
 
 	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.blocksSub == nil {
+	if m.txsSub == nil || m.logsSub == nil || m.blocksSub == nil || m.dropsSub == nil {
 		log.Crit("Subscribe for event system failed")
 	}
 
@@ -159,6 +169,7 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
+			case <-sub.f.drops:
 			}
 		}
 
@@ -226,6 +237,7 @@ func (es *EventSystem) subscribeMinedPendingLogs(crit ethereum.FilterQuery, logs
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		drops:     make(chan []*evmcore.DroppedTx),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -243,6 +255,7 @@ func (es *EventSystem) subscribeLogs(crit ethereum.FilterQuery, logs chan []*typ
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		drops:     make(chan []*evmcore.DroppedTx),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -260,6 +273,7 @@ func (es *EventSystem) subscribePendingLogs(crit ethereum.FilterQuery, logs chan
 		logs:      logs,
 		hashes:    make(chan []common.Hash),
 		headers:   make(chan *types.Header),
+		drops:     make(chan []*evmcore.DroppedTx),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -276,6 +290,24 @@ func (es *EventSystem) SubscribeNewHeads(headers chan *types.Header) *Subscripti
 		logs:      make(chan []*types.Log),
 		hashes:    make(chan []common.Hash),
 		headers:   headers,
+		drops:     make(chan []*evmcore.DroppedTx),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeDroppedTxs creates a subscription that writes transactions which were
+// dropped from the transaction pool without being included.
+func (es *EventSystem) SubscribeDroppedTxs(drops chan []*evmcore.DroppedTx) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       DroppedTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		drops:     drops,
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -292,6 +324,7 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 		logs:      make(chan []*types.Log),
 		hashes:    hashes,
 		headers:   make(chan *types.Header),
+		drops:     make(chan []*evmcore.DroppedTx),
 		installed: make(chan struct{}),
 		err:       make(chan error),
 	}
@@ -323,6 +356,10 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		for _, f := range filters[PendingTransactionsSubscription] {
 			f.hashes <- hashes
 		}
+	case evmcore.DroppedTxsNotify:
+		for _, f := range filters[DroppedTransactionsSubscription] {
+			f.drops <- e.Drops
+		}
 	case evmcore.ChainHeadNotify:
 		for _, f := range filters[BlocksSubscription] {
 			h := e.Block.EthHeader()
@@ -339,6 +376,7 @@ func (es *EventSystem) eventLoop() {
 		es.blocksSub.Unsubscribe()
 		es.txsSub.Unsubscribe()
 		es.logsSub.Unsubscribe()
+		es.dropsSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -355,6 +393,8 @@ func (es *EventSystem) eventLoop() {
 			es.broadcast(index, ev)
 		case ev := <-es.blocksCh:
 			es.broadcast(index, ev)
+		case ev := <-es.dropsCh:
+			es.broadcast(index, ev)
 
 		case f := <-es.install:
 			index[f.typ][f.id] = f
@@ -371,6 +411,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-es.blocksSub.Err():
 			return
+		case <-es.dropsSub.Err():
+			return
 		}
 	}
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
 	"github.com/zilionixx/go-zilionixx/evmcore"
 	"github.com/zilionixx/go-zilionixx/integration/makegenesis"
 	"github.com/zilionixx/go-zilionixx/topicsdb"
@@ -43,9 +44,11 @@ import (
 type testBackend struct {
 	db         ethdb.Database
 	logIndex   *topicsdb.Index
+	bloomIndex *bloomsdb.Index
 	blocksFeed *notify.Feed
 	txsFeed    *notify.Feed
 	logsFeed   *notify.Feed
+	dropsFeed  *notify.Feed
 }
 
 func newTestBackend() *testBackend {
@@ -55,6 +58,7 @@ func newTestBackend() *testBackend {
 		blocksFeed: new(notify.Feed),
 		txsFeed:    new(notify.Feed),
 		logsFeed:   new(notify.Feed),
+		dropsFeed:  new(notify.Feed),
 	}
 }
 
@@ -127,6 +131,10 @@ func (b *testBackend) SubscribeLogsEvent(ch chan<- []*types.Log) notify.Subscrip
 	return b.logsFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeDroppedTxsNotify(ch chan<- evmcore.DroppedTxsNotify) notify.Subscription {
+	return b.dropsFeed.Subscribe(ch)
+}
+
 func (b *testBackend) SubscribeNewBlockEvent(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
 	return b.blocksFeed.Subscribe(ch)
 }
@@ -135,6 +143,10 @@ func (b *testBackend) EvmLogIndex() *topicsdb.Index {
 	return b.logIndex
 }
 
+func (b *testBackend) EvmBloomIndex() *bloomsdb.Index {
+	return b.bloomIndex
+}
+
 // TestBlockSubscription tests if a block subscription returns block hashes for posted chain notify.
 // It creates multiple subscriptions:
 // - one at the start and should receive all posted chain events and a second (blockHashes)
@@ -256,6 +268,43 @@ func TestPendingTxFilter(t *testing.T) {
 	}
 }
 
+// TestDroppedTxSubscription tests whether dropped tx subscriptions receive all dropped transactions.
+func TestDroppedTxSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newTestBackend()
+		es      = NewEventSystem(backend)
+
+		drops = []*evmcore.DroppedTx{
+			{Tx: types.NewTransaction(0, common.Address{}, new(big.Int), 0, new(big.Int), nil), Reason: evmcore.DropReplaced},
+			{Tx: types.NewTransaction(1, common.Address{}, new(big.Int), 0, new(big.Int), nil), Reason: evmcore.DropExpired},
+		}
+	)
+
+	ch := make(chan []*evmcore.DroppedTx)
+	sub := es.SubscribeDroppedTxs(ch)
+
+	go func() {
+		backend.dropsFeed.Send(evmcore.DroppedTxsNotify{Drops: drops})
+	}()
+
+	select {
+	case got := <-ch:
+		if len(got) != len(drops) {
+			t.Fatalf("invalid number of dropped transactions, want %d, got %d", len(drops), len(got))
+		}
+		for i := range got {
+			if got[i].Tx.Hash() != drops[i].Tx.Hash() || got[i].Reason != drops[i].Reason {
+				t.Errorf("drops[%d] mismatched", i)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dropped transactions weren't received")
+	}
+	sub.Unsubscribe()
+}
+
 // TestLogFilterCreation test whether a given filter criteria makes sense.
 // If not it must return an error.
 func TestLogFilterCreation(t *testing.T) {
@@ -17,14 +17,18 @@
 package filters
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"math/big"
 	"os"
+	"sort"
 	"testing"
 
+	"github.com/zilionixx/zilion-base/kvdb/memorydb"
 	"github.com/zilionixx/zilion-base/kvdb/table"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -32,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
 	"github.com/zilionixx/go-zilionixx/topicsdb"
 	"github.com/zilionixx/go-zilionixx/utils/adapters/ethdb2kvdb"
 )
@@ -112,6 +117,96 @@ func BenchmarkFilters(b *testing.B) {
 	}
 }
 
+func BenchmarkFiltersBloomBits(b *testing.B) {
+	const (
+		blocks      = 20010
+		sectionSize = 1024
+	)
+
+	dir, err := ioutil.TempDir("", "filtertest")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ldb, err := rawdb.NewLevelDBDatabase(dir, 0, 0, "", false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ldb.Close()
+
+	backend := newTestBackend()
+	backend.db = rawdb.NewTable(ldb, "a")
+	backend.logIndex = topicsdb.New(table.New(ethdb2kvdb.Wrap(ldb), []byte("b")))
+	bloomIndex := bloomsdb.New(memorydb.New(), sectionSize)
+
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.BytesToAddress([]byte("jeff"))
+		addr3   = common.BytesToAddress([]byte("ethereum"))
+		addr4   = common.BytesToAddress([]byte("random addresses please"))
+	)
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr1, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, blocks, func(i int, gen *core.BlockGen) {
+		switch i {
+		case 2403:
+			gen.AddUncheckedReceipt(makeReceipt(addr1))
+		case 1034:
+			gen.AddUncheckedReceipt(makeReceipt(addr2))
+		case 34:
+			gen.AddUncheckedReceipt(makeReceipt(addr3))
+		case 19999:
+			gen.AddUncheckedReceipt(makeReceipt(addr4))
+		}
+	})
+	blooms := []types.Bloom{{}} // genesis
+	for i, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+		rawdb.WriteReceipts(backend.db, block.Hash(), block.NumberU64(), receipts[i])
+		for _, receipt := range receipts[i] {
+			for _, l := range receipt.Logs {
+				l.BlockNumber = block.NumberU64()
+			}
+			backend.logIndex.MustPush(receipt.Logs...)
+		}
+		blooms = append(blooms, types.CreateBloom(receipts[i]))
+	}
+	for section := uint64(0); (section+1)*sectionSize <= uint64(len(blooms)); section++ {
+		err := bloomIndex.AddSection(section, blooms[section*sectionSize:(section+1)*sectionSize])
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	cfg := testConfig()
+	cfg.IndexedLogsBlockRangeLimit = blocks
+	for _, bench := range []struct {
+		name  string
+		index *bloomsdb.Index
+	}{
+		{"topicsdb", nil},
+		{"bloombits", bloomIndex},
+	} {
+		backend.bloomIndex = bench.index
+		filter := NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr1, addr2, addr3, addr4}, nil)
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				logs, err := filter.Logs(context.Background())
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(logs) != 4 {
+					b.Fatal("expected 4 logs, got", len(logs))
+				}
+			}
+		})
+	}
+}
+
 func TestFilters(t *testing.T) {
 	var (
 		backend = newTestBackend()
@@ -264,4 +359,207 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 
+	// blocks of the complete sections are pruned by the bloom-bits index
+	blooms := make([]types.Bloom, len(chain)+1)
+	for _, rr := range receipts {
+		for _, r := range rr {
+			for _, l := range r.Logs {
+				blooms[l.BlockNumber].Add(l.Address.Bytes())
+				for _, topic := range l.Topics {
+					blooms[l.BlockNumber].Add(topic.Bytes())
+				}
+			}
+		}
+	}
+	backend.bloomIndex = bloomsdb.New(memorydb.New(), 256)
+	for section := 0; (section+1)*256 <= len(blooms); section++ {
+		if err := backend.bloomIndex.AddSection(uint64(section), blooms[section*256:(section+1)*256]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := testConfig()
+	cfg.IndexedLogsBlockRangeLimit = 300 // only the blocks matched by blooms and the ones after the indexed sections are limited
+
+	filter = NewRangeFilter(backend, cfg, 0, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 4 {
+		t.Error("expected 4 log, got", len(logs))
+	}
+
+	filter = NewRangeFilter(backend, cfg, 1, 10, nil, [][]common.Hash{{hash1, hash2}})
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 2 {
+		t.Error("expected 2 log, got", len(logs))
+	}
+
+	filter = NewRangeFilter(backend, cfg, 0, -1, []common.Address{failAddr}, nil)
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 0 {
+		t.Error("expected 0 log, got", len(logs))
+	}
+
+	// blocks matched by blooms are counted against the range limit
+	for i := range blooms {
+		for j := range blooms[i] {
+			blooms[i][j] = 0xff
+		}
+	}
+	backend.bloomIndex = bloomsdb.New(memorydb.New(), 256)
+	for section := 0; (section+1)*256 <= len(blooms); section++ {
+		if err := backend.bloomIndex.AddSection(uint64(section), blooms[section*256:(section+1)*256]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter = NewRangeFilter(backend, cfg, 0, -1, []common.Address{failAddr}, nil)
+	_, err = filter.Logs(context.Background())
+	if err == nil {
+		t.Error("expected too wide blocks range error")
+	}
+}
+
+func TestFiltersLogsPage(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.BytesToAddress([]byte("jeff"))
+		topic   = common.BytesToHash([]byte("topic"))
+	)
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr1, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, 40, func(i int, gen *core.BlockGen) {
+		if i%3 != 0 {
+			return
+		}
+		for j := 0; j < 2; j++ {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{
+				{Address: addr1, Topics: []common.Hash{topic}},
+				{Address: addr2},
+			}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(i*2+j), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		}
+	})
+	blooms := []types.Bloom{{}} // genesis
+	for i, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+		rawdb.WriteReceipts(backend.db, block.Hash(), block.NumberU64(), receipts[i])
+		derived := rawdb.ReadReceipts(backend.db, block.Hash(), block.NumberU64(), params.TestChainConfig)
+		for _, r := range derived {
+			backend.logIndex.MustPush(r.Logs...)
+		}
+		blooms = append(blooms, types.CreateBloom(derived))
+	}
+	bloomIndex := bloomsdb.New(memorydb.New(), 16)
+	for section := 0; (section+1)*16 <= len(blooms); section++ {
+		if err := bloomIndex.AddSection(uint64(section), blooms[section*16:(section+1)*16]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idOf := func(l *types.Log) topicsdb.ID {
+		return topicsdb.NewID(l.BlockNumber, l.TxHash, l.Index)
+	}
+	for _, index := range []*bloomsdb.Index{nil, bloomIndex} {
+		backend.bloomIndex = index
+		for _, tc := range []struct {
+			addresses []common.Address
+			expect    int
+		}{
+			{[]common.Address{addr1}, 26},
+			{[]common.Address{addr1, addr2}, 52},
+			{nil, 52},
+		} {
+			filter := NewRangeFilter(backend, testConfig(), 2, -1, tc.addresses, nil)
+			all, err := filter.Logs(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != tc.expect {
+				t.Fatalf("expected %d logs, got %d", tc.expect, len(all))
+			}
+			// pages are ordered by log IDs
+			sort.Slice(all, func(i, j int) bool {
+				a, b := idOf(all[i]), idOf(all[j])
+				return bytes.Compare(a.Bytes(), b.Bytes()) < 0
+			})
+
+			var (
+				paged  []*types.Log
+				cursor *topicsdb.ID
+			)
+			for {
+				page, next, err := filter.LogsPage(context.Background(), cursor, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(page) > 3 || (next == nil) == (len(page) == 3) {
+					t.Fatalf("unexpected page of %d logs, next=%v", len(page), next)
+				}
+				paged = append(paged, page...)
+				if next == nil {
+					break
+				}
+				cursor = next
+			}
+			if len(paged) != len(all) {
+				t.Fatalf("expected %d logs, got %d", len(all), len(paged))
+			}
+			for i := range all {
+				if idOf(all[i]) != idOf(paged[i]) {
+					t.Fatalf("log %d mismatch", i)
+				}
+			}
+		}
+	}
+
+	// the number of results is limited
+	cfg := testConfig()
+	cfg.LogsMaxResults = 26
+	for _, addresses := range [][]common.Address{{addr1}, {addr1, addr2}, nil} {
+		logs, err := NewRangeFilter(backend, cfg, 2, -1, addresses, nil).Logs(context.Background())
+		if len(addresses) == 1 {
+			if err != nil || len(logs) != 26 {
+				t.Fatalf("expected 26 logs, got %d, err=%v", len(logs), err)
+			}
+		} else if err == nil {
+			t.Fatal("expected too many results error")
+		}
+	}
+
+	api := NewPublicLogsAPI(backend, cfg)
+	crit := FilterCriteria{FromBlock: big.NewInt(2), Addresses: []common.Address{addr1}}
+	if _, err := api.GetLogsPage(context.Background(), crit, nil, 27); err == nil {
+		t.Fatal("expected too large limit error")
+	}
+	invalid := hexutil.Bytes{1, 2, 3}
+	if _, err := api.GetLogsPage(context.Background(), crit, &invalid, 10); err == nil {
+		t.Fatal("expected invalid cursor error")
+	}
+	page, err := api.GetLogsPage(context.Background(), crit, nil, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Logs) != 20 || page.Cursor == nil {
+		t.Fatalf("expected 20 logs and a cursor, got %d", len(page.Logs))
+	}
+	page, err = api.GetLogsPage(context.Background(), crit, page.Cursor, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Logs) != 6 || page.Cursor != nil {
+		t.Fatalf("expected the last 6 logs, got %d", len(page.Logs))
+	}
 }
@@ -0,0 +1,69 @@
+package freezer
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Freezer is a set of named tables in a directory
+type Freezer struct {
+	dir    string
+	tables map[string]*Table
+}
+
+// Open opens or creates the tables in the directory
+func Open(dir string, names ...string) (*Freezer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	f := &Freezer{
+		dir:    dir,
+		tables: make(map[string]*Table, len(names)),
+	}
+	for _, name := range names {
+		t, err := OpenTable(filepath.Join(dir, name+".idx"), filepath.Join(dir, name+".dat"))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		f.tables[name] = t
+	}
+	return f, nil
+}
+
+// Exists returns true if the directory of a freezer exists
+func Exists(dir string) bool {
+	_, err := os.Stat(dir)
+	return err == nil
+}
+
+// Table returns the table, or nil if the table wasn't opened
+func (f *Freezer) Table(name string) *Table {
+	return f.tables[name]
+}
+
+// Dir returns the directory of the freezer
+func (f *Freezer) Dir() string {
+	return f.dir
+}
+
+// Sync flushes the appended items of all the tables to disk
+func (f *Freezer) Sync() error {
+	for _, t := range f.tables {
+		if err := t.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the tables
+func (f *Freezer) Close() error {
+	var err error
+	for _, t := range f.tables {
+		if e := t.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,262 @@
+// Package freezer implements an append-only flat-file storage of ancient chain data.
+package freezer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// headerSize is a size of the index file header, which is the number of the first item
+	headerSize = 8
+	// entrySize is a size of an index entry, which is the end offset of an item in the data file
+	entrySize = 8
+)
+
+var (
+	// ErrOutOfOrder is returned if an appended item isn't the next one
+	ErrOutOfOrder = errors.New("appended item is out of order")
+	// ErrNotFound is returned if an item isn't stored in the table
+	ErrNotFound = errors.New("item isn't found")
+)
+
+// Table is an append-only flat-file storage of items, which are addressed by sequential numbers.
+// Items are concatenated in the data file, the index file stores the end offset of every item.
+type Table struct {
+	index *os.File
+	data  *os.File
+
+	tail uint64 // number of the first item
+	head uint64 // number of the next item
+	size uint64 // size of the data file
+
+	mu sync.RWMutex
+}
+
+// OpenTable opens the table files, and repairs an interrupted append
+func OpenTable(indexPath, dataPath string) (*Table, error) {
+	index, err := os.OpenFile(indexPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(dataPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		_ = index.Close()
+		return nil, err
+	}
+	t := &Table{
+		index: index,
+		data:  data,
+	}
+	if err := t.repair(); err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair truncates the partially written entries and items
+func (t *Table) repair() error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+	indexSize := uint64(indexStat.Size())
+	dataSize := uint64(dataStat.Size())
+
+	if indexSize < headerSize {
+		// the first item was never appended
+		if err := t.index.Truncate(0); err != nil {
+			return err
+		}
+		return t.data.Truncate(0)
+	}
+	var header [headerSize]byte
+	if _, err := t.index.ReadAt(header[:], 0); err != nil {
+		return err
+	}
+	t.tail = binary.BigEndian.Uint64(header[:])
+
+	items := (indexSize - headerSize) / entrySize
+	// drop the entries of items which weren't fully written into the data file
+	for ; items > 0; items-- {
+		end, err := t.readEntry(items - 1)
+		if err != nil {
+			return err
+		}
+		if end <= dataSize {
+			t.size = end
+			break
+		}
+	}
+	if items == 0 {
+		t.size = 0
+	}
+	t.head = t.tail + items
+
+	if err := t.index.Truncate(int64(headerSize + items*entrySize)); err != nil {
+		return err
+	}
+	return t.data.Truncate(int64(t.size))
+}
+
+// readEntry reads the end offset of the i-th item of the table
+func (t *Table) readEntry(i uint64) (uint64, error) {
+	var entry [entrySize]byte
+	if _, err := t.index.ReadAt(entry[:], int64(headerSize+i*entrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(entry[:]), nil
+}
+
+// Tail returns the number of the first item
+func (t *Table) Tail() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tail
+}
+
+// Head returns the number of the next item, i.e. the number of the last item plus one
+func (t *Table) Head() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.head
+}
+
+// Empty returns true if no items are stored
+func (t *Table) Empty() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.head == t.tail
+}
+
+// Has returns true if the item is stored
+func (t *Table) Has(n uint64) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return n >= t.tail && n < t.head
+}
+
+// Get returns the item, or ErrNotFound if the item isn't stored
+func (t *Table) Get(n uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if n < t.tail || n >= t.head {
+		return nil, ErrNotFound
+	}
+	i := n - t.tail
+	start := uint64(0)
+	if i != 0 {
+		var err error
+		start, err = t.readEntry(i - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.readEntry(i)
+	if err != nil {
+		return nil, err
+	}
+	if end < start || end > t.size {
+		return nil, fmt.Errorf("corrupted index entry of item %d", n)
+	}
+	item := make([]byte, end-start)
+	if _, err := t.data.ReadAt(item, int64(start)); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Append writes the item into the table. The first appended item defines the tail,
+// every following item has to be the next one.
+// Appended items are durable only after Sync.
+func (t *Table) Append(n uint64, item []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.head == t.tail {
+		if t.size != 0 {
+			return fmt.Errorf("non-empty data file of an empty table")
+		}
+		var header [headerSize]byte
+		binary.BigEndian.PutUint64(header[:], n)
+		if _, err := t.index.WriteAt(header[:], 0); err != nil {
+			return err
+		}
+		t.tail, t.head = n, n
+	} else if n != t.head {
+		return fmt.Errorf("%w: item %d, expected %d", ErrOutOfOrder, n, t.head)
+	}
+
+	if _, err := t.data.WriteAt(item, int64(t.size)); err != nil {
+		return err
+	}
+	end := t.size + uint64(len(item))
+	var entry [entrySize]byte
+	binary.BigEndian.PutUint64(entry[:], end)
+	if _, err := t.index.WriteAt(entry[:], int64(headerSize+(t.head-t.tail)*entrySize)); err != nil {
+		return err
+	}
+	t.size = end
+	t.head++
+	return nil
+}
+
+// Truncate drops the items starting from the given number
+func (t *Table) Truncate(head uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if head >= t.head {
+		return nil
+	}
+	if head < t.tail {
+		head = t.tail
+	}
+	items := head - t.tail
+	size := uint64(0)
+	if items != 0 {
+		var err error
+		size, err = t.readEntry(items - 1)
+		if err != nil {
+			return err
+		}
+	}
+	if err := t.index.Truncate(int64(headerSize + items*entrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.head = head
+	t.size = size
+	return nil
+}
+
+// Sync flushes the appended items to disk
+func (t *Table) Sync() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// items are synced before the index entries, so that entries never point to missing items
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the table files
+func (t *Table) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	err1 := t.index.Close()
+	err2 := t.data.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
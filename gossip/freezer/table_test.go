@@ -0,0 +1,107 @@
+package freezer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testItem(n uint64) []byte {
+	return []byte(fmt.Sprintf("item%d", n*n))
+}
+
+func TestTable(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	f, err := Open(dir, "test")
+	require.NoError(err)
+	table := f.Table("test")
+	require.True(table.Empty())
+
+	for n := uint64(10); n < 100; n++ {
+		require.NoError(table.Append(n, testItem(n)))
+	}
+	require.ErrorIs(table.Append(200, testItem(200)), ErrOutOfOrder)
+	require.NoError(table.Append(100, nil))
+	require.NoError(f.Sync())
+
+	check := func(table *Table, tail, head uint64) {
+		require.Equal(tail, table.Tail())
+		require.Equal(head, table.Head())
+		require.False(table.Has(tail - 1))
+		require.False(table.Has(head))
+		_, err := table.Get(head)
+		require.ErrorIs(err, ErrNotFound)
+		for n := tail; n < head; n++ {
+			require.True(table.Has(n))
+			item, err := table.Get(n)
+			require.NoError(err)
+			if n == 100 {
+				require.Empty(item)
+			} else {
+				require.Equal(testItem(n), item)
+			}
+		}
+	}
+	check(table, 10, 101)
+
+	require.NoError(table.Truncate(50))
+	check(table, 10, 50)
+	require.NoError(table.Append(50, testItem(50)))
+	check(table, 10, 51)
+	require.NoError(f.Close())
+
+	// reopen
+	f, err = Open(dir, "test")
+	require.NoError(err)
+	check(f.Table("test"), 10, 51)
+	require.NoError(f.Close())
+}
+
+func TestTableRepair(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	f, err := Open(dir, "test")
+	require.NoError(err)
+	for n := uint64(0); n < 10; n++ {
+		require.NoError(f.Table("test").Append(n, testItem(n)))
+	}
+	require.NoError(f.Close())
+
+	// simulate a partially written index entry and a missing item
+	indexPath := filepath.Join(dir, "test.idx")
+	dataPath := filepath.Join(dir, "test.dat")
+	stat, err := os.Stat(indexPath)
+	require.NoError(err)
+	require.NoError(os.Truncate(indexPath, stat.Size()-3))
+	stat, err = os.Stat(dataPath)
+	require.NoError(err)
+	require.NoError(os.Truncate(dataPath, stat.Size()-int64(len(testItem(8)))-1))
+
+	f, err = Open(dir, "test")
+	require.NoError(err)
+	table := f.Table("test")
+	require.Equal(uint64(8), table.Head())
+	for n := uint64(0); n < 8; n++ {
+		item, err := table.Get(n)
+		require.NoError(err)
+		require.Equal(testItem(n), item)
+	}
+	require.NoError(table.Append(8, testItem(8)))
+	item, err := table.Get(8)
+	require.NoError(err)
+	require.Equal(testItem(8), item)
+	require.NoError(f.Close())
+}
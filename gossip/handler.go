@@ -73,6 +73,7 @@ type handlerConfig struct {
 	checkers     *eventcheck.Checkers
 	s            *Store
 	processEvent func(*inter.EventPayload) error
+	identity     validatorIdentifier
 }
 
 type ProtocolManager struct {
@@ -101,6 +102,7 @@ type ProtocolManager struct {
 	store        *Store
 	processEvent func(*inter.EventPayload) error
 	engineMu     sync.Locker
+	identity     validatorIdentifier
 
 	notifier             dagNotifier
 	emittedEventsCh      chan *inter.EventPayload
@@ -148,6 +150,7 @@ func newHandler(
 		checkers:             c.checkers,
 		peers:                newPeerSet(),
 		engineMu:             c.engineMu,
+		identity:             c.identity,
 		newPeerCh:            make(chan *peer),
 		noMorePeers:          make(chan struct{}),
 		txsyncCh:             make(chan *txsync),
@@ -190,7 +193,8 @@ func newHandler(
 		},
 		PeerEpoch: func(peer string) idx.Epoch {
 			p := pm.peers.Peer(peer)
-			if p == nil {
+			// peers which have pruned events of the current epoch aren't selected for syncing
+			if p == nil || !p.ServesEventsOf(pm.store.GetEpoch()) {
 				return 0
 			}
 			return p.progress.Epoch
@@ -525,6 +529,8 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	// Propagate existing transactions. new transactions appearing
 	// after this will be sent via broadcasts.
 	pm.syncTransactions(p, pm.txpool.SampleHashes(pm.config.Protocol.MaxInitialTxHashesSend))
+	// Prove to the peer that we're a validator, so it would forward private transactions to us
+	pm.sendValidatorIdentity(p)
 
 	// Handle incoming messages until the connection is torn down
 	for {
@@ -735,7 +741,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		txs := make(types.Transactions, 0, len(requests))
 		for _, txid := range requests {
 			tx := pm.txpool.Get(txid)
-			if tx == nil {
+			if tx == nil || pm.txpool.IsPrivate(txid) {
 				continue
 			}
 			txs = append(txs, tx)
@@ -744,6 +750,43 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			p.EnqueueSendTransactions(batch, p.queue)
 		})
 
+	case msg.Code == PrivateEvmTxsMsg:
+		if !p.SupportsValidatorIdentity() {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		// Transactions arrived, make sure we have a valid and fresh graph to handle them
+		if atomic.LoadUint32(&pm.synced) == 0 {
+			break
+		}
+		var txs types.Transactions
+		if err := msg.Decode(&txs); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := checkLenLimits(len(txs), txs); err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			p.MarkTransaction(tx.Hash())
+		}
+		pm.txpool.AddPrivateRemotes(txs)
+
+	case msg.Code == ValidatorIdentityMsg:
+		if !p.SupportsValidatorIdentity() {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		var proof validatorIdentity
+		if err := msg.Decode(&proof); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if pm.identity == nil || proof.Epoch != myEpoch {
+			break
+		}
+		if err := pm.identity.VerifyIdentity(p.ID(), &proof); err != nil {
+			p.Log().Debug("Invalid validator identity", "validator", proof.Validator, "err", err)
+			break
+		}
+		p.SetValidator(proof.Validator, proof.Epoch)
+
 	case msg.Code == EventsMsg:
 		var events inter.EventPayloads
 		if err := msg.Decode(&events); err != nil {
@@ -810,9 +853,12 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 		pid := p.id
+		prunedBefore := pm.prunedEventsBefore(request.Session.Start)
 		_, peerErr := pm.seeder.NotifyRequestReceived(streamseeder.Peer{
-			ID:        pid,
-			SendChunk: p.SendEventsStream,
+			ID: pid,
+			SendChunk: func(r dagstream.Response, ids hash.Events) error {
+				return p.SendEventsStream(r, ids, prunedBefore)
+			},
 			Misbehaviour: func(err error) {
 				pm.peerMisbehaviour(pid, err)
 			},
@@ -844,6 +890,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 
 		_ = pm.leecher.NotifyChunkReceived(chunk.SessionID, last, chunk.Done)
+		if len(chunk.PrunedBefore) != 0 && p.SupportsPrunedEvents() {
+			pm.onPeerPrunedEvents(p, chunk.PrunedBefore[0])
+		}
 
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
@@ -851,6 +900,30 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	return nil
 }
 
+// onPeerPrunedEvents switches the events syncing to another peer, if the peer cannot serve events of the current epoch
+func (pm *ProtocolManager) onPeerPrunedEvents(p *peer, prunedBefore idx.Epoch) {
+	p.SetPrunedEventsBefore(prunedBefore)
+	if p.ServesEventsOf(pm.store.GetEpoch()) {
+		return
+	}
+	p.Log().Debug("Peer has pruned the requested events", "before epoch", prunedBefore)
+	// re-registration terminates the session with the peer, and it isn't selected until the epoch is synced
+	_ = pm.leecher.UnregisterPeer(p.id)
+	_ = pm.leecher.RegisterPeer(p.id)
+}
+
+// prunedEventsBefore returns the epoch before which the events of a stream starting from the selector are pruned,
+// or zero if the events aren't pruned
+func (pm *ProtocolManager) prunedEventsBefore(start []byte) idx.Epoch {
+	pruned := pm.store.GetPrunedEventsEpoch()
+	var from hash.Event
+	copy(from[:], start)
+	if from.Epoch() >= pruned {
+		return 0
+	}
+	return pruned
+}
+
 func (pm *ProtocolManager) decideBroadcastAggressiveness(size int, passed time.Duration, peersNum int) int {
 	percents := 100
 	maxPercents := 1000000 * percents
@@ -917,13 +990,18 @@ func (pm *ProtocolManager) BroadcastEvent(event *inter.EventPayload, passed time
 }
 
 // BroadcastTxs will propagate a batch of transactions to all peers which are not known to
-// already have the given transaction.
+// already have the given transaction. Private transactions are sent only to validators.
 func (pm *ProtocolManager) BroadcastTxs(txs types.Transactions) {
 	var txset = make(map[*peer]types.Transactions)
 
 	// Broadcast transactions to a batch of peers not knowing about it
 	totalSize := common.StorageSize(0)
+	var private types.Transactions
 	for _, tx := range txs {
+		if pm.txpool.IsPrivate(tx.Hash()) {
+			private = append(private, tx)
+			continue
+		}
 		peers := pm.peers.PeersWithoutTx(tx.Hash())
 		for _, peer := range peers {
 			txset[peer] = append(txset[peer], tx)
@@ -947,6 +1025,47 @@ func (pm *ProtocolManager) BroadcastTxs(txs types.Transactions) {
 		})
 		i++
 	}
+	if len(private) != 0 {
+		pm.broadcastPrivateTxs(private)
+	}
+}
+
+// broadcastPrivateTxs will propagate a batch of private transactions to validators of
+// the current epoch which are not known to already have the given transaction.
+func (pm *ProtocolManager) broadcastPrivateTxs(txs types.Transactions) {
+	var txset = make(map[*peer]types.Transactions)
+
+	epoch := pm.store.GetEpoch()
+	for _, tx := range txs {
+		peers := pm.peers.ValidatorsWithoutTx(tx.Hash(), epoch)
+		for _, peer := range peers {
+			txset[peer] = append(txset[peer], tx)
+		}
+		log.Trace("Broadcast private transaction", "hash", tx.Hash(), "recipients", len(peers))
+	}
+	for peer, txs := range txset {
+		SplitTransactions(txs, func(batch types.Transactions) {
+			peer.AsyncSendPrivateTransactions(batch, peer.queue)
+		})
+	}
+}
+
+// sendValidatorIdentity sends the validator identity proof to the peer, if the node is a validator
+func (pm *ProtocolManager) sendValidatorIdentity(p *peer) {
+	if pm.identity == nil || !p.SupportsValidatorIdentity() {
+		return
+	}
+	if proof := pm.identity.ProveIdentity(p.ID()); proof != nil {
+		p.AsyncSendValidatorIdentity(proof, p.queue)
+	}
+}
+
+// BroadcastValidatorIdentity sends the validator identity proofs to all peers.
+// It's called when the epoch or the validator is switched.
+func (pm *ProtocolManager) BroadcastValidatorIdentity() {
+	for _, peer := range pm.peers.List() {
+		pm.sendValidatorIdentity(peer)
+	}
 }
 
 // Mined broadcast loop
@@ -1005,6 +1124,7 @@ func (pm *ProtocolManager) onNewEpochLoop() {
 				}
 			}
 			pm.leecher.OnNewEpoch(myEpoch)
+			pm.BroadcastValidatorIdentity()
 		// Err() channel will be closed when unsubscribing.
 		case <-pm.newEpochsSub.Err():
 			return
@@ -34,9 +34,10 @@ const (
 // PeerInfo represents a short summary of the sub-protocol metadata known
 // about a connected peer.
 type PeerInfo struct {
-	Version     int       `json:"version"` // protocol version negotiated
-	Epoch       idx.Epoch `json:"epoch"`
-	NumOfBlocks idx.Block `json:"blocks"`
+	Version     int             `json:"version"` // protocol version negotiated
+	Epoch       idx.Epoch       `json:"epoch"`
+	NumOfBlocks idx.Block       `json:"blocks"`
+	Validator   idx.ValidatorID `json:"validator,omitempty"` // proven validator ID, if any
 }
 
 type broadcastItem struct {
@@ -62,6 +63,11 @@ type peer struct {
 
 	progress PeerProgress
 
+	validator      idx.ValidatorID // validator ID proven by the peer
+	validatorEpoch idx.Epoch       // epoch of the validator proof
+
+	prunedEventsBefore idx.Epoch // events of older epochs are pruned by the peer
+
 	sync.RWMutex
 }
 
@@ -72,6 +78,52 @@ func (p *peer) SetProgress(x PeerProgress) {
 	p.progress = x
 }
 
+// SupportsValidatorIdentity returns true if the peer has negotiated the protocol version
+// with validator identity proofs and private transactions
+func (p *peer) SupportsValidatorIdentity() bool {
+	return p.version >= zilionixx63
+}
+
+// SetValidator remembers the validator ID proven by the peer for the epoch
+func (p *peer) SetValidator(id idx.ValidatorID, epoch idx.Epoch) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.validator, p.validatorEpoch = id, epoch
+}
+
+// IsValidator returns true if the peer has proven that it's a validator of the epoch
+func (p *peer) IsValidator(epoch idx.Epoch) bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.validator != 0 && p.validatorEpoch == epoch
+}
+
+// SupportsPrunedEvents returns true if the peer has negotiated the protocol version
+// which reports pruned ranges of events
+func (p *peer) SupportsPrunedEvents() bool {
+	return p.version >= zilionixx64
+}
+
+// SetPrunedEventsBefore remembers that the peer cannot serve events of the epochs before the given one
+func (p *peer) SetPrunedEventsBefore(epoch idx.Epoch) {
+	p.Lock()
+	defer p.Unlock()
+
+	if epoch > p.prunedEventsBefore {
+		p.prunedEventsBefore = epoch
+	}
+}
+
+// ServesEventsOf returns false if the peer has reported that events of the epoch are pruned
+func (p *peer) ServesEventsOf(epoch idx.Epoch) bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return epoch >= p.prunedEventsBefore
+}
+
 func (p *peer) InterestedIn(h hash.Event) bool {
 	e := h.Epoch()
 
@@ -136,11 +188,18 @@ func (p *peer) close() {
 
 // Info gathers and returns a collection of metadata known about a peer.
 func (p *peer) Info() *PeerInfo {
-	return &PeerInfo{
+	p.RLock()
+	defer p.RUnlock()
+
+	info := &PeerInfo{
 		Version:     p.version,
 		Epoch:       p.progress.Epoch,
 		NumOfBlocks: p.progress.LastBlockIdx,
 	}
+	if p.validatorEpoch == p.progress.Epoch {
+		info.Validator = p.validator
+	}
+	return info
 }
 
 // MarkEvent marks a event as known for the peer, ensuring that the event will
@@ -292,6 +351,22 @@ func (p *peer) AsyncSendTransactionHashes(txids []common.Hash, queue chan broadc
 	}
 }
 
+// AsyncSendPrivateTransactions queues list of private transactions propagation to a remote
+// peer. If the peer's broadcast queue is full, the transactions are silently dropped.
+func (p *peer) AsyncSendPrivateTransactions(txs types.Transactions, queue chan broadcastItem) {
+	if p.asyncSendNonEncodedItem(txs, PrivateEvmTxsMsg, queue) {
+		// Mark all the transactions as known, but ensure we don't overflow our limits
+		for _, tx := range txs {
+			p.knownTxs.Add(tx.Hash())
+		}
+		for p.knownTxs.Cardinality() >= p.cfg.MaxKnownTxs {
+			p.knownTxs.Pop()
+		}
+	} else {
+		p.Log().Debug("Dropping private transactions propagation", "count", len(txs))
+	}
+}
+
 // EnqueueSendTransactions queues list of transactions propagation to a remote
 // peer.
 // The method is blocking in a case if the peer's broadcast queue is full.
@@ -390,6 +465,14 @@ func (p *peer) EnqueueSendEventsRLP(events []rlp.RawValue, ids []hash.Event, que
 	}
 }
 
+// AsyncSendValidatorIdentity queues a validator identity proof to a remote peer.
+// If the peer's broadcast queue is full, the proof is silently dropped.
+func (p *peer) AsyncSendValidatorIdentity(proof *validatorIdentity, queue chan broadcastItem) {
+	if !p.asyncSendNonEncodedItem(proof, ValidatorIdentityMsg, queue) {
+		p.Log().Debug("Dropping validator identity propagation")
+	}
+}
+
 // AsyncSendProgress queues a progress propagation to a remote peer.
 // If the peer's broadcast queue is full, the progress is silently dropped.
 func (p *peer) AsyncSendProgress(progress PeerProgress, queue chan broadcastItem) {
@@ -430,7 +513,9 @@ func (p *peer) RequestTransactions(txids []common.Hash) error {
 	return nil
 }
 
-func (p *peer) SendEventsStream(r dagstream.Response, ids hash.Events) error {
+// SendEventsStream sends the events stream chunk. If prunedBefore isn't zero, the chunk reports
+// that the events of the requested range before the epoch are pruned, if the peer supports it.
+func (p *peer) SendEventsStream(r dagstream.Response, ids hash.Events, prunedBefore idx.Epoch) error {
 	// Mark all the event hash as known, but ensure we don't overflow our limits
 	for _, id := range ids {
 		p.knownEvents.Add(id)
@@ -438,7 +523,16 @@ func (p *peer) SendEventsStream(r dagstream.Response, ids hash.Events) error {
 			p.knownEvents.Pop()
 		}
 	}
-	return p2p.Send(p.rw, EventsStreamResponse, r)
+	resp := eventsStreamResponse{
+		SessionID: r.SessionID,
+		Done:      r.Done,
+		IDs:       r.IDs,
+		Events:    r.Events,
+	}
+	if prunedBefore != 0 && p.SupportsPrunedEvents() {
+		resp.PrunedBefore = []idx.Epoch{prunedBefore}
+	}
+	return p2p.Send(p.rw, EventsStreamResponse, resp)
 }
 
 func (p *peer) RequestEventsStream(r dagstream.Request) error {
@@ -628,6 +722,21 @@ func (ps *peerSet) PeersWithoutTx(hash common.Hash) []*peer {
 	return list
 }
 
+// ValidatorsWithoutTx retrieves a list of peers which have proven to be validators of the epoch,
+// and do not have a given transaction in their set of known hashes.
+func (ps *peerSet) ValidatorsWithoutTx(hash common.Hash, epoch idx.Epoch) []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.SupportsValidatorIdentity() && p.IsValidator(epoch) && !p.knownTxs.Contains(hash) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // BestPeer retrieves the known peer with the currently highest total difficulty.
 func (ps *peerSet) BestPeer() *peer {
 	ps.lock.RLock()
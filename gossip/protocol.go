@@ -13,17 +13,21 @@ import (
 
 // Constants to match up protocol versions and messages
 const (
-	ProtocolVersion = 62 // derived from eth62
+	zilionixx62 = 62 // derived from eth62
+	zilionixx63 = 63 // adds validator identity proofs and private transactions
+	zilionixx64 = 64 // adds pruned ranges of events to events stream responses
+
+	ProtocolVersion = zilionixx64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "zilionixx"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{ProtocolVersion}
+var ProtocolVersions = []uint{zilionixx64, zilionixx63, zilionixx62}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{ProtocolVersion: EventsStreamResponse + 1}
+var protocolLengths = map[uint]uint64{zilionixx64: PrivateEvmTxsMsg + 1, zilionixx63: PrivateEvmTxsMsg + 1, zilionixx62: EventsStreamResponse + 1}
 
 const protocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -55,6 +59,11 @@ const (
 	RequestEventsStream = 8
 	// Contains the requested events by RequestEventsStream
 	EventsStreamResponse = 9
+
+	// Proves that the sender is a validator of the current epoch (zilionixx63)
+	ValidatorIdentityMsg = 10
+	// Contains a batch of private transactions, which are forwarded only to validators (zilionixx63)
+	PrivateEvmTxsMsg = 11
 )
 
 type errCode int
@@ -94,6 +103,14 @@ type txPool interface {
 	// AddRemotes should add the given transactions to the pool.
 	AddRemotes([]*types.Transaction) []error
 
+	// AddPrivateRemotes should add the given transactions to the pool, marking them as private.
+	AddPrivateRemotes([]*types.Transaction) []error
+
+	// IsPrivate should return true if the transaction must be forwarded only to validators.
+	IsPrivate(common.Hash) bool
+	// IsPriority should return true if the transaction belongs to the priority lane.
+	IsPriority(*types.Transaction) bool
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending() (map[common.Address]types.Transactions, error)
@@ -115,6 +132,13 @@ type handshakeData struct {
 	Genesis         common.Hash
 }
 
+// validatorIdentity is the network packet which proves that the sender is a validator
+type validatorIdentity struct {
+	Epoch     idx.Epoch
+	Validator idx.ValidatorID
+	Sig       []byte
+}
+
 // PeerProgress is synchronization status of a peer
 type PeerProgress struct {
 	Epoch            idx.Epoch
@@ -129,4 +153,15 @@ type epochChunk struct {
 	Done      bool
 	IDs       hash.Events
 	Events    inter.EventPayloads
+	// PrunedBefore is set if the events of the requested range before the epoch are pruned by the sender (zilionixx64)
+	PrunedBefore []idx.Epoch `rlp:"tail"`
+}
+
+// eventsStreamResponse is dagstream.Response along with the pruned range of the requested events
+type eventsStreamResponse struct {
+	SessionID    uint32
+	Done         bool
+	IDs          hash.Events
+	Events       []interface{}
+	PrunedBefore []idx.Epoch `rlp:"tail"`
 }
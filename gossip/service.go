@@ -1,10 +1,12 @@
 package gossip
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/core"
@@ -121,11 +123,18 @@ type Service struct {
 	dagIndexer          *vecmt.Index
 	engineMu            *sync.RWMutex
 	emitter             *emitter.Emitter
+	emitterDir          string
 	txpool              *evmcore.TxPool
 	heavyCheckReader    HeavyCheckReader
 	gasPowerCheckReader GasPowerCheckReader
 	checkers            *eventcheck.Checkers
 	uniqueEventIDs      uniqueID
+	extraQueue          extraDataQueue
+	identity            peerIdentity
+
+	// emitterMu serializes validator switches with each other and with the service stop
+	emitterMu      sync.Mutex
+	emitterStopped bool
 
 	// version watcher
 	verWatcher *verwatcher.VerWarcher
@@ -163,6 +172,9 @@ func NewService(stack *node.Node, config Config, store *Store, signer valkeystor
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.Snapshot != "" {
+		config.TxPool.Snapshot = stack.ResolvePath(config.TxPool.Snapshot)
+	}
 
 	svc, err := newService(config, store, signer, blockProc, engine, dagIndexer)
 	if err != nil {
@@ -171,6 +183,7 @@ func NewService(stack *node.Node, config Config, store *Store, signer valkeystor
 
 	svc.p2pServer = stack.Server()
 	svc.accountManager = stack.AccountManager()
+	svc.emitterDir = stack.ResolvePath("emitter")
 	// Create the net API service
 	svc.netRPCService = ethapi.NewPublicNetAPI(svc.p2pServer, store.GetRules().NetworkID)
 
@@ -222,14 +235,22 @@ func newService(config Config, store *Store, signer valkeystore.SignerI, blockPr
 	var err error
 	svc.dialCandidates, err = dnsclient.NewIterator()
 
+	// create validator identity prover for peers
+	svc.identity = peerIdentity{
+		signer:  signer,
+		reader:  &svc.heavyCheckReader,
+		localID: svc.localNodeID,
+	}
+	svc.identity.SetValidator(config.Emitter.Validator.ID)
+
 	// create protocol manager
-	svc.pm, err = newHandler(handlerConfig{config, &svc.feed, svc.txpool, svc.engineMu, svc.checkers, store, svc.processEvent})
+	svc.pm, err = newHandler(handlerConfig{config, &svc.feed, svc.txpool, svc.engineMu, svc.checkers, store, svc.processEvent, &svc.identity})
 	if err != nil {
 		return nil, err
 	}
 
 	// create API backend
-	svc.EthAPI = &EthAPIBackend{config.ExtRPCEnabled, svc, stateReader, config.AllowUnprotectedTxs}
+	svc.EthAPI = &EthAPIBackend{config.ExtRPCEnabled, svc, stateReader, config.AllowUnprotectedTxs, ethapi.NewTxSubmitLimiter(config.TxSubmitLimits)}
 
 	svc.emitter = svc.makeEmitter(signer)
 
@@ -270,6 +291,39 @@ func (s *Service) makeEmitter(signer valkeystore.SignerI) *emitter.Emitter {
 	})
 }
 
+// SwitchValidator switches the node to another validator ID or key, or to the observer mode if ID is zero.
+// File with the last emitted event is switched along with the validator ID.
+func (s *Service) SwitchValidator(validator emitter.ValidatorConfig) error {
+	s.emitterMu.Lock()
+	defer s.emitterMu.Unlock()
+	if s.emitterStopped {
+		return errors.New("service is stopped")
+	}
+
+	file := s.config.Emitter.PrevEmittedEventFile
+	if prev := s.emitter.GetValidator(); validator.ID != 0 && (validator.ID != prev.ID || len(file.Path) == 0) {
+		file.Path = emitter.PrevEmittedEventFilePath(s.emitterDir, validator.ID)
+	}
+	s.emitter.SetValidator(validator, file)
+	// the emitter updates the validator key of the config under the engine lock on a key rotation
+	s.engineMu.Lock()
+	s.config.Emitter.Validator = validator
+	s.config.Emitter.PrevEmittedEventFile = file
+	s.engineMu.Unlock()
+	s.identity.SetValidator(validator.ID)
+	s.pm.BroadcastValidatorIdentity()
+	s.Log.Info("Validator is switched", "id", validator.ID, "pubkey", validator.PubKey.String())
+	return nil
+}
+
+// localNodeID returns the p2p node ID of the node
+func (s *Service) localNodeID() enode.ID {
+	if s.p2pServer == nil || s.p2pServer.LocalNode() == nil {
+		return enode.ID{}
+	}
+	return s.p2pServer.LocalNode().ID()
+}
+
 // MakeProtocols constructs the P2P protocol definitions for `zilionixx`.
 func MakeProtocols(svc *Service, backend *ProtocolManager, network uint64, disc enode.Iterator) []p2p.Protocol {
 	protocols := make([]p2p.Protocol, len(ProtocolVersions))
@@ -329,17 +383,39 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   filters.NewPublicFilterAPI(s.EthAPI, s.config.FilterAPI),
 			Public:    true,
+		}, {
+			Namespace: "zilionixx",
+			Version:   "1.0",
+			Service:   filters.NewPublicLogsAPI(s.EthAPI, s.config.FilterAPI),
+			Public:    true,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "emitter",
+			Version:   "1.0",
+			Service:   NewPrivateEmitterAPI(s),
+			Public:    false,
 		},
 	}...)
 
 	return apis
 }
 
+// ValidatorAPIs returns admin api methods for switching the validator, which require access to the validator keystore.
+func (s *Service) ValidatorAPIs(keystore valkeystore.KeystoreI) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "validator",
+			Version:   "1.0",
+			Service:   NewPrivateValidatorAPI(s, keystore),
+			Public:    false,
+		},
+	}
+}
+
 // Start method invoked when the node is ready to start the service.
 func (s *Service) Start() error {
 	err := s.store.Init()
@@ -353,13 +429,38 @@ func (s *Service) Start() error {
 
 	s.pm.Start(s.p2pServer.MaxPeers)
 
+	s.emitterMu.Lock()
 	s.emitter.Start()
+	s.emitterMu.Unlock()
 
 	s.verWatcher.Start()
 
+	s.startAncientsMover()
+	s.startEventsPruner()
+	s.startTxUnindexer()
+	s.startBloomIndexer()
+
 	return nil
 }
 
+// startPeriodicJob runs the job in background with the given period until the service is stopped
+func (s *Service) startPeriodicJob(period time.Duration, job func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				job()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
 // WaitBlockEnd waits until parallel block processing is complete (if any)
 func (s *Service) WaitBlockEnd() {
 	s.blockProcWg.Wait()
@@ -370,7 +471,10 @@ func (s *Service) Stop() error {
 	defer log.Info("Zilionixx service stopped")
 	s.verWatcher.Stop()
 	close(s.done)
+	s.emitterMu.Lock()
+	s.emitterStopped = true
 	s.emitter.Stop()
+	s.emitterMu.Unlock()
 	s.pm.Stop()
 	s.wg.Wait()
 	s.feed.scope.Close()
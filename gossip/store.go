@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/zilionixx/go-zilionixx/gossip/evmstore"
+	"github.com/zilionixx/go-zilionixx/gossip/freezer"
 	"github.com/zilionixx/go-zilionixx/gossip/sfcapi"
 	"github.com/zilionixx/go-zilionixx/logger"
 	"github.com/zilionixx/go-zilionixx/utils/rlpstore"
@@ -23,6 +24,8 @@ type Store struct {
 	dbs kvdb.FlushableDBProducer
 	cfg StoreConfig
 
+	consensusDBs *ConsensusDBs // opened consensus databases, may be nil
+
 	async *asyncStore
 
 	mainDB kvdb.Store
@@ -37,6 +40,13 @@ type Store struct {
 		Blocks          kvdb.Store `table:"b"`
 		Genesis         kvdb.Store `table:"g"`
 
+		// Pruning of historical events
+		EventsPruning  kvdb.Store `table:"P"`
+		PrunedEventTxs kvdb.Store `table:"p"`
+
+		// Pruning of historical EVM states
+		StatesPruning kvdb.Store `table:"Z"`
+
 		// P2P-only
 		HighestLamport kvdb.Store `table:"l"`
 
@@ -46,6 +56,16 @@ type Store struct {
 		// API-only
 		BlockHashes kvdb.Store `table:"B"`
 		SfcAPI      kvdb.Store `table:"S"`
+		BlockRules  kvdb.Store `table:"R"`
+	}
+
+	// ancient is a flat-file store of old blocks, receipts and events, or nil
+	ancient struct {
+		db        *freezer.Freezer
+		blocks    *freezer.Table
+		receipts  *freezer.Table
+		events    *freezer.Table
+		lastEvent atomic.Value // hash.Event, the last frozen event
 	}
 
 	prevFlushTime time.Time
@@ -60,6 +80,7 @@ type Store struct {
 		EvmBlocks       *wlru.Cache  `cache:"-"` // store by pointer
 		BlockEpochState atomic.Value // store by value
 		HighestLamport  atomic.Value // store by value
+		RulesHistory    atomic.Value // store by pointer
 	}
 
 	rlp rlpstore.Helper
@@ -101,6 +122,7 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 	s.initCache()
 	s.evm = evmstore.NewStore(s.mainDB, cfg.EVM)
 	s.sfcapi = sfcapi.NewStore(s.table.SfcAPI)
+	s.openAncients()
 
 	if err := s.migrateData(); err != nil {
 		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
@@ -134,6 +156,7 @@ func (s *Store) Close() {
 	_ = s.mainDB.Close()
 	s.async.Close()
 	s.sfcapi.Close()
+	s.closeAncients()
 	_ = s.closeEpochStore()
 }
 
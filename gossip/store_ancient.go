@@ -0,0 +1,301 @@
+package gossip
+
+/*
+	Blocks, receipts and events of old epochs are moved from the DB into the flat-file freezer.
+	Blocks and receipts are addressed by block index, events are appended in the order of their keys,
+	so a frozen event is found by a binary search of its ID.
+*/
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/gossip/freezer"
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+const (
+	ancientBlocksTable   = "blocks"
+	ancientReceiptsTable = "receipts"
+	ancientEventsTable   = "events"
+)
+
+func (s *Store) openAncients() {
+	cfg := s.cfg.Freezer
+	if cfg.Dir == "" || (cfg.Epochs == 0 && !freezer.Exists(cfg.Dir)) {
+		return
+	}
+	db, err := freezer.Open(cfg.Dir, ancientBlocksTable, ancientReceiptsTable, ancientEventsTable)
+	if err != nil {
+		s.Log.Crit("Failed to open the freezer", "dir", cfg.Dir, "err", err)
+	}
+	s.ancient.db = db
+	s.ancient.blocks = db.Table(ancientBlocksTable)
+	s.ancient.receipts = db.Table(ancientReceiptsTable)
+	s.ancient.events = db.Table(ancientEventsTable)
+
+	// blocks and receipts are appended in pairs, drop the unpaired item of an interrupted run
+	blocks, receipts := s.ancient.blocks, s.ancient.receipts
+	if blocks.Empty() || receipts.Empty() {
+		err = blocks.Truncate(blocks.Tail())
+		if err == nil {
+			err = receipts.Truncate(receipts.Tail())
+		}
+	} else if blocks.Head() != receipts.Head() {
+		head := blocks.Head()
+		if receipts.Head() < head {
+			head = receipts.Head()
+		}
+		err = blocks.Truncate(head)
+		if err == nil {
+			err = receipts.Truncate(head)
+		}
+	}
+	if err != nil {
+		s.Log.Crit("Failed to repair the freezer", "err", err)
+	}
+	s.evm.SetAncientReceipts(receipts)
+
+	if events := s.ancient.events; !events.Empty() {
+		id, _ := s.readAncientEvent(events.Head() - 1)
+		s.ancient.lastEvent.Store(id)
+	}
+}
+
+func (s *Store) closeAncients() {
+	if s.ancient.db == nil {
+		return
+	}
+	if err := s.ancient.db.Close(); err != nil {
+		s.Log.Error("Failed to close the freezer", "err", err)
+	}
+	s.ancient.db = nil
+}
+
+// ancientsMoving returns true if the old data has to be moved into the freezer.
+func (s *Store) ancientsMoving() bool {
+	return s.ancient.db != nil && s.cfg.Freezer.Epochs != 0
+}
+
+func (s *Store) getAncientBlock(n idx.Block) *inter.Block {
+	if s.ancient.blocks == nil || !s.ancient.blocks.Has(uint64(n)) {
+		return nil
+	}
+	buf, err := s.ancient.blocks.Get(uint64(n))
+	if err != nil {
+		s.Log.Crit("Failed to read ancient block", "err", err)
+	}
+	block := &inter.Block{}
+	if err := rlp.DecodeBytes(buf, block); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
+	}
+	return block
+}
+
+// lastAncientEvent returns the ID of the last frozen event
+func (s *Store) lastAncientEvent() (hash.Event, bool) {
+	last, ok := s.ancient.lastEvent.Load().(hash.Event)
+	return last, ok
+}
+
+func (s *Store) readAncientEvent(n uint64) (hash.Event, rlp.RawValue) {
+	item, err := s.ancient.events.Get(n)
+	if err != nil {
+		s.Log.Crit("Failed to read ancient event", "err", err)
+	}
+	if len(item) < len(hash.ZeroEvent) {
+		s.Log.Crit("Malformed ancient event", "size", len(item))
+	}
+	return hash.BytesToEvent(item[:len(hash.ZeroEvent)]), item[len(hash.ZeroEvent):]
+}
+
+// searchAncientEvent returns the number of the first frozen event with the key not less than the given one
+func (s *Store) searchAncientEvent(key []byte) uint64 {
+	tail, head := s.ancient.events.Tail(), s.ancient.events.Head()
+	i := sort.Search(int(head-tail), func(i int) bool {
+		id, _ := s.readAncientEvent(tail + uint64(i))
+		return bytes.Compare(id.Bytes(), key) >= 0
+	})
+	return tail + uint64(i)
+}
+
+func (s *Store) getAncientEventRLP(id hash.Event) rlp.RawValue {
+	last, ok := s.lastAncientEvent()
+	if !ok || bytes.Compare(id.Bytes(), last.Bytes()) > 0 {
+		return nil
+	}
+	n := s.searchAncientEvent(id.Bytes())
+	if n >= s.ancient.events.Head() {
+		return nil
+	}
+	found, raw := s.readAncientEvent(n)
+	if found != id {
+		return nil
+	}
+	return raw
+}
+
+func (s *Store) getAncientEvent(id hash.Event) *inter.EventPayload {
+	raw := s.getAncientEventRLP(id)
+	if raw == nil {
+		return nil
+	}
+	e := &inter.EventPayload{}
+	if err := rlp.DecodeBytes(raw, e); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(raw))
+	}
+	fixEventTxHashes(e)
+	return e
+}
+
+// forEachEventRLP iterates the frozen events and then the events in the DB with the given prefix, starting from the key prefix+start.
+func (s *Store) forEachEventRLP(prefix, start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
+	from := append(append([]byte{}, prefix...), start...)
+	if last, ok := s.lastAncientEvent(); ok && bytes.Compare(last.Bytes(), from) >= 0 {
+		for n := s.searchAncientEvent(from); ; n++ {
+			id, raw := s.readAncientEvent(n)
+			if !bytes.HasPrefix(id.Bytes(), prefix) {
+				return
+			}
+			if !onEvent(id, raw) {
+				return
+			}
+			if id == last {
+				break
+			}
+		}
+		// skip the events which are frozen but not deleted from the DB yet
+		if !bytes.HasPrefix(last.Bytes(), prefix) {
+			return
+		}
+		start = append(common.CopyBytes(last.Bytes()[len(prefix):]), 0)
+	}
+
+	it := s.table.Events.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
+			return
+		}
+	}
+}
+
+// freezeAncients copies blocks, receipts and events of epochs before the limit into the freezer.
+// At most maxItems blocks and maxItems events are copied at once.
+func (s *Store) freezeAncients(limit idx.Epoch, maxItems int) (blocks int, events int, err error) {
+	blocks, err = s.freezeAncientBlocks(limit, maxItems)
+	if err != nil {
+		return
+	}
+	// events are moved only if they aren't pruned
+	if !s.eventsPruning() {
+		events, err = s.freezeAncientEvents(limit, maxItems)
+		if err != nil {
+			return
+		}
+	}
+	err = s.ancient.db.Sync()
+	return
+}
+
+func (s *Store) freezeAncientBlocks(limit idx.Epoch, maxItems int) (int, error) {
+	n := idx.Block(s.ancient.blocks.Head())
+	if s.ancient.blocks.Empty() {
+		it := s.table.Blocks.NewIterator(nil, nil)
+		if it.Next() {
+			n = idx.BytesToBlock(it.Key())
+		}
+		it.Release()
+	}
+	copied := 0
+	for ; copied < maxItems; n++ {
+		raw, err := s.table.Blocks.Get(n.Bytes())
+		if err != nil {
+			return copied, err
+		}
+		if raw == nil {
+			break
+		}
+		var block inter.Block
+		if err := rlp.DecodeBytes(raw, &block); err != nil {
+			return copied, err
+		}
+		if block.Atropos.Epoch() >= limit {
+			break
+		}
+		if err := s.ancient.blocks.Append(uint64(n), raw); err != nil {
+			return copied, err
+		}
+		if err := s.ancient.receipts.Append(uint64(n), s.evm.GetRawReceiptsRLP(n)); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+func (s *Store) freezeAncientEvents(limit idx.Epoch, maxItems int) (int, error) {
+	var start []byte
+	if last, ok := s.lastAncientEvent(); ok {
+		start = append(last.Bytes(), 0)
+	}
+	it := s.table.Events.NewIterator(nil, start)
+	defer it.Release()
+	copied := 0
+	for ; copied < maxItems && it.Next(); copied++ {
+		id := hash.BytesToEvent(it.Key())
+		if id.Epoch() >= limit {
+			break
+		}
+		item := append(id.Bytes(), it.Value()...)
+		if err := s.ancient.events.Append(s.ancient.events.Head(), item); err != nil {
+			return copied, err
+		}
+		s.ancient.lastEvent.Store(id)
+	}
+	return copied, it.Error()
+}
+
+// deleteFrozenAncients deletes the blocks, receipts and events which are copied into the freezer from the DB.
+func (s *Store) deleteFrozenAncients() {
+	if !s.ancient.blocks.Empty() {
+		head := idx.Block(s.ancient.blocks.Head())
+		var frozen []idx.Block
+		it := s.table.Blocks.NewIterator(nil, nil)
+		for it.Next() {
+			n := idx.BytesToBlock(it.Key())
+			if n >= head {
+				break
+			}
+			frozen = append(frozen, n)
+		}
+		it.Release()
+		for _, n := range frozen {
+			if err := s.table.Blocks.Delete(n.Bytes()); err != nil {
+				s.Log.Crit("Failed to delete key", "err", err)
+			}
+			s.cache.Blocks.Remove(n)
+			s.evm.DelReceipts(n)
+		}
+	}
+
+	if last, ok := s.lastAncientEvent(); ok {
+		var frozen hash.Events
+		it := s.table.Events.NewIterator(nil, nil)
+		for it.Next() {
+			if bytes.Compare(it.Key(), last.Bytes()) > 0 {
+				break
+			}
+			frozen = append(frozen, hash.BytesToEvent(it.Key()))
+		}
+		it.Release()
+		for _, id := range frozen {
+			s.DelEvent(id)
+		}
+	}
+}
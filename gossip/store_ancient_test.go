@@ -0,0 +1,145 @@
+package gossip
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/kvdb/flushable"
+	"github.com/zilionixx/zilion-base/kvdb/memorydb"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+func TestStoreAncients(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "ancient_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := LiteStoreConfig()
+	cfg.Freezer.Dir = dir
+	cfg.Freezer.Epochs = 1
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+	require.True(store.ancientsMoving())
+
+	// events of epochs 1-4, and a block for each event
+	var events []*inter.EventPayload
+	for epoch := idx.Epoch(1); epoch <= 4; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 5; lamport++ {
+			me := inter.MutableEventPayload{}
+			me.SetEpoch(epoch)
+			me.SetLamport(lamport)
+			me.SetSeq(idx.Event(lamport))
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e)
+		}
+	}
+	var blocks []*inter.Block
+	for i, e := range events {
+		n := idx.Block(i + 1)
+		block := &inter.Block{
+			Atropos:     e.ID(),
+			Events:      hash.Events{e.ID()},
+			Txs:         []common.Hash{},
+			InternalTxs: []common.Hash{},
+			SkippedTxs:  []uint32{},
+			GasUsed:     uint64(i),
+		}
+		store.SetBlock(n, block)
+		blocks = append(blocks, block)
+		if i%2 == 0 {
+			store.EvmStore().SetRawReceipts(n, []*types.ReceiptForStorage{{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: uint64(i),
+				Logs:              []*types.Log{},
+			}})
+		}
+	}
+
+	// data of epochs 1-2 is moved in batches
+	for {
+		movedBlocks, movedEvents, err := store.freezeAncients(3, 3)
+		require.NoError(err)
+		store.deleteFrozenAncients()
+		if movedBlocks == 0 && movedEvents == 0 {
+			break
+		}
+	}
+	require.Equal(uint64(1), store.ancient.blocks.Tail())
+	require.Equal(uint64(11), store.ancient.blocks.Head())
+	require.Equal(uint64(11), store.ancient.receipts.Head())
+	require.Equal(uint64(10), store.ancient.events.Head())
+
+	for i, e := range events {
+		n := idx.Block(i + 1)
+		frozen := e.Epoch() < 3
+		inDB, err := store.table.Events.Has(e.ID().Bytes())
+		require.NoError(err)
+		require.Equal(!frozen, inDB)
+		inDB, err = store.table.Blocks.Has(n.Bytes())
+		require.NoError(err)
+		require.Equal(!frozen, inDB)
+
+		store.cache.Events.Purge()
+		store.cache.EventsHeaders.Purge()
+		store.cache.Blocks.Purge()
+		require.True(store.HasEvent(e.ID()))
+		require.Equal(e.ID(), store.GetEventPayload(e.ID()).ID())
+		require.Equal(e.ID(), store.GetEvent(e.ID()).ID())
+		raw, _ := rlp.EncodeToBytes(e)
+		require.Equal(raw, []byte(store.GetEventPayloadRLP(e.ID())))
+		require.Equal(blocks[i], store.GetBlock(n))
+		require.Equal([]hash.Event{e.ID()}, []hash.Event(store.FindEventHashes(e.Epoch(), e.Lamport(), e.ID().Bytes()[8:12])))
+
+		receipts := store.EvmStore().GetReceipts(n)
+		if i%2 == 0 {
+			require.Len(receipts, 1)
+			require.Equal(uint64(i), receipts[0].CumulativeGasUsed)
+		} else {
+			require.Nil(receipts)
+		}
+	}
+	require.False(store.HasEvent(hash.FakeEvent()))
+
+	// iterations cover both the freezer and the DB
+	var iterated hash.Events
+	store.ForEachEvent(2, func(e *inter.EventPayload) bool {
+		iterated = append(iterated, e.ID())
+		return true
+	})
+	require.Len(iterated, 15)
+	for i, id := range iterated {
+		require.Equal(events[i+5].ID(), id)
+	}
+	iterated = nil
+	store.ForEachEpochEvent(2, func(e *inter.EventPayload) bool {
+		iterated = append(iterated, e.ID())
+		return len(iterated) < 3
+	})
+	require.Len(iterated, 3)
+	require.Equal(events[5].ID(), iterated[0])
+	iterated = nil
+	store.ForEachEventRLP(events[8].ID().Bytes(), func(id hash.Event, _ rlp.RawValue) bool {
+		iterated = append(iterated, id)
+		return true
+	})
+	require.Len(iterated, len(events)-8)
+	require.Equal(events[8].ID(), iterated[0])
+
+	var blockNums []idx.Block
+	store.ForEachBlock(func(n idx.Block, block *inter.Block) {
+		blockNums = append(blockNums, n)
+		require.Equal(blocks[n-1], block)
+	})
+	require.Len(blockNums, len(blocks))
+}
@@ -43,6 +43,9 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	if block == nil {
+		block = s.getAncientBlock(n)
+	}
 
 	// Add to LRU cache.
 	if block != nil {
@@ -53,7 +56,15 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 }
 
 func (s *Store) ForEachBlock(fn func(index idx.Block, block *inter.Block)) {
-	it := s.table.Blocks.NewIterator(nil, nil)
+	var start []byte
+	if s.ancient.blocks != nil && !s.ancient.blocks.Empty() {
+		head := idx.Block(s.ancient.blocks.Head())
+		for n := idx.Block(s.ancient.blocks.Tail()); n < head; n++ {
+			fn(n, s.getAncientBlock(n))
+		}
+		start = head.Bytes()
+	}
+	it := s.table.Blocks.NewIterator(nil, start)
 	defer it.Release()
 	for it.Next() {
 		var block inter.Block
@@ -0,0 +1,17 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/zilionixx/zilion-base/inter/idx"
+)
+
+// sectionBlooms returns blooms of the blocks of the bloom-bits index section, which are built from the blocks receipts
+func (s *Store) sectionBlooms(section uint64) []types.Bloom {
+	size := s.evm.EvmBlooms().SectionSize()
+	blooms := make([]types.Bloom, size)
+	for i := range blooms {
+		receipts := s.evm.GetReceipts(idx.Block(section*size + uint64(i)))
+		blooms[i] = types.CreateBloom(receipts)
+	}
+	return blooms
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/zilionixx/zilion-base/hash"
 	"github.com/zilionixx/zilion-base/inter/idx"
-	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/zilionixx/go-zilionixx/inter"
@@ -53,6 +52,8 @@ func (s *Store) GetEventPayload(id hash.Event) *inter.EventPayload {
 
 	if w != nil {
 		fixEventTxHashes(w)
+	} else {
+		w = s.getAncientEvent(id)
 	}
 
 	// Put event to LRU cache.
@@ -74,10 +75,14 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 
 	key := id.Bytes()
 	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	if w != nil {
+		fixEventTxHashes(w)
+	} else {
+		w = s.getAncientEvent(id)
+	}
 	if w == nil {
 		return nil
 	}
-	fixEventTxHashes(w)
 
 	eh := w.Event
 
@@ -88,40 +93,28 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 	return &eh
 }
 
-func (s *Store) forEachEvent(it ethdb.Iterator, onEvent func(event *inter.EventPayload) bool) {
-	for it.Next() {
+func (s *Store) forEachEvent(prefix, start []byte, onEvent func(event *inter.EventPayload) bool) {
+	s.forEachEventRLP(prefix, start, func(_ hash.Event, raw rlp.RawValue) bool {
 		event := &inter.EventPayload{}
-		err := rlp.DecodeBytes(it.Value(), event)
+		err := rlp.DecodeBytes(raw, event)
 		if err != nil {
 			s.Log.Crit("Failed to decode event", "err", err)
 		}
 
-		if !onEvent(event) {
-			return
-		}
-	}
+		return onEvent(event)
+	})
 }
 
 func (s *Store) ForEachEpochEvent(epoch idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
-	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(epoch.Bytes(), nil, onEvent)
 }
 
 func (s *Store) ForEachEvent(start idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
-	it := s.table.Events.NewIterator(nil, start.Bytes())
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(nil, start.Bytes(), onEvent)
 }
 
 func (s *Store) ForEachEventRLP(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
-	it := s.table.Events.NewIterator(nil, start)
-	defer it.Release()
-	for it.Next() {
-		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
-			return
-		}
-	}
+	s.forEachEventRLP(nil, start, onEvent)
 }
 
 func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix []byte) hash.Events {
@@ -130,11 +123,10 @@ func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix
 	prefix.Write(hashPrefix)
 	res := make(hash.Events, 0, 10)
 
-	it := s.table.Events.NewIterator(prefix.Bytes(), nil)
-	defer it.Release()
-	for it.Next() {
-		res = append(res, hash.BytesToEvent(it.Key()))
-	}
+	s.forEachEventRLP(prefix.Bytes(), nil, func(key hash.Event, _ rlp.RawValue) bool {
+		res = append(res, key)
+		return true
+	})
 
 	return res
 }
@@ -147,13 +139,16 @@ func (s *Store) GetEventPayloadRLP(id hash.Event) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if data == nil {
+		data = s.getAncientEventRLP(id)
+	}
 	return data
 }
 
 // HasEvent returns true if event exists.
 func (s *Store) HasEvent(h hash.Event) bool {
 	has, _ := s.table.Events.Has(h.Bytes())
-	return has
+	return has || s.getAncientEventRLP(h) != nil
 }
 
 func (s *Store) loadHighestLamport() idx.Lamport {
@@ -0,0 +1,127 @@
+package gossip
+
+/*
+	Events of old epochs are pruned, while blocks and receipts are kept.
+	Transactions of a pruned event are moved into the EVM transactions table,
+	so that the blocks which reference the event can still be assembled.
+*/
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+// GetPrunedEventsEpoch returns the first epoch whose events are kept. Events of older epochs may be pruned.
+func (s *Store) GetPrunedEventsEpoch() idx.Epoch {
+	b, err := s.table.EventsPruning.Get([]byte("e"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToEpoch(b)
+}
+
+func (s *Store) setPrunedEventsEpoch(epoch idx.Epoch) {
+	if err := s.table.EventsPruning.Put([]byte("e"), epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// eventsPruning returns true if the historical events have to be pruned.
+func (s *Store) eventsPruning() bool {
+	return s.cfg.EventsPruner.Epochs != 0
+}
+
+// getPrunedEventTxs returns transactions of a pruned event
+func (s *Store) getPrunedEventTxs(id hash.Event) (types.Transactions, bool) {
+	b, err := s.table.PrunedEventTxs.Get(id.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return nil, false
+	}
+	var hashes []common.Hash
+	if err := rlp.DecodeBytes(b, &hashes); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(b))
+	}
+	txs := make(types.Transactions, 0, len(hashes))
+	for _, h := range hashes {
+		tx := s.getPrunedEventTx(h)
+		if tx == nil {
+			s.Log.Crit("Tx of pruned event not found", "event", id.String(), "tx", h.String())
+		}
+		txs = append(txs, tx)
+	}
+	return txs, true
+}
+
+// getPrunedEventTx returns a transaction of a pruned event
+func (s *Store) getPrunedEventTx(h common.Hash) *types.Transaction {
+	tx := s.evm.GetTx(h)
+	if tx != nil {
+		// hashes of a few transactions were fixed, see fixEventTxHashes
+		tx.SetHash(h)
+	}
+	return tx
+}
+
+// protectedEvents returns the events which are referenced by the epoch and block states
+func (s *Store) protectedEvents() map[hash.Event]bool {
+	protected := make(map[hash.Event]bool)
+	for _, v := range s.GetEpochState().ValidatorStates {
+		protected[v.PrevEpochEvent] = true
+	}
+	for _, v := range s.GetBlockState().ValidatorStates {
+		protected[v.LastEvent] = true
+	}
+	return protected
+}
+
+// pruneEvents deletes at most maxItems events of epochs before the limit.
+// Transactions of the pruned events are kept.
+func (s *Store) pruneEvents(limit idx.Epoch, maxItems int) int {
+	if s.GetPrunedEventsEpoch() < limit {
+		s.setPrunedEventsEpoch(limit)
+	}
+	protected := s.protectedEvents()
+
+	var pruned []*inter.EventPayload
+	it := s.table.Events.NewIterator(nil, nil)
+	for len(pruned) < maxItems && it.Next() {
+		id := hash.BytesToEvent(it.Key())
+		if id.Epoch() >= limit {
+			break
+		}
+		if protected[id] {
+			continue
+		}
+		e := &inter.EventPayload{}
+		if err := rlp.DecodeBytes(it.Value(), e); err != nil {
+			s.Log.Crit("Failed to decode event", "err", err)
+		}
+		fixEventTxHashes(e)
+		pruned = append(pruned, e)
+	}
+	it.Release()
+
+	for _, e := range pruned {
+		if e.Txs().Len() != 0 {
+			hashes := make([]common.Hash, e.Txs().Len())
+			for i, tx := range e.Txs() {
+				hashes[i] = tx.Hash()
+				s.evm.SetTx(hashes[i], tx)
+			}
+			s.rlp.Set(s.table.PrunedEventTxs, e.ID().Bytes(), hashes)
+		}
+		s.DelEvent(e.ID())
+	}
+	return len(pruned)
+}
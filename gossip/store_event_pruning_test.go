@@ -0,0 +1,164 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/gossip/dagstream"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+
+	"github.com/zilionixx/go-zilionixx/gossip/blockproc"
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+func TestStorePruneEvents(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	// events of epochs 1-4, every second event has a transaction
+	var events []*inter.EventPayload
+	for epoch := idx.Epoch(1); epoch <= 4; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 5; lamport++ {
+			me := inter.MutableEventPayload{}
+			me.SetEpoch(epoch)
+			me.SetLamport(lamport)
+			if lamport%2 == 0 {
+				me.SetTxs(types.Transactions{types.NewTransaction(uint64(epoch)*10+uint64(lamport), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)})
+			}
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e)
+		}
+	}
+	protected := events[1].ID()
+	store.SetBlockEpochState(blockproc.BlockState{
+		ValidatorStates: []blockproc.ValidatorBlockState{{LastEvent: protected}},
+		DirtyRules:      zilionixx.FakeNetRules(),
+	}, blockproc.EpochState{
+		Epoch: 5,
+		Rules: zilionixx.FakeNetRules(),
+	})
+	require.Equal(idx.Epoch(0), store.GetPrunedEventsEpoch())
+
+	// events of epochs 1-2 are pruned in batches
+	total := 0
+	for {
+		pruned := store.pruneEvents(3, 3)
+		if pruned == 0 {
+			break
+		}
+		total += pruned
+	}
+	require.Equal(9, total)
+	require.Equal(idx.Epoch(3), store.GetPrunedEventsEpoch())
+
+	store.cache.Events.Purge()
+	store.cache.EventsHeaders.Purge()
+	for _, e := range events {
+		kept := e.Epoch() >= 3 || e.ID() == protected
+		require.Equal(kept, store.HasEvent(e.ID()), e.ID().String())
+		require.Equal(kept, store.GetEventPayload(e.ID()) != nil)
+		require.Equal(kept, len(store.FindEventHashes(e.Epoch(), e.Lamport(), e.ID().Bytes()[8:12])) != 0)
+
+		txs, ok := store.getPrunedEventTxs(e.ID())
+		require.Equal(!kept && e.Txs().Len() != 0, ok)
+		if ok {
+			require.Equal(e.Txs().Len(), txs.Len())
+			for i, tx := range txs {
+				require.Equal(e.Txs()[i].Hash(), tx.Hash())
+				raw1, _ := rlp.EncodeToBytes(e.Txs()[i])
+				raw2, _ := rlp.EncodeToBytes(tx)
+				require.Equal(raw1, raw2)
+			}
+		}
+	}
+
+	var iterated hash.Events
+	store.ForEachEvent(1, func(e *inter.EventPayload) bool {
+		iterated = append(iterated, e.ID())
+		return true
+	})
+	require.Equal(hash.Events{protected}, iterated[:1])
+	require.Len(iterated, 11)
+}
+
+func TestEventsStreamResponsePruned(t *testing.T) {
+	require := require.New(t)
+
+	e := inter.MutableEventPayload{}
+	e.SetEpoch(2)
+	event := e.Build()
+	raw, err := rlp.EncodeToBytes(event)
+	require.NoError(err)
+
+	for _, pruned := range [][]idx.Epoch{nil, {5}} {
+		b, err := rlp.EncodeToBytes(eventsStreamResponse{
+			SessionID:    1,
+			Done:         true,
+			Events:       []interface{}{rlp.RawValue(raw)},
+			PrunedBefore: pruned,
+		})
+		require.NoError(err)
+
+		var chunk epochChunk
+		require.NoError(rlp.DecodeBytes(b, &chunk))
+		require.Equal(uint32(1), chunk.SessionID)
+		require.True(chunk.Done)
+		require.Equal(event.ID(), chunk.Events[0].ID())
+		require.Equal(len(pruned), len(chunk.PrunedBefore))
+		if pruned != nil {
+			require.Equal(pruned, chunk.PrunedBefore)
+		}
+	}
+}
+
+func TestEventsStreamPrunedProtocolVersion(t *testing.T) {
+	require := require.New(t)
+
+	// chunk format of the versions before zilionixx64
+	type legacyChunk struct {
+		SessionID uint32
+		Done      bool
+		IDs       hash.Events
+		Events    inter.EventPayloads
+	}
+
+	for _, version := range []int{zilionixx63, zilionixx64} {
+		in, out := p2p.MsgPipe()
+		p := NewPeer(version, p2p.NewPeer(enode.ID{1}, "", nil), in, DefaultPeerCacheConfig(cachescale.Identity))
+		go func() {
+			_ = p.SendEventsStream(dagstream.Response{SessionID: 1, Done: true}, nil, 5)
+		}()
+		msg, err := out.ReadMsg()
+		require.NoError(err)
+		require.Equal(uint64(EventsStreamResponse), msg.Code)
+
+		if version < zilionixx64 {
+			var chunk legacyChunk
+			require.NoError(msg.Decode(&chunk))
+		} else {
+			var chunk epochChunk
+			require.NoError(msg.Decode(&chunk))
+			require.Equal([]idx.Epoch{5}, chunk.PrunedBefore)
+		}
+		in.Close()
+	}
+
+	p := NewPeer(zilionixx64, p2p.NewPeer(enode.ID{1}, "", nil), nil, DefaultPeerCacheConfig(cachescale.Identity))
+	require.True(p.ServesEventsOf(1))
+	p.SetPrunedEventsBefore(5)
+	p.SetPrunedEventsBefore(3)
+	require.False(p.ServesEventsOf(4))
+	require.True(p.ServesEventsOf(5))
+}
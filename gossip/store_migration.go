@@ -37,7 +37,8 @@ func (s *Store) migrations() *migration.Migration {
 		Next("used gas recovery", s.recoverUsedGas).
 		Next("tx hashes recovery", s.recoverTxHashes).
 		Next("DAG heads recovery", s.recoverHeadsStorage).
-		Next("DAG last events recovery", s.recoverLastEventsStorage)
+		Next("DAG last events recovery", s.recoverLastEventsStorage).
+		Next("EVM states pruning horizon", s.recoverStatesPruning)
 }
 
 func (s *Store) recoverUsedGas() error {
@@ -251,3 +252,10 @@ func (s *Store) recoverLastEventsStorage() error {
 	es.FlushLastEvents()
 	return nil
 }
+
+// recoverStatesPruning sets the states pruning horizon to the latest block,
+// because states of the older blocks may be pruned before the horizon is recorded.
+func (s *Store) recoverStatesPruning() error {
+	s.SetStatesPrunedBlock(s.GetLatestBlockIndex())
+	return nil
+}
@@ -0,0 +1,85 @@
+package gossip
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+type (
+	// rulesRecord is the network rules which are applied starting from the block
+	rulesRecord struct {
+		Block idx.Block
+		Rules zilionixx.Rules
+	}
+
+	// rulesHistory is the sorted rules records, along with the epoch of the latest recorded block
+	rulesHistory struct {
+		records []rulesRecord
+		epoch   idx.Epoch
+	}
+)
+
+// SetBlockRules records the rules applied to the block of the epoch, if they differ from the rules of the previous blocks.
+// Rules may change only at an epoch sealing, so blocks of an already recorded epoch are skipped.
+// Not safe for concurrent use.
+func (s *Store) SetBlockRules(n idx.Block, epoch idx.Epoch, rules zilionixx.Rules) {
+	history := s.getRulesHistory()
+	if len(history.records) != 0 && history.epoch == epoch {
+		return
+	}
+	records := history.records
+	if len(records) == 0 || !rulesEqual(records[len(records)-1].Rules, rules) {
+		s.rlp.Set(s.table.BlockRules, n.Bytes(), &rules)
+		records = append(append(make([]rulesRecord, 0, len(records)+1), records...), rulesRecord{n, rules.Copy()})
+	}
+	s.cache.RulesHistory.Store(&rulesHistory{
+		records: records,
+		epoch:   epoch,
+	})
+}
+
+// GetBlockRules returns the rules which were applied to the block, or nil if they aren't recorded.
+// Blocks before the first record, i.e. blocks of a DB created before the rules history, use the first recorded rules.
+func (s *Store) GetBlockRules(n idx.Block) *zilionixx.Rules {
+	records := s.getRulesHistory().records
+	if len(records) == 0 {
+		return nil
+	}
+	i := sort.Search(len(records), func(i int) bool {
+		return records[i].Block > n
+	})
+	if i != 0 {
+		i--
+	}
+	rules := records[i].Rules.Copy()
+	return &rules
+}
+
+func (s *Store) getRulesHistory() *rulesHistory {
+	if v := s.cache.RulesHistory.Load(); v != nil {
+		return v.(*rulesHistory)
+	}
+	history := &rulesHistory{}
+	it := s.table.BlockRules.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		var rules zilionixx.Rules
+		if err := rlp.DecodeBytes(it.Value(), &rules); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err)
+		}
+		history.records = append(history.records, rulesRecord{idx.BytesToBlock(it.Key()), rules})
+	}
+	s.cache.RulesHistory.Store(history)
+	return history
+}
+
+func rulesEqual(a, b zilionixx.Rules) bool {
+	aBytes, _ := rlp.EncodeToBytes(&a)
+	bBytes, _ := rlp.EncodeToBytes(&b)
+	return bytes.Equal(aBytes, bBytes)
+}
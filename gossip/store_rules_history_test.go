@@ -0,0 +1,45 @@
+package gossip
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+func TestStoreBlockRules(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	require.Nil(store.GetBlockRules(1))
+
+	pre := zilionixx.FakeNetRules()
+	pre.Upgrades.London = false
+	post := pre.Copy()
+	post.Upgrades.London = true
+
+	store.SetBlockRules(2, 1, pre)
+	store.SetBlockRules(3, 1, post) // rules of a recorded epoch are skipped
+	store.SetBlockRules(5, 2, pre)  // same rules aren't recorded again
+	store.SetBlockRules(7, 3, post)
+	store.SetBlockRules(8, 4, post)
+
+	expect := func(store *Store) {
+		for n, london := range map[idx.Block]bool{1: false, 2: false, 3: false, 6: false, 7: true, 8: true, 100: true} {
+			rules := store.GetBlockRules(n)
+			require.NotNil(rules, n)
+			require.Equal(london, rules.Upgrades.London, n)
+		}
+	}
+	expect(store)
+
+	// the history is restored from the DB
+	store.cache.RulesHistory = atomic.Value{}
+	expect(store)
+	require.Len(store.getRulesHistory().records, 2)
+}
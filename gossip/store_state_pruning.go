@@ -0,0 +1,25 @@
+package gossip
+
+import (
+	"github.com/zilionixx/zilion-base/inter/idx"
+)
+
+// GetStatesPrunedBlock returns the latest block whose EVM state may be pruned.
+// States of the newer blocks are kept.
+func (s *Store) GetStatesPrunedBlock() idx.Block {
+	b, err := s.table.StatesPruning.Get([]byte("b"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToBlock(b)
+}
+
+// SetStatesPrunedBlock stores the latest block whose EVM state may be pruned.
+func (s *Store) SetStatesPrunedBlock(n idx.Block) {
+	if err := s.table.StatesPruning.Put([]byte("b"), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
@@ -0,0 +1,127 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zilionixx/zilion-base/abft"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/kvdb"
+
+	"github.com/zilionixx/go-zilionixx/bloomsdb"
+	"github.com/zilionixx/go-zilionixx/gossip/evmstore"
+	"github.com/zilionixx/go-zilionixx/gossip/sfcapi"
+	"github.com/zilionixx/go-zilionixx/topicsdb"
+	"github.com/zilionixx/go-zilionixx/utils/dbstats"
+)
+
+// DBTables returns the logical tables of a gossip or consensus database, or nil if the database is unknown.
+func DBTables(name string) []dbstats.Table {
+	switch {
+	case name == "zilionbft":
+		return dbstats.StructTables(abft.Store{}, "table", "", nil)
+	case strings.HasPrefix(name, "zilionbft-"):
+		return dbstats.StructTables(abft.Store{}, "epochTable", "", nil)
+	case name == "gossip":
+		tables := dbstats.StructTables(Store{}, "table", "", nil)
+		tables = append(tables, dbstats.StructTables(sfcapi.Store{}, "table", "SfcAPI", []byte("S"))...)
+		tables = append(tables, dbstats.StructTables(evmstore.Store{}, "table", "", nil)...)
+		tables = append(tables, dbstats.StructTables(topicsdb.Index{}, "table", "EvmLogs", []byte("L"))...)
+		tables = append(tables, dbstats.StructTables(bloomsdb.Index{}, "table", "EvmBlooms", []byte("F"))...)
+		tables = append(tables, dbstats.Table{Name: "Evm", Prefix: []byte("M")})
+		return tables
+	case name == "gossip-async":
+		return dbstats.StructTables(asyncStore{}, "table", "", nil)
+	case strings.HasPrefix(name, "gossip-"):
+		return dbstats.StructTables(epochStore{}, "table", "", nil)
+	}
+	return nil
+}
+
+// ConsensusDBs tracks the opened consensus databases, which are
+// included into the stats and compaction of the gossip databases.
+type ConsensusDBs struct {
+	mu      sync.Mutex
+	main    kvdb.Store
+	epoch   idx.Epoch
+	epochDB kvdb.Store
+}
+
+// NewConsensusDBs creates the tracker of the consensus databases.
+func NewConsensusDBs(main kvdb.Store) *ConsensusDBs {
+	return &ConsensusDBs{
+		main: main,
+	}
+}
+
+// SetEpochDB remembers the opened epoch database, it replaces the database of the previous epoch.
+func (c *ConsensusDBs) SetEpochDB(epoch idx.Epoch, db kvdb.Store) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch, c.epochDB = epoch, db
+}
+
+// SetConsensusDBs sets the opened consensus databases.
+func (s *Store) SetConsensusDBs(dbs *ConsensusDBs) {
+	s.consensusDBs = dbs
+}
+
+// openedDBs returns the opened gossip and consensus databases by their names
+func (s *Store) openedDBs() map[string]kvdb.Store {
+	dbs := map[string]kvdb.Store{
+		"gossip":       s.mainDB,
+		"gossip-async": s.async.mainDB,
+	}
+	if es := s.getAnyEpochStore(); es != nil {
+		dbs[fmt.Sprintf("gossip-%d", es.epoch)] = es.db
+	}
+	if c := s.consensusDBs; c != nil {
+		c.mu.Lock()
+		dbs["zilionbft"] = c.main
+		if c.epochDB != nil {
+			dbs[fmt.Sprintf("zilionbft-%d", c.epoch)] = c.epochDB
+		}
+		c.mu.Unlock()
+	}
+	return dbs
+}
+
+// DBStats counts the keys of every table of the opened gossip and consensus databases.
+func (s *Store) DBStats(ctx context.Context) ([]dbstats.Stats, error) {
+	dbs := s.openedDBs()
+	names := make([]string, 0, len(dbs))
+	for name := range dbs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stats []dbstats.Stats
+	for _, name := range names {
+		dbStats, err := dbstats.Collect(ctx, name, dbs[name], DBTables(name))
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, dbStats...)
+	}
+	return stats, nil
+}
+
+// CompactDB compacts the key range of the table of an opened gossip or consensus database, or the whole database if the table is empty.
+func (s *Store) CompactDB(name, table string) error {
+	db := s.openedDBs()[name]
+	if db == nil {
+		return fmt.Errorf("database %s isn't opened", name)
+	}
+	if table == "" {
+		return db.Compact(nil, nil)
+	}
+	t, ok := dbstats.Find(DBTables(name), table)
+	if !ok {
+		return fmt.Errorf("table %s isn't found in %s", table, name)
+	}
+	s.Log.Info("Compacting database table", "db", name, "table", table)
+	return db.Compact(t.Prefix, dbstats.PrefixEnd(t.Prefix))
+}
@@ -0,0 +1,68 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+func TestStoreDBStats(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	for n := idx.Block(1); n <= 3; n++ {
+		store.SetBlock(n, &inter.Block{Atropos: hash.FakeEvent()})
+	}
+
+	stats, err := store.DBStats(context.Background())
+	require.NoError(err)
+	var blocks bool
+	for _, s := range stats {
+		if s.DB == "gossip" && s.Table == "Blocks" {
+			blocks = true
+			require.Equal(uint64(3), s.Keys)
+			require.NotZero(s.ValuesSize)
+		}
+	}
+	require.True(blocks)
+
+	require.NoError(store.CompactDB("gossip", "Blocks"))
+	require.NoError(store.CompactDB("gossip", ""))
+	require.Error(store.CompactDB("gossip", "unknown"))
+	require.Error(store.CompactDB("unknown", ""))
+
+	// consensus databases are included once they're set
+	cMainDB, err := store.dbs.OpenDB("zilionbft")
+	require.NoError(err)
+	cEpochDB, err := store.dbs.OpenDB("zilionbft-2")
+	require.NoError(err)
+	require.NoError(cMainDB.Put([]byte("c"), []byte{1}))
+	require.NoError(cEpochDB.Put([]byte("r1"), []byte{1}))
+	cdbs := NewConsensusDBs(cMainDB)
+	store.SetConsensusDBs(cdbs)
+	cdbs.SetEpochDB(2, cEpochDB)
+	stats, err = store.DBStats(context.Background())
+	require.NoError(err)
+	tables := map[string]string{}
+	for _, s := range stats {
+		tables[s.DB] = s.Table
+	}
+	require.Equal("LastDecidedState", tables["zilionbft"])
+	require.Equal("Roots", tables["zilionbft-2"])
+	require.NoError(store.CompactDB("zilionbft", "LastDecidedState"))
+	require.NoError(store.CompactDB("zilionbft-2", "Roots"))
+
+	// the epoch database is replaced by the next one
+	cNextEpochDB, err := store.dbs.OpenDB("zilionbft-3")
+	require.NoError(err)
+	cdbs.SetEpochDB(3, cNextEpochDB)
+	require.Error(store.CompactDB("zilionbft-2", ""))
+	require.NoError(store.CompactDB("zilionbft-3", ""))
+}
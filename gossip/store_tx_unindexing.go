@@ -0,0 +1,91 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+// GetTxIndexTail returns the first block whose transactions are indexed, or nil if no transactions were unindexed.
+func (s *Store) GetTxIndexTail() *idx.Block {
+	return s.evm.GetTxIndexTail()
+}
+
+// GetTxPruned returns the block of the transaction if it was removed from the transactions index, or nil otherwise.
+func (s *Store) GetTxPruned(txid common.Hash) *idx.Block {
+	return s.evm.GetTxPruned(txid)
+}
+
+// blockTxHashes returns hashes of all the transactions of the block, including the skipped ones
+func (s *Store) blockTxHashes(block *inter.Block) []common.Hash {
+	hashes := make([]common.Hash, 0, len(block.InternalTxs)+len(block.Txs))
+	hashes = append(hashes, block.InternalTxs...)
+	hashes = append(hashes, block.Txs...)
+	for _, id := range block.Events {
+		if e := s.GetEventPayload(id); e != nil {
+			for _, tx := range e.Txs() {
+				hashes = append(hashes, tx.Hash())
+			}
+		} else if txs, ok := s.getPrunedEventTxs(id); ok {
+			for _, tx := range txs {
+				hashes = append(hashes, tx.Hash())
+			}
+		}
+	}
+	return hashes
+}
+
+// txsUnindexBatch is a batch of transactions of the consecutive blocks which are unindexed at once
+type txsUnindexBatch struct {
+	txs    []blockTx
+	blocks int
+	// tail is the first block after the batch
+	tail idx.Block
+}
+
+// blockTx is a transaction of a block
+type blockTx struct {
+	hash  common.Hash
+	block idx.Block
+}
+
+// collectUnindexTxs collects transactions of at most maxBlocks blocks before the given one,
+// starting from the tail of the transactions index. Doesn't modify the store.
+func (s *Store) collectUnindexTxs(before idx.Block, maxBlocks int) txsUnindexBatch {
+	var n idx.Block
+	if tail := s.evm.GetTxIndexTail(); tail != nil {
+		n = *tail
+	} else if genesis := s.GetGenesisBlockIndex(); genesis != nil {
+		n = *genesis
+	}
+	batch := txsUnindexBatch{}
+	for ; n < before && batch.blocks < maxBlocks; n++ {
+		block := s.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		for _, h := range s.blockTxHashes(block) {
+			batch.txs = append(batch.txs, blockTx{h, n})
+		}
+		batch.blocks++
+	}
+	batch.tail = n
+	return batch
+}
+
+// unindexTxs deletes positions of the collected transactions and advances the tail of the transactions index.
+// The deleted transactions are marked as pruned, so they aren't reported as unknown.
+func (s *Store) unindexTxs(batch txsUnindexBatch) {
+	if batch.blocks == 0 {
+		return
+	}
+	for _, tx := range batch.txs {
+		// a skipped transaction may be indexed in a later block
+		if position := s.evm.GetTxPosition(tx.hash); position != nil && position.Block == tx.block {
+			s.evm.DelTxPosition(tx.hash)
+			s.evm.SetTxPruned(tx.hash, tx.block)
+		}
+	}
+	s.evm.SetTxIndexTail(batch.tail)
+}
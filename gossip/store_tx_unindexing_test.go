@@ -0,0 +1,80 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/gossip/evmstore"
+	"github.com/zilionixx/go-zilionixx/inter"
+)
+
+func TestStoreUnindexTxs(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	// blocks 1-6 with a transaction each, the transaction of block 5 is also skipped in block 2
+	txOf := func(n idx.Block) common.Hash {
+		return common.BytesToHash(n.Bytes())
+	}
+	for n := idx.Block(1); n <= 6; n++ {
+		block := &inter.Block{
+			Atropos: hash.FakeEvent(),
+			Txs:     []common.Hash{txOf(n)},
+		}
+		if n == 2 {
+			block.Txs = append(block.Txs, txOf(5))
+		}
+		store.SetBlock(n, block)
+		store.EvmStore().SetTxPosition(txOf(n), evmstore.TxPosition{Block: n})
+	}
+	require.Nil(store.GetTxIndexTail())
+
+	unindexTxs := func(before idx.Block, maxBlocks int) int {
+		batch := store.collectUnindexTxs(before, maxBlocks)
+		store.unindexTxs(batch)
+		return batch.blocks
+	}
+
+	// blocks before 4 are unindexed in batches
+	require.Equal(2, unindexTxs(4, 2))
+	require.Equal(idx.Block(3), *store.GetTxIndexTail())
+	require.Equal(1, unindexTxs(4, 2))
+	require.Equal(idx.Block(4), *store.GetTxIndexTail())
+	require.Equal(0, unindexTxs(4, 2))
+	require.Equal(idx.Block(4), *store.GetTxIndexTail())
+
+	for n := idx.Block(1); n <= 6; n++ {
+		position := store.EvmStore().GetTxPosition(txOf(n))
+		pruned := store.GetTxPruned(txOf(n))
+		if n < 4 {
+			require.Nil(position)
+			require.NotNil(pruned)
+			require.Equal(n, *pruned)
+		} else {
+			require.NotNil(position)
+			require.Equal(n, position.Block)
+			require.Nil(pruned)
+		}
+	}
+
+	// collected transactions aren't unindexed until the batch is applied
+	batch := store.collectUnindexTxs(6, 10)
+	require.Equal(2, batch.blocks)
+	require.Equal(idx.Block(4), *store.GetTxIndexTail())
+	require.NotNil(store.EvmStore().GetTxPosition(txOf(4)))
+
+	// the tail is advanced further as the chain grows
+	require.Equal(2, unindexTxs(6, 10))
+	require.Equal(idx.Block(6), *store.GetTxIndexTail())
+	require.Nil(store.EvmStore().GetTxPosition(txOf(5)))
+	require.NotNil(store.EvmStore().GetTxPosition(txOf(6)))
+
+	// unknown transactions aren't reported as pruned
+	require.Nil(store.GetTxPruned(common.Hash{0xff}))
+}
@@ -0,0 +1,267 @@
+package gossip
+
+/*
+	Consistency checks of the stored data, which may be broken by an unclean shutdown.
+	Heads and last events are rebuilt from the epoch events, blocks beyond the latest block state are deleted.
+	Other inconsistencies are only reported.
+*/
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/utils/concurrent"
+)
+
+// VerifyIssue is an inconsistency of the stored data.
+type VerifyIssue struct {
+	Check    string
+	Err      error
+	Repaired bool
+}
+
+// VerifyReport is a result of the stored data verification.
+type VerifyReport struct {
+	Blocks int
+	// MissingStates is a number of blocks whose EVM state is pruned.
+	// States of old blocks aren't needed to continue the chain,
+	// missing states of the blocks after the pruning horizon are reported as issues.
+	MissingStates int
+	Issues        []VerifyIssue
+}
+
+func (r *VerifyReport) add(check string, repaired bool, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, VerifyIssue{
+		Check:    check,
+		Err:      fmt.Errorf(format, args...),
+		Repaired: repaired,
+	})
+}
+
+// Verify cross-checks the latest block and epoch state with the blocks, the blocks with the events and EVM states,
+// and the heads and last events of the current epoch with the epoch events. Fixable issues are repaired if repair is true.
+// Repaired data has to be committed by the caller.
+func (s *Store) Verify(ctx context.Context, repair bool) (*VerifyReport, error) {
+	report := &VerifyReport{}
+	s.verifyBlockState(report, repair)
+	if err := s.verifyBlocks(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.verifyEpochEvents(ctx, report, repair); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// verifyBlockState checks that the latest block state matches the latest stored block.
+func (s *Store) verifyBlockState(report *VerifyReport, repair bool) {
+	const check = "state"
+	bs, es := s.GetBlockEpochState()
+	last := bs.LastBlock
+
+	block := s.GetBlock(last.Idx)
+	if block == nil {
+		report.add(check, false, "latest block %d isn't found", last.Idx)
+	} else {
+		if block.Atropos != last.Atropos {
+			report.add(check, false, "latest block %d atropos mismatch: state %s, block %s", last.Idx, last.Atropos.String(), block.Atropos.String())
+		}
+		if block.Time != last.Time {
+			report.add(check, false, "latest block %d time mismatch: state %d, block %d", last.Idx, last.Time, block.Time)
+		}
+		if block.Root != bs.FinalizedStateRoot {
+			report.add(check, false, "latest block %d root mismatch: state %s, block %s", last.Idx, bs.FinalizedStateRoot.String(), block.Root.String())
+		}
+	}
+	if last.Atropos != hash.ZeroEvent {
+		if last.Atropos.Epoch() > es.Epoch {
+			report.add(check, false, "latest block atropos %s is from a future epoch %d", last.Atropos.String(), es.Epoch)
+		}
+		if n := s.GetBlockIndex(last.Atropos); n == nil || *n != last.Idx {
+			if repair {
+				s.SetBlockIndex(last.Atropos, last.Idx)
+			}
+			report.add(check, repair, "latest block %d isn't indexed by its atropos %s", last.Idx, last.Atropos.String())
+		}
+	}
+	if !s.hasStateRoot(bs.FinalizedStateRoot) {
+		report.add(check, false, "EVM state %s of the latest block %d isn't found", bs.FinalizedStateRoot.String(), last.Idx)
+	}
+	if !s.hasStateRoot(es.EpochStateRoot) {
+		report.add(check, false, "EVM state %s of the epoch %d start isn't found", es.EpochStateRoot.String(), es.Epoch)
+	}
+
+	// blocks which are written after the latest block state
+	var beyond []idx.Block
+	it := s.table.Blocks.NewIterator(nil, (last.Idx + 1).Bytes())
+	for it.Next() {
+		beyond = append(beyond, idx.BytesToBlock(it.Key()))
+	}
+	it.Release()
+	for _, n := range beyond {
+		if repair {
+			s.delBlock(n)
+		}
+		report.add(check, repair, "block %d is beyond the latest block %d", n, last.Idx)
+	}
+}
+
+// verifyBlocks checks that events and EVM states of the blocks exist.
+func (s *Store) verifyBlocks(ctx context.Context, report *VerifyReport) (err error) {
+	const check = "blocks"
+	prunedEpoch := s.GetPrunedEventsEpoch()
+	prunedStates := s.GetStatesPrunedBlock()
+	last := s.GetLatestBlockIndex()
+
+	// missing states are reported by ranges of blocks
+	var missingFrom, missingTo idx.Block
+	reportMissing := func() {
+		if missingFrom == 0 {
+			return
+		}
+		if missingFrom == missingTo {
+			report.add(check, false, "EVM state of block %d isn't found", missingFrom)
+		} else {
+			report.add(check, false, "EVM states of blocks %d-%d aren't found", missingFrom, missingTo)
+		}
+		missingFrom, missingTo = 0, 0
+	}
+
+	s.ForEachBlock(func(n idx.Block, block *inter.Block) {
+		if err != nil || n > last {
+			return
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		report.Blocks++
+
+		missing := 0
+		for _, id := range block.Events {
+			// events of the old epochs may be pruned
+			if id.Epoch() >= prunedEpoch && !s.HasEvent(id) {
+				missing++
+			}
+		}
+		if missing != 0 {
+			report.add(check, false, "%d of %d events of block %d aren't found", missing, len(block.Events), n)
+		}
+		if s.hasStateRoot(block.Root) {
+			return
+		}
+		if n <= prunedStates {
+			report.MissingStates++
+			return
+		}
+		if missingFrom != 0 && missingTo+1 != n {
+			reportMissing()
+		}
+		if missingFrom == 0 {
+			missingFrom = n
+		}
+		missingTo = n
+	})
+	reportMissing()
+	return err
+}
+
+// verifyEpochEvents checks that the heads and last events of the current epoch match the epoch events.
+func (s *Store) verifyEpochEvents(ctx context.Context, report *VerifyReport, repair bool) (err error) {
+	epoch := s.GetEpoch()
+	s.loadEpochStore(epoch)
+	es := s.getEpochStore(epoch)
+	if es == nil {
+		report.add("heads", false, "epoch %d DB isn't opened", epoch)
+		return nil
+	}
+
+	heads := make(hash.EventsSet)
+	lasts := make(map[idx.ValidatorID]hash.Event)
+	lastSeqs := make(map[idx.ValidatorID]idx.Event)
+	s.ForEachEpochEvent(epoch, func(e *inter.EventPayload) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		heads.Add(e.ID())
+		for _, p := range e.Parents() {
+			delete(heads, p)
+		}
+		if e.Seq() > lastSeqs[e.Creator()] {
+			lastSeqs[e.Creator()] = e.Seq()
+			lasts[e.Creator()] = e.ID()
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	issues := len(report.Issues)
+	stored := es.GetHeads()
+	stored.RLock()
+	for id := range stored.Val {
+		if !heads.Contains(id) {
+			report.add("heads", repair, "head %s isn't an epoch event without descendants", id.String())
+		}
+	}
+	for id := range heads {
+		if !stored.Val.Contains(id) {
+			report.add("heads", repair, "epoch event %s without descendants isn't a head", id.String())
+		}
+	}
+	stored.RUnlock()
+
+	storedLasts := es.GetLastEvents()
+	storedLasts.RLock()
+	for vid, id := range storedLasts.Val {
+		if lasts[vid] != id {
+			report.add("lastEvents", repair, "last event of validator %d mismatch: stored %s, epoch events %s", vid, id.String(), lasts[vid].String())
+		}
+	}
+	for vid, id := range lasts {
+		if _, ok := storedLasts.Val[vid]; !ok {
+			report.add("lastEvents", repair, "last event %s of validator %d isn't stored", id.String(), vid)
+		}
+	}
+	storedLasts.RUnlock()
+
+	if repair && len(report.Issues) != issues {
+		es.SetHeads(concurrent.WrapEventsSet(heads))
+		es.FlushHeads()
+		es.SetLastEvents(concurrent.WrapValidatorEventsSet(lasts))
+		es.FlushLastEvents()
+	}
+	return nil
+}
+
+// hasStateRoot returns true if the EVM state trie of the root is stored.
+func (s *Store) hasStateRoot(root hash.Hash) bool {
+	if common.Hash(root) == types.EmptyRootHash {
+		return true
+	}
+	_, err := s.evm.EvmDatabase().TrieDB().Node(common.Hash(root))
+	return err == nil
+}
+
+// delBlock deletes the block and its atropos index.
+func (s *Store) delBlock(n idx.Block) {
+	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	if block != nil {
+		if bn := s.GetBlockIndex(block.Atropos); bn != nil && *bn == n {
+			if err := s.table.BlockHashes.Delete(block.Atropos.Bytes()); err != nil {
+				s.Log.Crit("Failed to delete key", "err", err)
+			}
+			s.cache.BlockHashes.Remove(block.Atropos)
+		}
+	}
+	if err := s.table.Blocks.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+	s.cache.Blocks.Remove(n)
+}
@@ -0,0 +1,134 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/hash"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/gossip/blockproc"
+	"github.com/zilionixx/go-zilionixx/inter"
+	"github.com/zilionixx/go-zilionixx/utils/concurrent"
+	"github.com/zilionixx/go-zilionixx/zilionixx"
+)
+
+func TestStoreVerify(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	const epoch = 2
+	newEvent := func(creator idx.ValidatorID, seq idx.Event, lamport idx.Lamport, parents ...hash.Event) *inter.EventPayload {
+		me := inter.MutableEventPayload{}
+		me.SetEpoch(epoch)
+		me.SetCreator(creator)
+		me.SetSeq(seq)
+		me.SetLamport(lamport)
+		me.SetParents(parents)
+		e := me.Build()
+		store.SetEvent(e)
+		return e
+	}
+	e1 := newEvent(1, 1, 1)
+	e2 := newEvent(2, 1, 1)
+	e3 := newEvent(1, 2, 2, e1.ID(), e2.ID())
+
+	// block 1 references a not stored event, block 3 is written after the latest block state
+	root := hash.Hash(types.EmptyRootHash)
+	store.SetBlock(1, &inter.Block{Atropos: e2.ID(), Events: hash.Events{e1.ID(), hash.FakeEvent()}, Root: root})
+	store.SetBlock(2, &inter.Block{Time: 2, Atropos: e3.ID(), Events: hash.Events{e2.ID(), e3.ID()}, Root: root})
+	store.SetBlock(3, &inter.Block{Time: 3, Root: root})
+	store.SetBlockIndex(e3.ID(), 2)
+	store.SetBlockEpochState(blockproc.BlockState{
+		LastBlock:          blockproc.BlockCtx{Idx: 2, Time: 2, Atropos: e3.ID()},
+		FinalizedStateRoot: root,
+		DirtyRules:         zilionixx.FakeNetRules(),
+	}, blockproc.EpochState{
+		Epoch:          epoch,
+		EpochStateRoot: root,
+		Rules:          zilionixx.FakeNetRules(),
+	})
+
+	// heads and last events aren't updated with e3
+	store.loadEpochStore(epoch)
+	store.SetHeads(epoch, concurrent.WrapEventsSet(hash.NewEventsSet(e1.ID(), e2.ID())))
+	store.SetLastEvents(epoch, concurrent.WrapValidatorEventsSet(map[idx.ValidatorID]hash.Event{
+		1: e1.ID(),
+		2: e2.ID(),
+	}))
+
+	checks := func(report *VerifyReport) map[string]int {
+		res := make(map[string]int)
+		for _, issue := range report.Issues {
+			if !issue.Repaired {
+				res[issue.Check]++
+			}
+		}
+		return res
+	}
+
+	report, err := store.Verify(context.Background(), false)
+	require.NoError(err)
+	require.Equal(2, report.Blocks)
+	require.Equal(0, report.MissingStates)
+	require.Equal(map[string]int{"state": 1, "blocks": 1, "heads": 3, "lastEvents": 1}, checks(report))
+
+	report, err = store.Verify(context.Background(), true)
+	require.NoError(err)
+	require.Equal(map[string]int{"blocks": 1}, checks(report))
+	require.Nil(store.GetBlock(3))
+	require.Equal(hash.Events{e3.ID()}, store.GetHeadsSlice(epoch))
+	require.Equal(e3.ID(), *store.GetLastEvent(epoch, 1))
+	require.Equal(e2.ID(), *store.GetLastEvent(epoch, 2))
+
+	report, err = store.Verify(context.Background(), false)
+	require.NoError(err)
+	require.Len(report.Issues, 1)
+	require.Equal("blocks", report.Issues[0].Check)
+}
+
+func TestStoreVerifyStates(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	// states of blocks 1, 2, 3, 5 and 6 aren't stored, blocks up to 2 are pruned
+	empty := hash.Hash(types.EmptyRootHash)
+	for n := idx.Block(1); n <= 7; n++ {
+		root := hash.Hash(hash.FakeHash(int64(n)))
+		if n == 4 || n == 7 {
+			root = empty
+		}
+		store.SetBlock(n, &inter.Block{Time: inter.Timestamp(n), Root: root})
+	}
+	store.SetBlockEpochState(blockproc.BlockState{
+		LastBlock:          blockproc.BlockCtx{Idx: 7, Time: 7},
+		FinalizedStateRoot: empty,
+		DirtyRules:         zilionixx.FakeNetRules(),
+	}, blockproc.EpochState{
+		Epoch:          1,
+		EpochStateRoot: empty,
+		Rules:          zilionixx.FakeNetRules(),
+	})
+	store.SetStatesPrunedBlock(2)
+
+	report, err := store.Verify(context.Background(), false)
+	require.NoError(err)
+	require.Equal(7, report.Blocks)
+	require.Equal(2, report.MissingStates)
+	var missing []string
+	for _, issue := range report.Issues {
+		if issue.Check == "blocks" {
+			missing = append(missing, issue.Err.Error())
+		}
+	}
+	require.Equal([]string{
+		"EVM state of block 3 isn't found",
+		"EVM states of blocks 5-6 aren't found",
+	}, missing)
+}
@@ -0,0 +1,53 @@
+package gossip
+
+import (
+	"time"
+
+	"github.com/zilionixx/zilion-base/inter/idx"
+)
+
+const (
+	// txUnindexerPeriod is an interval between runs of the transactions unindexer
+	txUnindexerPeriod = time.Minute
+	// txUnindexerBatch is a max number of blocks which are unindexed under the engine lock at once
+	txUnindexerBatch = 100
+)
+
+// startTxUnindexer starts the background job which deletes positions of transactions
+// of the blocks which are older than TxLookupLimit.
+func (s *Service) startTxUnindexer() {
+	if !s.config.TxIndex || s.config.TxLookupLimit == 0 {
+		return
+	}
+	s.startPeriodicJob(txUnindexerPeriod, s.unindexTxs)
+}
+
+// unindexTxs unindexes the old blocks in batches. Transactions are collected under the read lock,
+// and their positions are deleted under the write lock.
+func (s *Service) unindexTxs() {
+	for {
+		s.engineMu.RLock()
+		head := uint64(s.store.GetLatestBlockIndex()) + 1
+		if head <= s.config.TxLookupLimit {
+			s.engineMu.RUnlock()
+			return
+		}
+		before := idx.Block(head - s.config.TxLookupLimit)
+		batch := s.store.collectUnindexTxs(before, txUnindexerBatch)
+		s.engineMu.RUnlock()
+		if batch.blocks == 0 {
+			return
+		}
+
+		s.engineMu.Lock()
+		s.store.unindexTxs(batch)
+		s.engineMu.Unlock()
+		s.Log.Info("Unindexed transactions", "blocks", batch.blocks, "tail", batch.tail)
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
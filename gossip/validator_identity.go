@@ -0,0 +1,92 @@
+package gossip
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/zilionixx/zilion-base/inter/idx"
+
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
+	"github.com/zilionixx/go-zilionixx/valkeystore"
+)
+
+var (
+	errNotValidator        = errors.New("not a validator of the current epoch")
+	errWrongIdentityEpoch  = errors.New("identity proof isn't for the current epoch")
+	errInvalidIdentitySig  = errors.New("invalid identity proof signature")
+	validatorIdentityLabel = []byte("zilionixx validator identity")
+)
+
+// validatorIdentifier signs and verifies proofs that a peer is a validator of the current epoch
+type validatorIdentifier interface {
+	// ProveIdentity returns a proof for the remote peer, or nil if the node isn't a validator
+	ProveIdentity(remote enode.ID) *validatorIdentity
+	// VerifyIdentity checks the proof received from the remote peer
+	VerifyIdentity(remote enode.ID, proof *validatorIdentity) error
+}
+
+// peerIdentity proves validator identity with the validator key of the current epoch.
+// A proof is bound to both sender and receiver node IDs, so it cannot be replayed by other peers.
+type peerIdentity struct {
+	validator uint32 // idx.ValidatorID, accessed atomically
+	signer    valkeystore.SignerI
+	reader    *HeavyCheckReader
+	localID   func() enode.ID
+}
+
+func validatorIdentityHash(epoch idx.Epoch, validator idx.ValidatorID, sender, receiver enode.ID) []byte {
+	return crypto.Keccak256(validatorIdentityLabel, epoch.Bytes(), validator.Bytes(), sender[:], receiver[:])
+}
+
+// SetValidator switches the validator ID to prove, zero disables proofs
+func (pi *peerIdentity) SetValidator(id idx.ValidatorID) {
+	atomic.StoreUint32(&pi.validator, uint32(id))
+}
+
+// Validator returns the local validator ID, or zero if the node isn't a validator
+func (pi *peerIdentity) Validator() idx.ValidatorID {
+	return idx.ValidatorID(atomic.LoadUint32(&pi.validator))
+}
+
+// ProveIdentity implements validatorIdentifier
+func (pi *peerIdentity) ProveIdentity(remote enode.ID) *validatorIdentity {
+	validator := pi.Validator()
+	if validator == 0 {
+		return nil
+	}
+	pubkeys, epoch := pi.reader.GetEpochPubKeys()
+	pubkey, ok := pubkeys[validator]
+	if !ok {
+		return nil
+	}
+	sig, err := pi.signer.Sign(pubkey, validatorIdentityHash(epoch, validator, pi.localID(), remote))
+	if err != nil {
+		return nil
+	}
+	return &validatorIdentity{
+		Epoch:     epoch,
+		Validator: validator,
+		Sig:       sig,
+	}
+}
+
+// VerifyIdentity implements validatorIdentifier
+func (pi *peerIdentity) VerifyIdentity(remote enode.ID, proof *validatorIdentity) error {
+	pubkeys, epoch := pi.reader.GetEpochPubKeys()
+	if proof.Epoch != epoch {
+		return errWrongIdentityEpoch
+	}
+	pubkey, ok := pubkeys[proof.Validator]
+	if !ok {
+		return errNotValidator
+	}
+	if pubkey.Type != validatorpk.Types.Secp256k1 || len(proof.Sig) != 64 {
+		return errInvalidIdentitySig
+	}
+	if !crypto.VerifySignature(pubkey.Raw, validatorIdentityHash(epoch, proof.Validator, remote, pi.localID()), proof.Sig) {
+		return errInvalidIdentitySig
+	}
+	return nil
+}
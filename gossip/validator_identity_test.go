@@ -0,0 +1,107 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/inter/idx"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+
+	"github.com/zilionixx/go-zilionixx/inter/validatorpk"
+)
+
+type testIdentitySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s testIdentitySigner) Sign(_ validatorpk.PubKey, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, s.key)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:64], nil
+}
+
+func (s testIdentitySigner) Unlocked(_ validatorpk.PubKey) bool {
+	return true
+}
+
+func TestValidatorIdentity(t *testing.T) {
+	require := require.New(t)
+
+	key, _ := crypto.GenerateKey()
+	reader := &HeavyCheckReader{}
+	reader.Addrs.Store(&ValidatorsPubKeys{
+		Epoch: 5,
+		PubKeys: map[idx.ValidatorID]validatorpk.PubKey{
+			1: {Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&key.PublicKey)},
+		},
+	})
+	var (
+		senderID   = enode.ID{1}
+		receiverID = enode.ID{2}
+		otherID    = enode.ID{3}
+	)
+	sender := &peerIdentity{
+		signer:  testIdentitySigner{key},
+		reader:  reader,
+		localID: func() enode.ID { return senderID },
+	}
+	receiver := &peerIdentity{
+		reader:  reader,
+		localID: func() enode.ID { return receiverID },
+	}
+
+	// observers don't prove anything
+	require.Nil(sender.ProveIdentity(receiverID))
+
+	sender.SetValidator(1)
+	proof := sender.ProveIdentity(receiverID)
+	require.NotNil(proof)
+	require.Equal(idx.Epoch(5), proof.Epoch)
+	require.NoError(receiver.VerifyIdentity(senderID, proof))
+
+	// proof cannot be replayed by another peer or to another peer
+	require.Equal(errInvalidIdentitySig, receiver.VerifyIdentity(otherID, proof))
+	require.Equal(errInvalidIdentitySig, (&peerIdentity{
+		reader:  reader,
+		localID: func() enode.ID { return otherID },
+	}).VerifyIdentity(senderID, proof))
+
+	// non-validators and other epochs are rejected
+	forged := *proof
+	forged.Validator = 2
+	require.Equal(errNotValidator, receiver.VerifyIdentity(senderID, &forged))
+	forged = *proof
+	forged.Epoch = 4
+	require.Equal(errWrongIdentityEpoch, receiver.VerifyIdentity(senderID, &forged))
+
+	sender.SetValidator(2)
+	require.Nil(sender.ProveIdentity(receiverID))
+}
+
+func TestValidatorIdentityProtocolVersion(t *testing.T) {
+	require := require.New(t)
+
+	// the length of the legacy version isn't changed
+	require.Equal(uint64(EventsStreamResponse+1), protocolLengths[zilionixx62])
+	require.Equal(uint64(PrivateEvmTxsMsg+1), protocolLengths[zilionixx63])
+
+	peers := newPeerSet()
+	legacy := NewPeer(zilionixx62, p2p.NewPeer(enode.ID{1}, "legacy", nil), nil, DefaultPeerCacheConfig(cachescale.Identity))
+	upgraded := NewPeer(zilionixx63, p2p.NewPeer(enode.ID{2}, "upgraded", nil), nil, DefaultPeerCacheConfig(cachescale.Identity))
+	require.False(legacy.SupportsValidatorIdentity())
+	require.True(upgraded.SupportsValidatorIdentity())
+	require.NoError(peers.Register(legacy))
+	require.NoError(peers.Register(upgraded))
+
+	// private transactions are forwarded only to the upgraded validators
+	legacy.SetValidator(1, 5)
+	upgraded.SetValidator(2, 5)
+	require.Equal([]*peer{upgraded}, peers.ValidatorsWithoutTx(common.Hash{1}, 5))
+}
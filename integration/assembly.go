@@ -67,9 +67,13 @@ func getStores(producer kvdb.FlushableDBProducer, cfg Configs) (*gossip.Store, *
 	gdb := gossip.NewStore(producer, cfg.ZilionixxStore)
 
 	cMainDb := mustOpenDB(producer, "zilionbft")
+	cdbs := gossip.NewConsensusDBs(cMainDb)
 	cGetEpochDB := func(epoch idx.Epoch) kvdb.DropableStore {
-		return mustOpenDB(producer, fmt.Sprintf("zilionbft-%d", epoch))
+		db := mustOpenDB(producer, fmt.Sprintf("zilionbft-%d", epoch))
+		cdbs.SetEpochDB(epoch, db)
+		return db
 	}
+	gdb.SetConsensusDBs(cdbs)
 	cdb := abft.NewStore(cMainDb, cGetEpochDB, panics("zilionbft store"), cfg.ZilionBFTStore)
 	genesisStore := genesisstore.NewStore(mustOpenDB(producer, "genesis"))
 	return gdb, cdb, genesisStore
@@ -2,6 +2,10 @@ package integration
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/syndtr/goleveldb/leveldb/opt"
@@ -13,16 +17,114 @@ import (
 	"github.com/zilionixx/zilion-base/utils/cachescale"
 
 	"github.com/zilionixx/go-zilionixx/gossip"
+	"github.com/zilionixx/go-zilionixx/kvdb/pebble"
 )
 
-func DBProducer(chaindataDir string, scale cachescale.Func) kvdb.IterableDBProducer {
+// Database engines
+const (
+	LevelDBEngine = "leveldb"
+	PebbleEngine  = "pebble"
+
+	DefaultDBEngine = LevelDBEngine
+)
+
+// dbEngineFile is a file in the chaindata directory which records the database engine
+const dbEngineFile = "ENGINE"
+
+// DBProducer returns the producer of databases in the chaindata directory.
+// Engine of a new chaindata directory is recorded in it, and an existing directory
+// cannot be opened with another engine. An interrupted swap of converted databases is finished first.
+// Empty engine means the recorded one, or DefaultDBEngine.
+func DBProducer(chaindataDir string, engine string, scale cachescale.Func) (kvdb.IterableDBProducer, error) {
 	if chaindataDir == "inmemory" || chaindataDir == "" {
-		return memorydb.NewProducer("")
+		return memorydb.NewProducer(""), nil
+	}
+	if err := FinishDBsSwap(chaindataDir); err != nil {
+		return nil, err
+	}
+	engine, err := ensureDBEngine(chaindataDir, engine)
+	if err != nil {
+		return nil, err
 	}
+	return EngineProducer(chaindataDir, engine, scale), nil
+}
 
-	return leveldb.NewProducer(chaindataDir, func(name string) int {
+// EngineProducer returns the producer of databases with the engine, without checking the recorded engine
+func EngineProducer(chaindataDir string, engine string, scale cachescale.Func) kvdb.IterableDBProducer {
+	getCache := func(name string) int {
 		return dbCacheSize(name, scale.I)
-	})
+	}
+	if engine == PebbleEngine {
+		return pebble.NewProducer(chaindataDir, getCache, dbHandles)
+	}
+	return leveldb.NewProducer(chaindataDir, getCache)
+}
+
+// CheckDBEngine returns an error if the engine name is unknown
+func CheckDBEngine(engine string) error {
+	if engine != LevelDBEngine && engine != PebbleEngine {
+		return fmt.Errorf("unknown database engine %q, expected %s or %s", engine, LevelDBEngine, PebbleEngine)
+	}
+	return nil
+}
+
+// ReadDBEngine returns the engine recorded in the chaindata directory.
+// Directories created before the engine was recorded are LevelDB ones.
+// Empty string is returned for a new directory.
+func ReadDBEngine(chaindataDir string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(chaindataDir, dbEngineFile))
+	if err == nil {
+		return strings.TrimSpace(string(b)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	files, err := ioutil.ReadDir(chaindataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			return LevelDBEngine, nil
+		}
+	}
+	return "", nil
+}
+
+// WriteDBEngine records the engine in the chaindata directory
+func WriteDBEngine(chaindataDir string, engine string) error {
+	if err := os.MkdirAll(chaindataDir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(chaindataDir, dbEngineFile), []byte(engine+"\n"), 0600)
+}
+
+func ensureDBEngine(chaindataDir string, engine string) (string, error) {
+	recorded, err := ReadDBEngine(chaindataDir)
+	if err != nil {
+		return "", err
+	}
+	if engine == "" {
+		engine = recorded
+	}
+	if engine == "" {
+		engine = DefaultDBEngine
+	}
+	if err := CheckDBEngine(engine); err != nil {
+		return "", err
+	}
+	if recorded != "" && recorded != engine {
+		return "", fmt.Errorf("database engine mismatch: %s uses %s, but %s is requested", chaindataDir, recorded, engine)
+	}
+	if recorded == "" || !dbEngineRecorded(chaindataDir) {
+		return engine, WriteDBEngine(chaindataDir, engine)
+	}
+	return engine, nil
+}
+
+func dbEngineRecorded(chaindataDir string) bool {
+	_, err := os.Stat(filepath.Join(chaindataDir, dbEngineFile))
+	return err == nil
 }
 
 func CheckDBList(names []string) error {
@@ -61,6 +163,22 @@ func dbCacheSize(name string, scale func(int) int) int {
 	return scale(2 * opt.MiB)
 }
 
+func dbHandles(name string) int {
+	if name == "gossip" {
+		return 512
+	}
+	if name == "zilionbft" {
+		return 64
+	}
+	if strings.HasPrefix(name, "zilionbft-") {
+		return 64
+	}
+	if strings.HasPrefix(name, "gossip-") {
+		return 64
+	}
+	return 32
+}
+
 func dropAllDBs(producer kvdb.IterableDBProducer) {
 	names := producer.Names()
 	for _, name := range names {
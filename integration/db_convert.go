@@ -0,0 +1,302 @@
+package integration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/zilionixx/zilion-base/kvdb"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+)
+
+const (
+	// convertProgressFile is a file in the destination directory which lists the converted databases
+	convertProgressFile = "CONVERTED"
+	// swapProgressFile is a file in the destination directory which marks it as the replacement of the chaindata directory
+	swapProgressFile = "SWAP"
+	// convertReportInterval is the time interval between progress logs of a database conversion
+	convertReportInterval = 8 * time.Second
+)
+
+// tableStats is a number of keys and a checksum of a table, i.e. of the keys with the same first byte
+type tableStats struct {
+	keys     uint64
+	checksum hash.Hash
+}
+
+// dbStats is a set of table stats of a database
+type dbStats map[byte]*tableStats
+
+func (s dbStats) add(key, value []byte) {
+	var prefix byte
+	if len(key) != 0 {
+		prefix = key[0]
+	}
+	t := s[prefix]
+	if t == nil {
+		t = &tableStats{checksum: sha256.New()}
+		s[prefix] = t
+	}
+	t.keys++
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(key)))
+	t.checksum.Write(size[:])
+	t.checksum.Write(key)
+	binary.BigEndian.PutUint32(size[:], uint32(len(value)))
+	t.checksum.Write(size[:])
+	t.checksum.Write(value)
+}
+
+func (s dbStats) keys() uint64 {
+	total := uint64(0)
+	for _, t := range s {
+		total += t.keys
+	}
+	return total
+}
+
+// compare returns an error describing the first mismatched table
+func (s dbStats) compare(other dbStats) error {
+	for prefix := 0; prefix < 256; prefix++ {
+		a, b := s[byte(prefix)], other[byte(prefix)]
+		if a == nil && b == nil {
+			continue
+		}
+		if a == nil || b == nil || a.keys != b.keys {
+			return fmt.Errorf("table %#x: keys count mismatch: have %d, want %d", prefix, b.keysNum(), a.keysNum())
+		}
+		if !bytes.Equal(a.checksum.Sum(nil), b.checksum.Sum(nil)) {
+			return fmt.Errorf("table %#x: checksum mismatch", prefix)
+		}
+	}
+	return nil
+}
+
+func (t *tableStats) keysNum() uint64 {
+	if t == nil {
+		return 0
+	}
+	return t.keys
+}
+
+func collectDBStats(db kvdb.Iteratee) (dbStats, error) {
+	stats := dbStats{}
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		stats.add(it.Key(), it.Value())
+	}
+	return stats, it.Error()
+}
+
+// ConvertDBs copies all the databases of the chaindata directory into a new directory with the given engine.
+// Key counts and checksums of every table are verified after a database is copied.
+// Converted databases are listed in the destination directory, so an interrupted conversion
+// is resumed and only the partially copied database is copied again.
+func ConvertDBs(srcDir, dstDir, engine string, scale cachescale.Func) error {
+	if err := CheckDBEngine(engine); err != nil {
+		return err
+	}
+	srcEngine, err := ReadDBEngine(srcDir)
+	if err != nil {
+		return err
+	}
+	if srcEngine == "" {
+		return fmt.Errorf("no databases found in %s", srcDir)
+	}
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return err
+	}
+	done, err := readConvertedDBs(dstDir)
+	if err != nil {
+		return err
+	}
+
+	src := EngineProducer(srcDir, srcEngine, scale)
+	dst := EngineProducer(dstDir, engine, scale)
+	for _, name := range src.Names() {
+		if done[name] {
+			log.Info("Database is already converted", "name", name)
+			continue
+		}
+		if err := convertDB(src, dst, name); err != nil {
+			return fmt.Errorf("failed to convert %s DB: %w", name, err)
+		}
+		if err := markConvertedDB(dstDir, name); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteDBEngine(dstDir, engine); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(dstDir, convertProgressFile))
+}
+
+// SwapDBs moves the original databases to backupDir and the converted databases to chaindataDir.
+// The converted directory is marked beforehand, so a swap interrupted after the first rename
+// is finished by FinishDBsSwap.
+func SwapDBs(chaindataDir, convertedDir, backupDir string) error {
+	if err := writeSyncedFile(filepath.Join(convertedDir, swapProgressFile), []byte(chaindataDir)); err != nil {
+		return err
+	}
+	if err := os.Rename(chaindataDir, backupDir); err != nil {
+		return err
+	}
+	return FinishDBsSwap(chaindataDir)
+}
+
+// FinishDBsSwap moves the converted databases to chaindataDir if the original databases
+// were already moved away by an interrupted SwapDBs. It does nothing if no swap is in progress.
+func FinishDBsSwap(chaindataDir string) error {
+	if _, err := os.Stat(chaindataDir); err == nil {
+		// the swap is either finished or not started, only the marker may be left
+		err := os.Remove(filepath.Join(chaindataDir, swapProgressFile))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, engine := range []string{LevelDBEngine, PebbleEngine} {
+		convertedDir := chaindataDir + "-" + engine
+		if _, err := os.Stat(filepath.Join(convertedDir, swapProgressFile)); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		log.Warn("Finishing the interrupted swap of the converted databases", "dir", convertedDir)
+		if err := os.Rename(convertedDir, chaindataDir); err != nil {
+			return err
+		}
+		return os.Remove(filepath.Join(chaindataDir, swapProgressFile))
+	}
+	return nil
+}
+
+func writeSyncedFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func convertDB(src, dst kvdb.DBProducer, name string) error {
+	start := time.Now()
+
+	srcDB, err := src.OpenDB(name)
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+	dstDB, err := dst.OpenDB(name)
+	if err != nil {
+		return err
+	}
+	// drop the partially copied DB of an interrupted conversion
+	if it := dstDB.NewIterator(nil, nil); it.Next() {
+		it.Release()
+		log.Warn("Dropping partially converted database", "name", name)
+		_ = dstDB.Close()
+		dstDB.Drop()
+		dstDB, err = dst.OpenDB(name)
+		if err != nil {
+			return err
+		}
+	} else {
+		it.Release()
+	}
+	defer dstDB.Close()
+
+	log.Info("Converting database", "name", name)
+	srcStats := dbStats{}
+	batch := dstDB.NewBatch()
+	it := srcDB.NewIterator(nil, nil)
+	defer it.Release()
+	reported := time.Now()
+	for it.Next() {
+		srcStats.add(it.Key(), it.Value())
+		if err := batch.Put(common.CopyBytes(it.Key()), common.CopyBytes(it.Value())); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(reported) >= convertReportInterval {
+			log.Info("Converting database", "name", name, "keys", srcStats.keys(), "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	dstStats, err := collectDBStats(dstDB)
+	if err != nil {
+		return err
+	}
+	if err := srcStats.compare(dstStats); err != nil {
+		return err
+	}
+	log.Info("Converted database", "name", name, "keys", srcStats.keys(), "tables", len(srcStats), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func readConvertedDBs(dstDir string) (map[string]bool, error) {
+	done := make(map[string]bool)
+	b, err := ioutil.ReadFile(filepath.Join(dstDir, convertProgressFile))
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(b), "\n") {
+		if name != "" {
+			done[name] = true
+		}
+	}
+	return done, nil
+}
+
+func markConvertedDB(dstDir, name string) error {
+	f, err := os.OpenFile(filepath.Join(dstDir, convertProgressFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(name + "\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
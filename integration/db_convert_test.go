@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/kvdb"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+)
+
+func fillTestDB(t *testing.T, producer kvdb.DBProducer, name string, n int) {
+	db, err := producer.OpenDB(name)
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		require.NoError(t, db.Put([]byte(fmt.Sprintf("%c%d", 'a'+i%3, i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(t, db.Close())
+}
+
+func requireSameDB(t *testing.T, a, b kvdb.DBProducer, name string) {
+	dbA, err := a.OpenDB(name)
+	require.NoError(t, err)
+	defer dbA.Close()
+	dbB, err := b.OpenDB(name)
+	require.NoError(t, err)
+	defer dbB.Close()
+
+	statsA, err := collectDBStats(dbA)
+	require.NoError(t, err)
+	statsB, err := collectDBStats(dbB)
+	require.NoError(t, err)
+	require.NoError(t, statsA.compare(statsB), name)
+	require.NotZero(t, statsA.keys(), name)
+}
+
+func TestConvertDBs(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "db_convert_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	srcDir := filepath.Join(dir, "chaindata")
+	dstDir := filepath.Join(dir, "chaindata-pebble")
+	src, err := DBProducer(srcDir, LevelDBEngine, cachescale.Identity)
+	require.NoError(err)
+	names := []string{"gossip", "gossip-1", "zilionbft", "zilionbft-1", "genesis"}
+	for i, name := range names {
+		fillTestDB(t, src, name, 100*(i+1))
+	}
+
+	// simulate an interrupted conversion: the first DB is converted, and the second one is partially copied
+	dst := EngineProducer(dstDir, PebbleEngine, cachescale.Identity)
+	require.NoError(convertDB(src, dst, "gossip"))
+	require.NoError(markConvertedDB(dstDir, "gossip"))
+	fillTestDB(t, dst, "gossip-1", 10)
+	fillTestDB(t, dst, "zilionbft", 1000)
+
+	require.NoError(ConvertDBs(srcDir, dstDir, PebbleEngine, cachescale.Identity))
+
+	engine, err := ReadDBEngine(dstDir)
+	require.NoError(err)
+	require.Equal(PebbleEngine, engine)
+	_, err = os.Stat(filepath.Join(dstDir, convertProgressFile))
+	require.True(os.IsNotExist(err))
+
+	dst, err = DBProducer(dstDir, "", cachescale.Identity)
+	require.NoError(err)
+	require.ElementsMatch(names, dst.Names())
+	for _, name := range names {
+		requireSameDB(t, src, dst, name)
+	}
+}
+
+func TestFinishDBsSwap(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "db_swap_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	chaindataDir := filepath.Join(dir, "chaindata")
+	convertedDir := filepath.Join(dir, "chaindata-pebble")
+	backupDir := filepath.Join(dir, "chaindata-leveldb")
+	src, err := DBProducer(chaindataDir, LevelDBEngine, cachescale.Identity)
+	require.NoError(err)
+	fillTestDB(t, src, "gossip", 100)
+	require.NoError(ConvertDBs(chaindataDir, convertedDir, PebbleEngine, cachescale.Identity))
+
+	// no swap is in progress
+	require.NoError(FinishDBsSwap(chaindataDir))
+	engine, err := ReadDBEngine(chaindataDir)
+	require.NoError(err)
+	require.Equal(LevelDBEngine, engine)
+
+	// simulate a swap interrupted between the renames
+	require.NoError(writeSyncedFile(filepath.Join(convertedDir, swapProgressFile), []byte(chaindataDir)))
+	require.NoError(os.Rename(chaindataDir, backupDir))
+
+	dst, err := DBProducer(chaindataDir, "", cachescale.Identity)
+	require.NoError(err)
+	engine, err = ReadDBEngine(chaindataDir)
+	require.NoError(err)
+	require.Equal(PebbleEngine, engine)
+	require.Equal([]string{"gossip"}, dst.Names())
+	_, err = os.Stat(filepath.Join(chaindataDir, swapProgressFile))
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(convertedDir)
+	require.True(os.IsNotExist(err))
+
+	backup := EngineProducer(backupDir, LevelDBEngine, cachescale.Identity)
+	requireSameDB(t, backup, dst, "gossip")
+}
+
+func TestDBStatsCompare(t *testing.T) {
+	require := require.New(t)
+
+	a, b := dbStats{}, dbStats{}
+	a.add([]byte("a1"), []byte("v"))
+	b.add([]byte("a1"), []byte("v"))
+	require.NoError(a.compare(b))
+
+	a.add([]byte("b1"), []byte("v"))
+	require.Error(a.compare(b))
+	b.add([]byte("b1"), []byte("w"))
+	require.Error(a.compare(b))
+}
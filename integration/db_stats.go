@@ -0,0 +1,42 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+
+	"github.com/zilionixx/go-zilionixx/gossip"
+	"github.com/zilionixx/go-zilionixx/utils/dbstats"
+)
+
+// DBStats counts the keys of every table of every database in the directory.
+// Keys of unknown tables are grouped by their first byte.
+func DBStats(ctx context.Context, chaindataDir string, scale cachescale.Func) ([]dbstats.Stats, error) {
+	if err := FinishDBsSwap(chaindataDir); err != nil {
+		return nil, err
+	}
+	engine, err := ReadDBEngine(chaindataDir)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		return nil, fmt.Errorf("no databases found in %s", chaindataDir)
+	}
+
+	producer := EngineProducer(chaindataDir, engine, scale)
+	var stats []dbstats.Stats
+	for _, name := range producer.Names() {
+		db, err := producer.OpenDB(name)
+		if err != nil {
+			return nil, err
+		}
+		dbStats, err := dbstats.Collect(ctx, name, db, gossip.DBTables(name))
+		_ = db.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect stats of %s DB: %w", name, err)
+		}
+		stats = append(stats, dbStats...)
+	}
+	return stats, nil
+}
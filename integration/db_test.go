@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+)
+
+func TestDBProducerEngine(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "db_engine_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// engine of a new directory is recorded
+	chaindata := filepath.Join(dir, "pebble")
+	_, err = DBProducer(chaindata, PebbleEngine, cachescale.Identity)
+	require.NoError(err)
+	engine, err := ReadDBEngine(chaindata)
+	require.NoError(err)
+	require.Equal(PebbleEngine, engine)
+
+	// the recorded engine is used by default, and another engine is refused
+	_, err = DBProducer(chaindata, "", cachescale.Identity)
+	require.NoError(err)
+	_, err = DBProducer(chaindata, LevelDBEngine, cachescale.Identity)
+	require.Error(err)
+
+	// directories with databases, but without the record, are LevelDB ones
+	chaindata = filepath.Join(dir, "legacy")
+	require.NoError(os.MkdirAll(filepath.Join(chaindata, "gossip"), 0700))
+	_, err = DBProducer(chaindata, PebbleEngine, cachescale.Identity)
+	require.Error(err)
+	_, err = DBProducer(chaindata, "", cachescale.Identity)
+	require.NoError(err)
+	engine, err = ReadDBEngine(chaindata)
+	require.NoError(err)
+	require.Equal(LevelDBEngine, engine)
+
+	_, err = DBProducer(filepath.Join(dir, "unknown"), "rocksdb", cachescale.Identity)
+	require.Error(err)
+}
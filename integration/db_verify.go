@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zilionixx/zilion-base/abft"
+	"github.com/zilionixx/zilion-base/utils/cachescale"
+
+	"github.com/zilionixx/go-zilionixx/gossip"
+)
+
+// VerifyDBs checks consistency of the gossip store, the consensus store and the EVM states.
+// Fixable issues of the gossip store are repaired and flushed if repair is true.
+func VerifyDBs(ctx context.Context, chaindataDir string, cfg Configs, scale cachescale.Func, repair bool) (*gossip.VerifyReport, error) {
+	if err := FinishDBsSwap(chaindataDir); err != nil {
+		return nil, err
+	}
+	engine, err := ReadDBEngine(chaindataDir)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		return nil, fmt.Errorf("no databases found in %s", chaindataDir)
+	}
+
+	// fails if the databases weren't flushed atomically
+	dbs, err := makeFlushableProducer(EngineProducer(chaindataDir, engine, scale))
+	if err != nil {
+		return nil, err
+	}
+	gdb, cdb, genesisStore := getStores(dbs, cfg)
+	defer gdb.Close()
+	defer cdb.Close()
+	defer genesisStore.Close()
+	if gdb.GetGenesisHash() == nil {
+		return nil, fmt.Errorf("malformed chainstore: genesis hash is not written")
+	}
+
+	report, err := gdb.Verify(ctx, repair)
+	if err != nil {
+		return nil, err
+	}
+	verifyConsensusState(report, gdb, cdb)
+
+	if repair {
+		if err := gdb.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit DBs: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// verifyConsensusState checks that the consensus epoch and validators match the gossip epoch state.
+func verifyConsensusState(report *gossip.VerifyReport, gdb *gossip.Store, cdb *abft.Store) {
+	const check = "consensus"
+	add := func(format string, args ...interface{}) {
+		report.Issues = append(report.Issues, gossip.VerifyIssue{
+			Check: check,
+			Err:   fmt.Errorf(format, args...),
+		})
+	}
+
+	validators, epoch := gdb.GetEpochValidators()
+	if cdb.GetEpoch() != epoch {
+		add("consensus epoch %d mismatches gossip epoch %d", cdb.GetEpoch(), epoch)
+		return
+	}
+	cValidators := cdb.GetValidators()
+	if cValidators.Len() != validators.Len() {
+		add("consensus has %d validators, gossip has %d", cValidators.Len(), validators.Len())
+		return
+	}
+	for _, id := range validators.SortedIDs() {
+		if cValidators.Get(id) != validators.Get(id) {
+			add("validator %d weight mismatch: consensus %d, gossip %d", id, cValidators.Get(id), validators.Get(id))
+		}
+	}
+}
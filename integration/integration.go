@@ -31,7 +31,11 @@ func NewIntegration(ctx *adapters.ServiceContext, genesis InputGenesis, stack *n
 		VectorClock:   vecmt.DefaultConfig(cachescale.Identity),
 	}
 
-	engine, dagIndex, gdb, _, _, blockProc := MakeEngine(DBProducer(ctx.Config.DataDir, cachescale.Identity), genesis, cfg)
+	producer, err := DBProducer(ctx.Config.DataDir, "", cachescale.Identity)
+	if err != nil {
+		panic(err)
+	}
+	engine, dagIndex, gdb, _, _, blockProc := MakeEngine(producer, genesis, cfg)
 	_ = genesis.Close()
 
 	valKeystore := valkeystore.NewDefaultMemKeystore()
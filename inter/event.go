@@ -57,6 +57,9 @@ type sigData struct {
 
 type payloadData struct {
 	txs types.Transactions
+	// compactSponsoredTxs is true if sponsored transactions are serialized compactly,
+	// which is allowed only after the Sponsorship upgrade. It doesn't affect the event hash.
+	compactSponsoredTxs bool
 }
 
 type Event struct {
@@ -115,6 +118,8 @@ func (e *sigData) Sig() Signature { return e.sig }
 
 func (e *payloadData) Txs() types.Transactions { return e.txs }
 
+func (e *payloadData) CompactSponsoredTxs() bool { return e.compactSponsoredTxs }
+
 func (e *MutableEventPayload) SetCreationTime(v Timestamp) { e.creationTime = v }
 
 func (e *MutableEventPayload) SetMedianTime(v Timestamp) { e.medianTime = v }
@@ -133,6 +138,8 @@ func (e *MutableEventPayload) SetSig(v Signature) { e.sig = v }
 
 func (e *MutableEventPayload) SetTxs(v types.Transactions) { e.txs = v }
 
+func (e *MutableEventPayload) SetCompactSponsoredTxs(v bool) { e.compactSponsoredTxs = v }
+
 func eventHash(eventSer []byte) hash.Hash {
 	hasher := sha256.New()
 	_, err := hasher.Write(eventSer)
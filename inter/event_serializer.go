@@ -140,7 +140,7 @@ func (e *EventPayload) MarshalCSER(w *cser.Writer) error {
 		w.U56(uint64(e.txs.Len()))
 		// txs
 		for _, tx := range e.txs {
-			err := TransactionMarshalCSER(w, tx)
+			err := TransactionMarshalCSER(w, tx, e.compactSponsoredTxs)
 			if err != nil {
 				return err
 			}
@@ -160,10 +160,13 @@ func (e *MutableEventPayload) UnmarshalCSER(r *cser.Reader) error {
 		// txs size
 		size := r.U56()
 		for i := uint64(0); i < size; i++ {
-			tx, err := TransactionUnmarshalCSER(r)
+			tx, compact, err := TransactionUnmarshalCSER(r)
 			if err != nil {
 				return err
 			}
+			if compact {
+				e.SetCompactSponsoredTxs(true)
+			}
 			txs = append(txs, tx)
 		}
 	}
@@ -0,0 +1,247 @@
+package inter
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/zilionixx/go-zilionixx/utils/cser"
+)
+
+// ExtraRecordType is a type of metadata record published in event extra data
+type ExtraRecordType uint8
+
+const (
+	// SoftwareVersionRecord is a node version string, with an empty key
+	SoftwareVersionRecord ExtraRecordType = 1
+	// GasPriceVoteRecord is a gas price voted by a validator, with an empty key
+	GasPriceVoteRecord ExtraRecordType = 2
+	// OracleValueRecord is a numeric value of an oracle feed, with the feed name as a key
+	OracleValueRecord ExtraRecordType = 3
+)
+
+const (
+	// ExtraDataMagic is the first byte of a typed extra data.
+	// Extra data starting with other bytes (e.g. legacy "v-" version strings) isn't interpreted.
+	ExtraDataMagic byte = 0x00
+	// ExtraDataVersion is the current version of typed extra data
+	ExtraDataVersion uint8 = 1
+
+	MaxExtraRecordKey      = 32
+	MaxExtraRecordBigValue = 32
+)
+
+var (
+	ErrUnknownExtraVersion  = errors.New("unknown extra data version")
+	ErrNonCanonicalExtra    = errors.New("extra data records aren't sorted or duplicated")
+	ErrMalformedExtraRecord = errors.New("malformed extra data record")
+)
+
+// ExtraRecord is a single metadata record.
+// Records are identified by type and key, e.g. every oracle feed has its own key.
+type ExtraRecord struct {
+	Type  ExtraRecordType
+	Key   []byte
+	Value []byte
+}
+
+// ExtraData is a typed, versioned content of event extra data.
+// It's signed along with the event, so the records are attributed to the event creator.
+// Records are sorted by type and key.
+type ExtraData struct {
+	Records []ExtraRecord
+}
+
+// Known returns true if the record type is interpreted by this version of the node
+func (t ExtraRecordType) Known() bool {
+	return t >= SoftwareVersionRecord && t <= OracleValueRecord
+}
+
+func (t ExtraRecordType) String() string {
+	switch t {
+	case SoftwareVersionRecord:
+		return "softwareVersion"
+	case GasPriceVoteRecord:
+		return "gasPriceVote"
+	case OracleValueRecord:
+		return "oracleValue"
+	}
+	return "unknown"
+}
+
+// NewSoftwareVersionRecord creates a record of node version
+func NewSoftwareVersionRecord(version string) ExtraRecord {
+	return ExtraRecord{
+		Type:  SoftwareVersionRecord,
+		Value: []byte(version),
+	}
+}
+
+// NewGasPriceVoteRecord creates a record of voted gas price
+func NewGasPriceVoteRecord(price *big.Int) ExtraRecord {
+	return ExtraRecord{
+		Type:  GasPriceVoteRecord,
+		Value: price.Bytes(),
+	}
+}
+
+// NewOracleValueRecord creates a record of an oracle feed value
+func NewOracleValueRecord(feed string, value *big.Int) ExtraRecord {
+	return ExtraRecord{
+		Type:  OracleValueRecord,
+		Key:   []byte(feed),
+		Value: value.Bytes(),
+	}
+}
+
+// BigValue returns the record value as an unsigned integer
+func (r ExtraRecord) BigValue() *big.Int {
+	return new(big.Int).SetBytes(r.Value)
+}
+
+func (r ExtraRecord) less(b ExtraRecord) bool {
+	if r.Type != b.Type {
+		return r.Type < b.Type
+	}
+	return bytes.Compare(r.Key, b.Key) < 0
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the record content. Records of unknown types are accepted as is.
+func (r ExtraRecord) Validate() error {
+	if len(r.Key) > MaxExtraRecordKey {
+		return ErrMalformedExtraRecord
+	}
+	switch r.Type {
+	case SoftwareVersionRecord:
+		if len(r.Key) != 0 || len(r.Value) == 0 || !isPrintable(r.Value) {
+			return ErrMalformedExtraRecord
+		}
+	case GasPriceVoteRecord, OracleValueRecord:
+		if r.Type == GasPriceVoteRecord && len(r.Key) != 0 {
+			return ErrMalformedExtraRecord
+		}
+		if r.Type == OracleValueRecord && (len(r.Key) == 0 || !isPrintable(r.Key)) {
+			return ErrMalformedExtraRecord
+		}
+		// non-canonical big-endian integers are rejected
+		if len(r.Value) > MaxExtraRecordBigValue || (len(r.Value) != 0 && r.Value[0] == 0) {
+			return ErrMalformedExtraRecord
+		}
+	}
+	return nil
+}
+
+// Set adds the record or replaces the record with the same type and key
+func (d *ExtraData) Set(r ExtraRecord) {
+	i := sort.Search(len(d.Records), func(i int) bool {
+		return !d.Records[i].less(r)
+	})
+	if i < len(d.Records) && !r.less(d.Records[i]) {
+		d.Records[i] = r
+		return
+	}
+	d.Records = append(d.Records, ExtraRecord{})
+	copy(d.Records[i+1:], d.Records[i:])
+	d.Records[i] = r
+}
+
+// Validate checks that the records are sorted, unique and well-formed
+func (d *ExtraData) Validate() error {
+	for i, r := range d.Records {
+		if i > 0 && !d.Records[i-1].less(r) {
+			return ErrNonCanonicalExtra
+		}
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *ExtraData) MarshalCSER(w *cser.Writer) error {
+	w.U32(uint32(len(d.Records)))
+	for _, r := range d.Records {
+		w.U8(uint8(r.Type))
+		w.SliceBytes(r.Key)
+		w.SliceBytes(r.Value)
+	}
+	return nil
+}
+
+func (d *ExtraData) UnmarshalCSER(r *cser.Reader, maxSize int) error {
+	// sizes cannot exceed the encoded body, check them before allocating
+	readBytes := func() []byte {
+		size := r.U56()
+		if size > uint64(maxSize) {
+			panic(cser.ErrMalformedEncoding)
+		}
+		buf := make([]byte, size)
+		r.FixedBytes(buf)
+		return buf
+	}
+	num := r.U32()
+	if uint64(num) > uint64(maxSize) {
+		return cser.ErrMalformedEncoding
+	}
+	d.Records = make([]ExtraRecord, num)
+	for i := range d.Records {
+		d.Records[i].Type = ExtraRecordType(r.U8())
+		d.Records[i].Key = readBytes()
+		d.Records[i].Value = readBytes()
+	}
+	return nil
+}
+
+// MarshalBinary encodes the typed extra data, prefixed with ExtraDataMagic and version
+func (d *ExtraData) MarshalBinary() ([]byte, error) {
+	body, err := cser.MarshalBinaryAdapter(d.MarshalCSER)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{ExtraDataMagic, ExtraDataVersion}, body...), nil
+}
+
+// UnmarshalBinary decodes the typed extra data. It doesn't validate the records.
+func (d *ExtraData) UnmarshalBinary(raw []byte) error {
+	if len(raw) < 2 || raw[0] != ExtraDataMagic {
+		return cser.ErrMalformedEncoding
+	}
+	if raw[1] != ExtraDataVersion {
+		return ErrUnknownExtraVersion
+	}
+	body := raw[2:]
+	return cser.UnmarshalBinaryAdapter(body, func(r *cser.Reader) error {
+		return d.UnmarshalCSER(r, len(body))
+	})
+}
+
+// IsTypedExtra returns true if extra data should be interpreted as ExtraData
+func IsTypedExtra(extra []byte) bool {
+	return len(extra) != 0 && extra[0] == ExtraDataMagic
+}
+
+// ParseExtra decodes and validates typed extra data.
+// It returns nil without an error if the extra data isn't typed.
+func ParseExtra(extra []byte) (*ExtraData, error) {
+	if !IsTypedExtra(extra) {
+		return nil, nil
+	}
+	d := &ExtraData{}
+	if err := d.UnmarshalBinary(extra); err != nil {
+		return nil, err
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
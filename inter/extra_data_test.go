@@ -0,0 +1,87 @@
+package inter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/go-zilionixx/utils/cser"
+)
+
+func TestExtraDataSerialization(t *testing.T) {
+	require := require.New(t)
+
+	d := ExtraData{}
+	d.Set(NewOracleValueRecord("ZNX/USD", big.NewInt(1500)))
+	d.Set(NewSoftwareVersionRecord("v-1.0.0"))
+	d.Set(NewGasPriceVoteRecord(big.NewInt(1e9)))
+	d.Set(NewOracleValueRecord("BTC/USD", big.NewInt(1)))
+	d.Set(NewOracleValueRecord("ZNX/USD", big.NewInt(2000)))
+	require.Len(d.Records, 4)
+	require.NoError(d.Validate())
+
+	raw, err := d.MarshalBinary()
+	require.NoError(err)
+	require.True(IsTypedExtra(raw))
+
+	parsed, err := ParseExtra(raw)
+	require.NoError(err)
+	reraw, err := parsed.MarshalBinary()
+	require.NoError(err)
+	require.Equal(raw, reraw)
+	require.Equal(GasPriceVoteRecord, parsed.Records[1].Type)
+	require.Equal("BTC/USD", string(parsed.Records[2].Key))
+	require.Equal(big.NewInt(2000), parsed.Records[3].BigValue())
+
+	// legacy extra isn't interpreted
+	parsed, err = ParseExtra([]byte("v-1.0.0"))
+	require.NoError(err)
+	require.Nil(parsed)
+
+	// unknown version
+	raw[1] = ExtraDataVersion + 1
+	_, err = ParseExtra(raw)
+	require.Equal(ErrUnknownExtraVersion, err)
+
+	// truncated
+	raw[1] = ExtraDataVersion
+	_, err = ParseExtra(raw[:len(raw)-3])
+	require.Error(err)
+	_, err = ParseExtra(raw[:2])
+	require.Equal(cser.ErrMalformedEncoding, err)
+}
+
+func TestExtraDataValidation(t *testing.T) {
+	for name, d := range map[string]ExtraData{
+		"unsorted": {Records: []ExtraRecord{
+			NewGasPriceVoteRecord(big.NewInt(1)),
+			NewSoftwareVersionRecord("v"),
+		}},
+		"duplicated": {Records: []ExtraRecord{
+			NewGasPriceVoteRecord(big.NewInt(1)),
+			NewGasPriceVoteRecord(big.NewInt(2)),
+		}},
+		"empty version": {Records: []ExtraRecord{
+			NewSoftwareVersionRecord(""),
+		}},
+		"non-canonical value": {Records: []ExtraRecord{
+			{Type: GasPriceVoteRecord, Value: []byte{0, 1}},
+		}},
+		"no feed name": {Records: []ExtraRecord{
+			NewOracleValueRecord("", big.NewInt(1)),
+		}},
+	} {
+		raw, err := d.MarshalBinary()
+		require.NoError(t, err, name)
+		_, err = ParseExtra(raw)
+		require.Error(t, err, name)
+	}
+
+	// records of unknown types are accepted
+	d := ExtraData{Records: []ExtraRecord{{Type: 100, Value: []byte{0}}}}
+	raw, err := d.MarshalBinary()
+	require.NoError(t, err)
+	_, err = ParseExtra(raw)
+	require.NoError(t, err)
+}
@@ -0,0 +1,130 @@
+package inter
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// A sponsored transaction is an access list transaction, which gas is paid by a sponsor instead of the sender.
+// The sponsor's signature is carried by the last access list entry, which has the SponsorshipMarker address
+// and three storage keys: R, S and V of the signature. The sponsor signs SponsorHash of the transaction
+// fields without the marker entry, and then the sender signs the whole transaction including it.
+
+// SponsorshipMarker is the reserved address of the access list entry with the sponsor's signature
+var SponsorshipMarker = common.HexToAddress("0xd100fee000000000000000000000000000000000")
+
+const (
+	// SponsoredTxType is a CSER-only type of sponsored transactions, which are access list transactions in other encodings
+	SponsoredTxType = 0x7f
+
+	sponsorshipKeys = 3
+	// sponsorHashPrefix separates sponsor signatures from signatures of transactions
+	sponsorHashPrefix = 0x5f
+)
+
+var (
+	ErrNotSponsored   = errors.New("transaction isn't sponsored")
+	ErrInvalidSponsor = errors.New("invalid sponsor signature")
+)
+
+// IsSponsored returns true if the transaction carries a well-formed sponsorship entry
+func IsSponsored(tx *types.Transaction) bool {
+	if tx.Type() != types.AccessListTxType {
+		return false
+	}
+	_, ok := sponsorshipEntry(tx.AccessList())
+	return ok
+}
+
+func sponsorshipEntry(al types.AccessList) (types.AccessTuple, bool) {
+	if len(al) == 0 {
+		return types.AccessTuple{}, false
+	}
+	last := al[len(al)-1]
+	if last.Address != SponsorshipMarker || len(last.StorageKeys) != sponsorshipKeys {
+		return types.AccessTuple{}, false
+	}
+	if v := last.StorageKeys[2].Big(); v.Cmp(common.Big1) > 0 {
+		return types.AccessTuple{}, false
+	}
+	return last, true
+}
+
+// sponsoredAccessList returns the access list without the sponsorship entry
+func sponsoredAccessList(tx *types.Transaction) types.AccessList {
+	al := tx.AccessList()
+	if _, ok := sponsorshipEntry(al); ok {
+		return al[:len(al)-1]
+	}
+	return al
+}
+
+// SponsorHash returns the hash to be signed by the sponsor of the sender's transaction.
+// The sponsorship entry and the sender's signature aren't covered.
+func SponsorHash(tx *types.Transaction, sender common.Address) common.Hash {
+	b, _ := rlp.EncodeToBytes([]interface{}{
+		tx.ChainId(),
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		sponsoredAccessList(tx),
+		sender,
+	})
+	return crypto.Keccak256Hash([]byte{sponsorHashPrefix}, b)
+}
+
+// TxSponsor recovers the sponsor of the sender's transaction
+func TxSponsor(tx *types.Transaction, sender common.Address) (common.Address, error) {
+	if tx.Type() != types.AccessListTxType {
+		return common.Address{}, ErrNotSponsored
+	}
+	entry, ok := sponsorshipEntry(tx.AccessList())
+	if !ok {
+		return common.Address{}, ErrNotSponsored
+	}
+	r, s, v := entry.StorageKeys[0].Big(), entry.StorageKeys[1].Big(), byte(entry.StorageKeys[2].Big().Uint64())
+	if !crypto.ValidateSignatureValues(v, r, s, true) {
+		return common.Address{}, ErrInvalidSponsor
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig[0:32], entry.StorageKeys[0][:])
+	copy(sig[32:64], entry.StorageKeys[1][:])
+	sig[64] = v
+	pub, err := crypto.Ecrecover(SponsorHash(tx, sender).Bytes(), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSponsor
+	}
+	return common.BytesToAddress(crypto.Keccak256(pub[1:])[12:]), nil
+}
+
+// WithSponsorSignature returns a copy of the unsigned access list transaction data with the sponsorship entry.
+// sig is the sponsor's [R || S || V] signature of SponsorHash. The result has to be signed by the sender.
+func WithSponsorSignature(tx *types.AccessListTx, sig []byte) (*types.AccessListTx, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, ErrInvalidSponsor
+	}
+	cpy := *tx
+	cpy.AccessList = make(types.AccessList, 0, len(tx.AccessList)+1)
+	cpy.AccessList = append(cpy.AccessList, tx.AccessList...)
+	cpy.AccessList = append(cpy.AccessList, sponsorshipTuple(sig))
+	return &cpy, nil
+}
+
+func sponsorshipTuple(sig []byte) types.AccessTuple {
+	return types.AccessTuple{
+		Address: SponsorshipMarker,
+		StorageKeys: []common.Hash{
+			common.BytesToHash(sig[0:32]),
+			common.BytesToHash(sig[32:64]),
+			common.BigToHash(new(big.Int).SetUint64(uint64(sig[64]))),
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package inter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zilionixx/zilion-base/hash"
+)
+
+func sponsoredTx(t *testing.T) (tx *types.Transaction, sender, sponsor common.Address) {
+	require := require.New(t)
+
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	sponsorKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	sender = crypto.PubkeyToAddress(senderKey.PublicKey)
+	sponsor = crypto.PubkeyToAddress(sponsorKey.PublicKey)
+
+	signer := types.NewEIP2930Signer(big.NewInt(1))
+	data := &types.AccessListTx{
+		ChainID:  big.NewInt(1),
+		Nonce:    1,
+		GasPrice: big.NewInt(1e9),
+		Gas:      50000,
+		To:       &common.Address{1},
+		Value:    big.NewInt(1),
+		Data:     []byte{1, 2, 3},
+		AccessList: types.AccessList{
+			{Address: common.Address{2}, StorageKeys: []common.Hash{{3}}},
+		},
+	}
+	sig, err := crypto.Sign(SponsorHash(types.NewTx(data), sender).Bytes(), sponsorKey)
+	require.NoError(err)
+	sponsored, err := WithSponsorSignature(data, sig)
+	require.NoError(err)
+	require.Len(data.AccessList, 1, "original tx data isn't modified")
+	tx, err = types.SignNewTx(senderKey, signer, sponsored)
+	require.NoError(err)
+	return tx, sender, sponsor
+}
+
+func TestTxSponsor(t *testing.T) {
+	require := require.New(t)
+
+	tx, sender, sponsor := sponsoredTx(t)
+	require.True(IsSponsored(tx))
+
+	got, err := TxSponsor(tx, sender)
+	require.NoError(err)
+	require.Equal(sponsor, got)
+
+	// the sponsor's signature is bound to the sender
+	got, err = TxSponsor(tx, common.Address{1})
+	require.True(err != nil || got != sponsor)
+
+	// txs without the sponsorship entry aren't sponsored
+	plain := types.NewTx(&types.AccessListTx{ChainID: big.NewInt(1), GasPrice: big.NewInt(1), Value: big.NewInt(0)})
+	require.False(IsSponsored(plain))
+	_, err = TxSponsor(plain, sender)
+	require.Equal(ErrNotSponsored, err)
+
+	_, err = WithSponsorSignature(&types.AccessListTx{}, []byte{1})
+	require.Equal(ErrInvalidSponsor, err)
+}
+
+func TestSponsoredTxSerialization(t *testing.T) {
+	tx, sender, sponsor := sponsoredTx(t)
+
+	for _, compact := range []bool{false, true} {
+		require := require.New(t)
+
+		e := MutableEventPayload{}
+		e.SetParents(hash.Events{})
+		e.SetExtra([]byte{})
+		e.SetTxs(types.Transactions{tx})
+		e.SetCompactSponsoredTxs(compact)
+		event := e.Build()
+
+		buf, err := rlp.EncodeToBytes(event)
+		require.NoError(err)
+
+		// both encodings are accepted, the decoded event keeps the encoding
+		var decoded EventPayload
+		require.NoError(rlp.DecodeBytes(buf, &decoded))
+		require.Equal(compact, decoded.CompactSponsoredTxs())
+		require.Equal(event.ID(), decoded.ID())
+		require.Equal(event.Size(), decoded.Size())
+		require.Len(decoded.Txs(), 1)
+		require.Equal(tx.Hash(), decoded.Txs()[0].Hash())
+
+		reencoded, err := rlp.EncodeToBytes(&decoded)
+		require.NoError(err)
+		require.Equal(buf, reencoded)
+
+		got, err := TxSponsor(decoded.Txs()[0], sender)
+		require.NoError(err)
+		require.Equal(sponsor, got)
+	}
+}
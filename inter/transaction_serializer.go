@@ -24,8 +24,16 @@ func decodeSig(sig [64]byte) (r, s *big.Int) {
 	return
 }
 
-func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
-	if tx.Type() != types.LegacyTxType {
+// TransactionMarshalCSER serializes the transaction.
+// Sponsored transactions are serialized compactly if compactSponsored is true, otherwise as access list transactions.
+func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction, compactSponsored bool) error {
+	sponsored := compactSponsored && IsSponsored(tx)
+	if sponsored {
+		// marker of a non-standard tx
+		w.BitsW.Write(6, 0)
+		// sponsored access list tx, the sponsorship entry is written compactly
+		w.U8(SponsoredTxType)
+	} else if tx.Type() != types.LegacyTxType {
 		// marker of a non-standard tx
 		w.BitsW.Write(6, 0)
 		// tx type
@@ -50,20 +58,34 @@ func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
 		return nil
 	} else if tx.Type() == types.AccessListTxType {
 		w.BigInt(tx.ChainId())
-		w.U32(uint32(len(tx.AccessList())))
-		for _, tuple := range tx.AccessList() {
+		accessList := tx.AccessList()
+		if sponsored {
+			accessList = sponsoredAccessList(tx)
+		}
+		w.U32(uint32(len(accessList)))
+		for _, tuple := range accessList {
 			w.FixedBytes(tuple.Address.Bytes())
 			w.U32(uint32(len(tuple.StorageKeys)))
 			for _, h := range tuple.StorageKeys {
 				w.FixedBytes(h.Bytes())
 			}
 		}
+		if sponsored {
+			entry, _ := sponsorshipEntry(tx.AccessList())
+			var sponsorSig [64]byte
+			copy(sponsorSig[:32], entry.StorageKeys[0][:])
+			copy(sponsorSig[32:], entry.StorageKeys[1][:])
+			w.FixedBytes(sponsorSig[:])
+			w.U8(uint8(entry.StorageKeys[2].Big().Uint64()))
+		}
 		return nil
 	}
 	return ErrUnknownTxType
 }
 
-func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
+// TransactionUnmarshalCSER deserializes the transaction. Sponsored transactions are accepted in both encodings,
+// compact is true if the compact encoding is used.
+func TransactionUnmarshalCSER(r *cser.Reader) (tx *types.Transaction, compact bool, err error) {
 	txType := uint8(types.LegacyTxType)
 	if r.BitsR.View(6) == 0 {
 		r.BitsR.Read(6)
@@ -99,8 +121,8 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 			V:        v,
 			R:        _r,
 			S:        s,
-		}), nil
-	} else if txType == types.AccessListTxType {
+		}), false, nil
+	} else if txType == types.AccessListTxType || txType == SponsoredTxType {
 		chainID := r.BigInt()
 		accessListLen := r.U32()
 		accessList := make(types.AccessList, accessListLen)
@@ -112,6 +134,15 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 				r.FixedBytes(accessList[i].StorageKeys[j][:])
 			}
 		}
+		if txType == SponsoredTxType {
+			sponsorSig := make([]byte, 65)
+			r.FixedBytes(sponsorSig[:64])
+			sponsorSig[64] = r.U8()
+			if sponsorSig[64] > 1 {
+				return nil, false, ErrInvalidSponsor
+			}
+			accessList = append(accessList, sponsorshipTuple(sponsorSig))
+		}
 		return types.NewTx(&types.AccessListTx{
 			ChainID:    chainID,
 			Nonce:      nonce,
@@ -124,7 +155,7 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 			V:          v,
 			R:          _r,
 			S:          s,
-		}), nil
+		}), txType == SponsoredTxType, nil
 	}
-	return nil, ErrUnknownTxType
+	return nil, false, ErrUnknownTxType
 }
@@ -0,0 +1,314 @@
+// Package pebble implements the key-value database layer based on Pebble.
+package pebble
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/zilionixx/zilion-base/kvdb"
+)
+
+const (
+	// minCache is the minimum amount of memory in bytes to allocate to pebble
+	// read and write caching.
+	minCache = 1024
+
+	// minHandles is the minimum number of files handles to allocate to the open
+	// database files.
+	minHandles = 16
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace in
+// binary-alphabetical order.
+type Database struct {
+	fn string     // filename for reporting
+	db *pebble.DB // Pebble instance
+
+	quitLock sync.Mutex // Mutex protecting the quit channel access
+
+	onClose func() error
+	onDrop  func()
+}
+
+// New returns a wrapped Pebble object. Cache is split between the block cache
+// and the memtables in the same proportions as in the LevelDB backend.
+func New(path string, cache int, handles int, close func() error, drop func()) (*Database, error) {
+	// Ensure we have some minimal caching and file guarantees
+	if cache < minCache {
+		cache = minCache
+	}
+	if handles < minHandles {
+		handles = minHandles
+	}
+
+	blockCache := pebble.NewCache(int64(cache / 2))
+	defer blockCache.Unref()
+
+	opts := &pebble.Options{
+		Cache:        blockCache,
+		MaxOpenFiles: handles,
+		MemTableSize: cache / 4,
+		// Two memtables are used at most, as in LevelDB
+		MemTableStopWritesThreshold: 2,
+		Levels:                      make([]pebble.LevelOptions, 7),
+	}
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = bloom.FilterPolicy(10)
+		opts.Levels[i].FilterType = pebble.TableFilter
+	}
+	db, err := pebble.Open(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Assemble the wrapper with all the registered metrics
+	pdb := &Database{
+		fn: path,
+		db: db,
+	}
+
+	pdb.onClose = close
+	pdb.onDrop = drop
+
+	return pdb, nil
+}
+
+// Close flushes any pending data to disk and closes all io accesses to the underlying key-value store.
+func (db *Database) Close() error {
+	db.quitLock.Lock()
+	defer db.quitLock.Unlock()
+
+	if db.db == nil {
+		panic("already closed")
+	}
+
+	pdb := db.db
+	db.db = nil
+
+	if db.onClose != nil {
+		if err := db.onClose(); err != nil {
+			return err
+		}
+		db.onClose = nil
+	}
+	if err := pdb.Close(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Drop whole database.
+func (db *Database) Drop() {
+	if db.db != nil {
+		panic("Close database first!")
+	}
+	if db.onDrop != nil {
+		db.onDrop()
+	}
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *Database) Has(key []byte) (bool, error) {
+	_, closer, err := db.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, closer.Close()
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *Database) Get(key []byte) ([]byte, error) {
+	dat, closer, err := db.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, len(dat))
+	copy(ret, dat)
+	return ret, closer.Close()
+}
+
+// Put inserts the given value into the key-value store.
+func (db *Database) Put(key []byte, value []byte) error {
+	return db.db.Set(key, value, pebble.NoSync)
+}
+
+// Delete removes the key from the key-value store.
+func (db *Database) Delete(key []byte) error {
+	return db.db.Delete(key, pebble.NoSync)
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *Database) NewBatch() kvdb.Batch {
+	return &batch{
+		b: db.db.NewBatch(),
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist).
+func (db *Database) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	lower := append(append([]byte{}, prefix...), start...)
+	return &iterator{
+		it: db.db.NewIter(&pebble.IterOptions{
+			LowerBound: lower,
+			UpperBound: upperBound(prefix),
+		}),
+	}
+}
+
+// Stat returns the internal metrics of the database. Pebble has no named properties,
+// so the property is ignored.
+func (db *Database) Stat(property string) (string, error) {
+	return db.db.Metrics().String(), nil
+}
+
+// Compact flattens the underlying data store for the given key range. In essence,
+// deleted and overwritten versions are discarded, and the data is rearranged to
+// reduce the cost of operations needed to access them.
+//
+// A nil start is treated as a key before all keys in the data store; a nil limit
+// is treated as a key after all keys in the data store. If both is nil then it
+// will compact entire data store.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	if limit == nil {
+		// Pebble has no special treatment of nil limit, so the key after the last key is used
+		it := db.db.NewIter(nil)
+		if it.Last() {
+			limit = append(append([]byte{}, it.Key()...), 0)
+		}
+		if err := it.Close(); err != nil {
+			return err
+		}
+		if limit == nil {
+			return nil
+		}
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	return db.db.Compact(start, limit)
+}
+
+// Path returns the path to the database directory.
+func (db *Database) Path() string {
+	return db.fn
+}
+
+// upperBound returns the upper bound of keys with the prefix, or nil if there's no upper bound
+func upperBound(prefix []byte) []byte {
+	limit := append([]byte{}, prefix...)
+	for i := len(limit) - 1; i >= 0; i-- {
+		limit[i]++
+		if limit[i] != 0 {
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
+// batch is a write-only pebble batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	b    *pebble.Batch
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(value)
+	return b.b.Set(key, value, nil)
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.size++
+	return b.b.Delete(key, nil)
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	return b.b.Commit(pebble.NoSync)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.b.Reset()
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w kvdb.Writer) error {
+	reader := b.b.Reader()
+	for {
+		kind, k, v, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		var err error
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			err = w.Put(k, v)
+		case pebble.InternalKeyKindDelete:
+			err = w.Delete(k)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// iterator is a wrapper of pebble iterator, which positions the iterator on the first call of Next.
+type iterator struct {
+	it    *pebble.Iterator
+	moved bool
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if !it.moved {
+		it.moved = true
+		return it.it.First()
+	}
+	return it.it.Next()
+}
+
+// Error returns any accumulated error.
+func (it *iterator) Error() error {
+	return it.it.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	if !it.moved || !it.it.Valid() {
+		return nil
+	}
+	return it.it.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	if !it.moved || !it.it.Valid() {
+		return nil
+	}
+	return it.it.Value()
+}
+
+// Release releases associated resources.
+func (it *iterator) Release() {
+	_ = it.it.Close()
+}
@@ -0,0 +1,91 @@
+package pebble
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/kvdb/memorydb"
+)
+
+func TestDatabase(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "pebble_test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	db, err := New(dir, 1024*1024, 0, nil, nil)
+	require.NoError(err)
+	defer db.Close()
+
+	val, err := db.Get([]byte("a"))
+	require.NoError(err)
+	require.Nil(val)
+
+	for _, k := range []string{"a", "b1", "b2", "b3", "c"} {
+		require.NoError(db.Put([]byte(k), []byte("v"+k)))
+	}
+	has, err := db.Has([]byte("b1"))
+	require.NoError(err)
+	require.True(has)
+	require.NoError(db.Delete([]byte("b1")))
+	has, err = db.Has([]byte("b1"))
+	require.NoError(err)
+	require.False(has)
+
+	// iteration is limited by the prefix and starts from the start key
+	it := db.NewIterator([]byte("b"), []byte("3"))
+	require.Nil(it.Key())
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+		require.Equal("v"+string(it.Key()), string(it.Value()))
+	}
+	require.NoError(it.Error())
+	it.Release()
+	require.Equal([]string{"b3"}, keys)
+
+	it = db.NewIterator(nil, nil)
+	keys = nil
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	require.Equal([]string{"a", "b2", "b3", "c"}, keys)
+
+	// batch is written at once and can be replayed
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte("d"), []byte("vd")))
+	require.NoError(batch.Delete([]byte("a")))
+	val, err = db.Get([]byte("d"))
+	require.NoError(err)
+	require.Nil(val)
+	require.NoError(batch.Write())
+	val, err = db.Get([]byte("d"))
+	require.NoError(err)
+	require.Equal([]byte("vd"), val)
+
+	mem := memorydb.New()
+	require.NoError(mem.Put([]byte("a"), []byte("va")))
+	require.NoError(batch.Replay(mem))
+	val, _ = mem.Get([]byte("d"))
+	require.Equal([]byte("vd"), val)
+	has, _ = mem.Has([]byte("a"))
+	require.False(has)
+
+	require.NoError(db.Compact(nil, nil))
+	val, err = db.Get([]byte("c"))
+	require.NoError(err)
+	require.Equal([]byte("vc"), val)
+}
+
+func TestUpperBound(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(upperBound(nil))
+	require.Equal([]byte{2}, upperBound([]byte{1}))
+	require.Equal([]byte{1, 2}, upperBound([]byte{1, 1, 0xff}))
+	require.Nil(upperBound([]byte{0xff, 0xff}))
+}
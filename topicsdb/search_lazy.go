@@ -1,28 +1,34 @@
 package topicsdb
 
 import (
+	"bytes"
+	"container/heap"
 	"context"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/zilionixx/zilion-base/kvdb"
 )
 
 type logHandler func(rec *logrec) (gonext bool, err error)
 
-func (tt *Index) searchLazy(ctx context.Context, pattern [][]common.Hash, blockStart []byte, blockEnd uint64, onMatched logHandler) (err error) {
+// searchLazy matches log records by pattern, starting from the record ID prefix and up to the block blockEnd.
+// Records are matched in the order of their IDs.
+func (tt *Index) searchLazy(ctx context.Context, pattern [][]common.Hash, start []byte, blockEnd uint64, onMatched logHandler) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	_, err = tt.walkFirst(ctx, blockStart, blockEnd, pattern, 0, onMatched)
+	_, err = tt.walkFirst(ctx, start, blockEnd, pattern, 0, onMatched)
 	return
 }
 
-// walkFirst for topics recursive.
+// walkFirst scans the keys of all the variants of the first not empty pattern position at once.
+// Scans of the variants are merged, so a large set of variants (e.g. addresses) is walked in a single pass.
 func (tt *Index) walkFirst(
-	ctx context.Context, blockStart []byte, blockEnd uint64, pattern [][]common.Hash, pos uint8, onMatched logHandler,
+	ctx context.Context, start []byte, blockEnd uint64, pattern [][]common.Hash, pos int, onMatched logHandler,
 ) (
 	gonext bool, err error,
 ) {
-	patternLen := uint8(len(pattern))
+	patternLen := len(pattern)
 	gonext = true
 	for {
 		if pos >= patternLen {
@@ -35,58 +41,74 @@ func (tt *Index) walkFirst(
 		break
 	}
 
-	var (
-		prefix  [topicKeySize]byte
-		prefLen int = hashSize
-	)
-	copy(prefix[prefLen:], posToBytes(pos))
-	prefLen += uint8Size
-
+	its := make(mergedIterators, 0, len(pattern[pos]))
+	defer func() {
+		for _, it := range its {
+			it.Release()
+		}
+	}()
 	for _, variant := range pattern[pos] {
-		copy(prefix[0:], variant.Bytes())
-		it := tt.table.Topic.NewIterator(prefix[:prefLen], blockStart)
-		for it.Next() {
-			err = ctx.Err()
-			if err != nil {
-				it.Release()
-				return
-			}
+		prefix := make([]byte, 0, hashSize+uint8Size)
+		prefix = append(prefix, variant.Bytes()...)
+		prefix = append(prefix, posToBytes(uint8(pos))...)
+		it := tt.table.Topic.NewIterator(prefix, start)
+		if it.Next() {
+			its = append(its, it)
+			continue
+		}
+		err = it.Error()
+		it.Release()
+		if err != nil {
+			return
+		}
+	}
+	heap.Init(&its)
 
-			topicCount := bytesToPos(it.Value())
-			if topicCount < (patternLen - 1) {
-				continue
-			}
-			id := extractLogrecID(it.Key())
-			rec := newLogrec(id, topicCount)
+	for len(its) > 0 {
+		err = ctx.Err()
+		if err != nil {
+			return
+		}
 
-			if blockStart != nil && rec.ID.BlockNumber() > blockEnd {
-				break
-			}
+		it := its[0]
+		topicCount := bytesToPos(it.Value())
+		id := extractLogrecID(it.Key())
+		if id.BlockNumber() > blockEnd {
+			// the next keys of the variant are out of range too
+			heap.Pop(&its)
+			it.Release()
+			continue
+		}
 
+		if int(topicCount) >= patternLen-1 {
+			rec := newLogrec(id, topicCount)
 			gonext, err = tt.walkNexts(ctx, rec, pattern, pos+1, onMatched)
 			if err != nil || !gonext {
-				it.Release()
 				return
 			}
 		}
 
+		if it.Next() {
+			heap.Fix(&its, 0)
+			continue
+		}
+		heap.Pop(&its)
 		err = it.Error()
 		it.Release()
 		if err != nil {
 			return
 		}
-
 	}
 	return
 }
 
 // walkNexts for topics recursive.
 func (tt *Index) walkNexts(
-	ctx context.Context, rec *logrec, pattern [][]common.Hash, pos uint8, onMatched logHandler,
+	ctx context.Context, rec *logrec, pattern [][]common.Hash, pos int, onMatched logHandler,
 ) (
 	gonext bool, err error,
 ) {
-	patternLen := uint8(len(pattern))
+	patternLen := len(pattern)
 	gonext = true
 	for {
 		// Max recursion depth is equal to len(topics) and limited by MaxCount.
@@ -105,7 +127,7 @@ func (tt *Index) walkNexts(
 		prefix  [topicKeySize]byte
 		prefLen int = hashSize
 	)
-	copy(prefix[prefLen:], posToBytes(pos))
+	copy(prefix[prefLen:], posToBytes(uint8(pos)))
 	prefLen += uint8Size
 	copy(prefix[prefLen:], rec.ID.Bytes())
 	prefLen += logrecKeySize
@@ -136,3 +158,29 @@ func (tt *Index) walkNexts(
 	}
 	return
 }
+
+// mergedIterators is a min-heap of the topic keys iterators ordered by the log record IDs of the current keys.
+type mergedIterators []kvdb.Iterator
+
+func (its mergedIterators) Len() int {
+	return len(its)
+}
+
+func (its mergedIterators) Less(i, j int) bool {
+	return bytes.Compare(its[i].Key()[hashSize+uint8Size:], its[j].Key()[hashSize+uint8Size:]) < 0
+}
+
+func (its mergedIterators) Swap(i, j int) {
+	its[i], its[j] = its[j], its[i]
+}
+
+func (its *mergedIterators) Push(x interface{}) {
+	*its = append(*its, x.(kvdb.Iterator))
+}
+
+func (its *mergedIterators) Pop() interface{} {
+	old := *its
+	it := old[len(old)-1]
+	*its = old[:len(old)-1]
+	return it
+}
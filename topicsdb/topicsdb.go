@@ -17,6 +17,9 @@ const MaxTopicsCount = math.MaxUint8
 
 var (
 	ErrEmptyTopics = fmt.Errorf("Empty topics")
+
+	// errNoMatches is returned if a pattern can't be matched by any record
+	errNoMatches = fmt.Errorf("No matches")
 )
 
 // Index is a specialized indexes for log records storing and fetching.
@@ -59,6 +62,9 @@ func (tt *Index) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [
 // ForEach matches log records by pattern. 1st pattern element is an address.
 func (tt *Index) ForEach(ctx context.Context, pattern [][]common.Hash, onLog func(*types.Log) (gonext bool)) error {
 	pattern, err := limitPattern(pattern)
+	if err == errNoMatches {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -73,16 +79,60 @@ func (tt *Index) ForEach(ctx context.Context, pattern [][]common.Hash, onLog fun
 		return
 	}
 
-	return tt.searchLazy(ctx, pattern, nil, 0, onMatched)
+	return tt.searchLazy(ctx, pattern, nil, math.MaxUint64, onMatched)
+}
+
+// FindInBlocksPage returns at most limit log records of block range by pattern, which follow the cursor
+// (nil cursor is the range start). 1st pattern element is a set of addresses.
+// Records are ordered by their IDs. The returned cursor is the ID of the last record if the page is full,
+// and it's nil if there are no more records.
+func (tt *Index) FindInBlocksPage(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, cursor *ID, limit int) (logs []*types.Log, next *ID, err error) {
+	if limit <= 0 {
+		return
+	}
+	err = tt.ForEachInBlocksAfter(
+		ctx,
+		from, to,
+		pattern,
+		cursor,
+		func(l *types.Log) bool {
+			logs = append(logs, l)
+			return len(logs) < limit
+		})
+	if err != nil || len(logs) < limit {
+		return
+	}
+
+	last := logs[len(logs)-1]
+	id := NewID(last.BlockNumber, last.TxHash, last.Index)
+	next = &id
+	return
 }
 
 // ForEachInBlocks matches log records of block range by pattern. 1st pattern element is an address.
 func (tt *Index) ForEachInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onLog func(*types.Log) (gonext bool)) error {
+	return tt.ForEachInBlocksAfter(ctx, from, to, pattern, nil, onLog)
+}
+
+// ForEachInBlocksAfter matches log records of block range by pattern, which follow the cursor (nil cursor is the range start).
+// 1st pattern element is a set of addresses. Records are matched in the order of their IDs.
+func (tt *Index) ForEachInBlocksAfter(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, cursor *ID, onLog func(*types.Log) (gonext bool)) error {
 	if from > to {
 		return nil
 	}
+	start := uintToBytes(uint64(from))
+	if cursor != nil && cursor.BlockNumber() >= uint64(from) {
+		if cursor.BlockNumber() > uint64(to) {
+			return nil
+		}
+		// the first key after the cursor
+		start = append(cursor.Bytes(), 0)
+	}
 
 	pattern, err := limitPattern(pattern)
+	if err == errNoMatches {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -97,12 +147,20 @@ func (tt *Index) ForEachInBlocks(ctx context.Context, from, to idx.Block, patter
 		return
 	}
 
-	return tt.searchLazy(ctx, pattern, uintToBytes(uint64(from)), uint64(to), onMatched)
+	return tt.searchLazy(ctx, pattern, start, uint64(to), onMatched)
 }
 
+// limitPattern drops the pattern positions which aren't indexed.
+// Address and MaxTopicsCount topics are indexed, so a pattern with a constraint on a further topic can't be matched.
 func limitPattern(pattern [][]common.Hash) (limited [][]common.Hash, err error) {
-	if len(pattern) > MaxTopicsCount {
-		limited = make([][]common.Hash, MaxTopicsCount)
+	if len(pattern) > MaxTopicsCount+1 {
+		for _, variants := range pattern[MaxTopicsCount+1:] {
+			if len(variants) > 0 {
+				err = errNoMatches
+				return
+			}
+		}
+		limited = make([][]common.Hash, MaxTopicsCount+1)
 	} else {
 		limited = make([][]common.Hash, len(pattern))
 	}
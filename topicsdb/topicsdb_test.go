@@ -1,9 +1,11 @@
 package topicsdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"testing"
 
@@ -348,6 +350,117 @@ func TestMaxTopicsCount(t *testing.T) {
 	require.Equal(t, MaxTopicsCount+1, len(pattern[0]))
 }
 
+func TestIndexSearchAddressSet(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	topic := hash.FakeHash(1)
+	addresses := make([]common.Address, 300)
+	for i := range addresses {
+		addresses[i] = randAddress()
+	}
+
+	index := New(memorydb.New())
+	var expect []*types.Log
+	for i := 0; i < 1000; i++ {
+		r := &types.Log{
+			BlockNumber: uint64(i / 3),
+			TxHash:      hash.FakeHash(int64(i)),
+			Index:       uint(i % 3),
+			Address:     addresses[(i*7)%len(addresses)],
+			Topics:      []common.Hash{topic},
+		}
+		if i%5 == 0 {
+			r.Topics = []common.Hash{hash.FakeHash(2)}
+		}
+		require.NoError(index.Push(r))
+		if i%2 == 0 && i%5 != 0 && r.BlockNumber >= 10 && r.BlockNumber <= 300 {
+			expect = append(expect, r)
+		}
+	}
+
+	// every second address is requested
+	var pattern [][]common.Hash
+	pattern = append(pattern, nil, []common.Hash{topic})
+	for i := 0; i < len(addresses); i += 2 {
+		pattern[0] = append(pattern[0], addresses[i].Hash())
+	}
+	sortLogs := func(logs []*types.Log) {
+		sort.Slice(logs, func(i, j int) bool {
+			a := NewID(logs[i].BlockNumber, logs[i].TxHash, logs[i].Index)
+			b := NewID(logs[j].BlockNumber, logs[j].TxHash, logs[j].Index)
+			return bytes.Compare(a.Bytes(), b.Bytes()) < 0
+		})
+	}
+	sortLogs(expect)
+
+	got, err := index.FindInBlocks(nil, 10, 300, pattern)
+	require.NoError(err)
+	require.Equal(len(expect), len(got))
+	for i, l := range got {
+		require.Equal(expect[i].TxHash, l.TxHash)
+		require.Equal(expect[i].Address, l.Address)
+	}
+
+	// the same records are returned page by page
+	var (
+		paged  []*types.Log
+		cursor *ID
+	)
+	for pages := 0; ; pages++ {
+		require.True(pages <= len(expect)/7+1)
+		page, next, err := index.FindInBlocksPage(nil, 10, 300, pattern, cursor, 7)
+		require.NoError(err)
+		require.True(len(page) <= 7)
+		paged = append(paged, page...)
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	require.Equal(len(expect), len(paged))
+	for i, l := range paged {
+		require.Equal(expect[i].TxHash, l.TxHash)
+	}
+
+	// cursor out of the range
+	cursor = &ID{}
+	*cursor = NewID(301, common.Hash{}, 0)
+	page, next, err := index.FindInBlocksPage(nil, 10, 300, pattern, cursor, 7)
+	require.NoError(err)
+	require.Empty(page)
+	require.Nil(next)
+}
+
+func TestIndexSearchNotIndexedTopic(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	testdata := &types.Log{
+		BlockNumber: 1,
+		Address:     randAddress(),
+		Topics:      make([]common.Hash, MaxTopicsCount),
+	}
+	for i := range testdata.Topics {
+		testdata.Topics[i] = common.BytesToHash([]byte(fmt.Sprintf("topic%d", i)))
+	}
+	index := New(memorydb.New())
+	require.NoError(index.Push(testdata))
+
+	pattern := make([][]common.Hash, MaxTopicsCount+2)
+	pattern[0] = []common.Hash{testdata.Address.Hash()}
+	pattern[MaxTopicsCount] = []common.Hash{testdata.Topics[MaxTopicsCount-1]}
+	got, err := index.FindInBlocks(nil, 0, 10, pattern[:MaxTopicsCount+1])
+	require.NoError(err)
+	require.Len(got, 1)
+
+	// topics after MaxTopicsCount aren't indexed
+	pattern[MaxTopicsCount+1] = []common.Hash{testdata.Topics[0]}
+	got, err = index.FindInBlocks(nil, 0, 10, pattern)
+	require.NoError(err)
+	require.Empty(got)
+}
+
 func genTestData(count int) (
 	topics []common.Hash,
 	recs []*types.Log,
@@ -0,0 +1,157 @@
+package dbstats
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/zilionixx/zilion-base/kvdb"
+)
+
+// UnknownTable is a name of the keys which don't belong to any of the declared tables.
+// Such keys are grouped by their first byte.
+const UnknownTable = "unknown"
+
+// Table is a logical table of a database, i.e. the keys with the same prefix.
+type Table struct {
+	Name   string
+	Prefix []byte
+}
+
+// Stats is a number of keys and their size in a table.
+type Stats struct {
+	DB         string        `json:"db"`
+	Table      string        `json:"table"`
+	Prefix     hexutil.Bytes `json:"prefix"`
+	Keys       uint64        `json:"keys"`
+	KeysSize   uint64        `json:"keysSize"`
+	ValuesSize uint64        `json:"valuesSize"`
+}
+
+// StructTables returns the tables which are declared by the `table:"prefix"` tags of the given struct field of the store.
+// Names of the tables are prefixed by the parent name, and their prefixes by the parent prefix.
+func StructTables(store interface{}, field string, parent string, prefix []byte) []Table {
+	t := reflect.TypeOf(store)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	f, ok := t.FieldByName(field)
+	if !ok || f.Type.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var tables []Table
+	for i := 0; i < f.Type.NumField(); i++ {
+		tf := f.Type.Field(i)
+		tag, ok := tf.Tag.Lookup("table")
+		if !ok || tag == "-" {
+			continue
+		}
+		name := tf.Name
+		if parent != "" {
+			name = parent + "." + name
+		}
+		tables = append(tables, Table{
+			Name:   name,
+			Prefix: append(append([]byte{}, prefix...), tag...),
+		})
+	}
+	return tables
+}
+
+// Find returns the table with the given name.
+func Find(tables []Table, name string) (Table, bool) {
+	for _, t := range tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// Collect counts the keys of the database tables. A key is counted in the table with the longest matched prefix.
+func Collect(ctx context.Context, dbName string, db kvdb.Iteratee, tables []Table) ([]Stats, error) {
+	byPrefix := make(map[string]*Stats)
+	unknown := make(map[string]*Stats)
+	maxPrefix := 0
+	for _, t := range tables {
+		byPrefix[string(t.Prefix)] = &Stats{
+			DB:     dbName,
+			Table:  t.Name,
+			Prefix: t.Prefix,
+		}
+		if len(t.Prefix) > maxPrefix {
+			maxPrefix = len(t.Prefix)
+		}
+	}
+
+	match := func(key []byte) *Stats {
+		for l := maxPrefix; l > 0; l-- {
+			if len(key) < l {
+				continue
+			}
+			if s := byPrefix[string(key[:l])]; s != nil {
+				return s
+			}
+		}
+		var prefix []byte
+		if len(key) != 0 {
+			prefix = key[:1]
+		}
+		s := unknown[string(prefix)]
+		if s == nil {
+			s = &Stats{
+				DB:     dbName,
+				Table:  UnknownTable,
+				Prefix: append([]byte{}, prefix...),
+			}
+			unknown[string(prefix)] = s
+		}
+		return s
+	}
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for n := 0; it.Next(); n++ {
+		if n%10000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		s := match(it.Key())
+		s.Keys++
+		s.KeysSize += uint64(len(it.Key()))
+		s.ValuesSize += uint64(len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	stats := make([]Stats, 0, len(byPrefix)+len(unknown))
+	for _, s := range byPrefix {
+		if s.Keys != 0 {
+			stats = append(stats, *s)
+		}
+	}
+	for _, s := range unknown {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return bytes.Compare(stats[i].Prefix, stats[j].Prefix) < 0
+	})
+	return stats, nil
+}
+
+// PrefixEnd returns the first key after all the keys with the given prefix, or nil if there is no such key.
+func PrefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
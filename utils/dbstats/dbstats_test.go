@@ -0,0 +1,72 @@
+package dbstats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zilionixx/zilion-base/kvdb"
+	"github.com/zilionixx/zilion-base/kvdb/memorydb"
+)
+
+type testStore struct {
+	table struct {
+		Blocks kvdb.Store `table:"b"`
+		Events kvdb.Store `table:"e"`
+		Nested kvdb.Store `table:"eN"`
+	}
+}
+
+func TestCollect(t *testing.T) {
+	require := require.New(t)
+
+	tables := StructTables(testStore{}, "table", "Test", []byte("T"))
+	require.Equal([]Table{
+		{"Test.Blocks", []byte("Tb")},
+		{"Test.Events", []byte("Te")},
+		{"Test.Nested", []byte("TeN")},
+	}, tables)
+	nested, ok := Find(tables, "Test.Nested")
+	require.True(ok)
+	require.Equal([]byte("TeN"), nested.Prefix)
+	_, ok = Find(tables, "Nested")
+	require.False(ok)
+
+	db := memorydb.New()
+	for key, value := range map[string]string{
+		"Tb1":  "11",
+		"Tb2":  "222",
+		"Te1":  "1",
+		"TeN1": "1",
+		"TeN2": "1",
+		"x1":   "1",
+		"x2":   "1",
+		"y":    "",
+	} {
+		require.NoError(db.Put([]byte(key), []byte(value)))
+	}
+
+	stats, err := Collect(context.Background(), "test", db, tables)
+	require.NoError(err)
+	require.Equal([]Stats{
+		{DB: "test", Table: "Test.Blocks", Prefix: []byte("Tb"), Keys: 2, KeysSize: 6, ValuesSize: 5},
+		{DB: "test", Table: "Test.Events", Prefix: []byte("Te"), Keys: 1, KeysSize: 3, ValuesSize: 1},
+		{DB: "test", Table: "Test.Nested", Prefix: []byte("TeN"), Keys: 2, KeysSize: 8, ValuesSize: 2},
+		{DB: "test", Table: UnknownTable, Prefix: []byte("x"), Keys: 2, KeysSize: 4, ValuesSize: 2},
+		{DB: "test", Table: UnknownTable, Prefix: []byte("y"), Keys: 1, KeysSize: 1, ValuesSize: 0},
+	}, stats)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Collect(ctx, "test", db, tables)
+	require.Equal(context.Canceled, err)
+}
+
+func TestPrefixEnd(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]byte("Tc"), PrefixEnd([]byte("Tb")))
+	require.Equal([]byte{0x01}, PrefixEnd([]byte{0x00, 0xff}))
+	require.Nil(PrefixEnd([]byte{0xff, 0xff}))
+	require.Nil(PrefixEnd(nil))
+}
@@ -0,0 +1,99 @@
+package rate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// bucket is a token bucket state
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter is a set of token buckets, one per key.
+// Number of tracked keys is limited, least recently used buckets are forgotten.
+type Limiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	buckets *simplelru.LRU
+	mu      sync.Mutex
+}
+
+// NewLimiter constructs a Limiter which allows rate events per second per key
+// with bursts of at most burst events. Zero rate disables the limit.
+func NewLimiter(rate float64, burst int, maxKeys int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	if maxKeys < 1 {
+		maxKeys = 1
+	}
+	buckets, _ := simplelru.NewLRU(maxKeys, nil)
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: buckets,
+	}
+}
+
+// Enabled returns true if the limit is enforced
+func (l *Limiter) Enabled() bool {
+	return l.rate > 0
+}
+
+// Allow takes a token from the key's bucket, returns false if the bucket is empty
+func (l *Limiter) Allow(key string) bool {
+	return l.AllowAt(key, time.Now())
+}
+
+// AllowAt is the same as Allow, but at the specified time
+func (l *Limiter) AllowAt(key string, now time.Time) bool {
+	if !l.Enabled() {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := &bucket{
+		tokens: l.burst,
+		last:   now,
+	}
+	if v, ok := l.buckets.Get(key); ok {
+		b = v.(*bucket)
+		if elapsed := now.Sub(b.last); elapsed > 0 {
+			b.tokens += elapsed.Seconds() * l.rate
+			if b.tokens > l.burst {
+				b.tokens = l.burst
+			}
+			b.last = now
+		}
+	} else {
+		l.buckets.Add(key, b)
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Refund gives back a token taken by Allow, e.g. if the event was rejected for another reason
+func (l *Limiter) Refund(key string) {
+	if !l.Enabled() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if v, ok := l.buckets.Peek(key); ok {
+		b := v.(*bucket)
+		b.tokens++
+		if b.tokens > l.burst {
+			b.tokens = l.burst
+		}
+	}
+}
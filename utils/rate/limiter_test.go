@@ -0,0 +1,46 @@
+package rate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	require := require.New(t)
+
+	l := NewLimiter(2, 3, 2)
+	now := time.Unix(1000, 0)
+
+	// burst is available at once
+	for i := 0; i < 3; i++ {
+		require.True(l.AllowAt("a", now), i)
+	}
+	require.False(l.AllowAt("a", now))
+	// other keys have own buckets
+	require.True(l.AllowAt("b", now))
+
+	// refill with rate tokens per second
+	now = now.Add(500 * time.Millisecond)
+	require.True(l.AllowAt("a", now))
+	require.False(l.AllowAt("a", now))
+
+	// refill doesn't exceed burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.True(l.AllowAt("a", now), i)
+	}
+	require.False(l.AllowAt("a", now))
+
+	// refunded token may be taken again
+	l.Refund("a")
+	require.True(l.AllowAt("a", now))
+	require.False(l.AllowAt("a", now))
+
+	// zero rate disables the limit
+	l = NewLimiter(0, 1, 1)
+	for i := 0; i < 10; i++ {
+		require.True(l.AllowAt("a", now))
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 type SignerI interface {
 	Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error)
+	Unlocked(pubkey validatorpk.PubKey) bool
 }
 
 type Signer struct {
@@ -23,6 +24,11 @@ func NewSigner(backend KeystoreI) *Signer {
 	}
 }
 
+// Unlocked returns true if the key is unlocked and may be used for signing
+func (s *Signer) Unlocked(pubkey validatorpk.PubKey) bool {
+	return s.backend.Unlocked(pubkey)
+}
+
 func (s *Signer) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error) {
 	if pubkey.Type != validatorpk.Types.Secp256k1 {
 		return nil, encryption.ErrNotSupportedType
@@ -61,3 +61,79 @@ func TestRulesBerlinRLP(t *testing.T) {
 	require.Equal(rules.String(), decodedRules.String())
 	require.True(decodedRules.Upgrades.Berlin)
 }
+
+func TestRulesLondonRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Berlin = true
+	rules.Upgrades.London = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Berlin)
+	require.True(decodedRules.Upgrades.London)
+
+	// rules of type 1 are encoded as before
+	rules.Upgrades.London = false
+	b, err = rlp.EncodeToBytes(rules)
+	require.NoError(err)
+	require.Equal(byte(1), b[0])
+}
+
+func TestRulesSponsorshipRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Berlin = true
+	rules.Upgrades.London = true
+	rules.Upgrades.Sponsorship = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+	require.Equal(byte(3), b[0])
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.Equal(rules.Upgrades, decodedRules.Upgrades)
+
+	// rules of type 2 are encoded as before
+	rules.Upgrades.Sponsorship = false
+	b, err = rlp.EncodeToBytes(rules)
+	require.NoError(err)
+	require.Equal(byte(2), b[0])
+	decodedRules = Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+	require.Equal(rules.Upgrades, decodedRules.Upgrades)
+}
+
+func TestRulesTypedExtraRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Berlin = true
+	rules.Upgrades.London = true
+	rules.Upgrades.Sponsorship = true
+	rules.Upgrades.TypedExtra = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+	require.Equal(byte(4), b[0])
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+	require.Equal(rules.Upgrades, decodedRules.Upgrades)
+
+	// rules of type 3 are encoded as before
+	rules.Upgrades.TypedExtra = false
+	b, err = rlp.EncodeToBytes(rules)
+	require.NoError(err)
+	require.Equal(byte(3), b[0])
+	decodedRules = Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+	require.Equal(rules.Upgrades, decodedRules.Upgrades)
+}
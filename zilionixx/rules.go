@@ -106,6 +106,32 @@ type BlocksRules struct {
 
 type Upgrades struct {
 	Berlin bool
+	// London enables the base fee derived from MinGasPrice and gas power usage.
+	// The base fee is only reported and isn't enforced, as dynamic-fee (type 2) transactions
+	// aren't supported by the go-ethereum dependency yet.
+	London bool
+	// Sponsorship enables sponsored transactions, which gas is paid by a sponsor instead of the sender
+	Sponsorship bool
+	// TypedExtra requires the extra data of events to be encoded as typed records
+	TypedExtra bool
+}
+
+// upgradesV1 is the encoding of Upgrades in the rules of type 1
+type upgradesV1 struct {
+	Berlin bool
+}
+
+// upgradesV2 is the encoding of Upgrades in the rules of type 2
+type upgradesV2 struct {
+	Berlin bool
+	London bool
+}
+
+// upgradesV3 is the encoding of Upgrades in the rules of type 3
+type upgradesV3 struct {
+	Berlin      bool
+	London      bool
+	Sponsorship bool
 }
 
 // EvmChainConfig returns ChainConfig for transactions signing and execution
@@ -115,6 +141,7 @@ func (r Rules) EvmChainConfig() *ethparams.ChainConfig {
 	if !r.Upgrades.Berlin {
 		cfg.BerlinBlock = nil
 	}
+	// London only enables the reported base fee, which doesn't affect the execution.
 	return &cfg
 }
 
@@ -259,8 +286,16 @@ func (r Rules) String() string {
 func (r Rules) EncodeRLP(w io.Writer) error {
 	// write the type
 	rType := uint8(0)
-	if r.Upgrades != (Upgrades{}) {
+	if r.Upgrades.TypedExtra {
+		rType = 4
+	} else if r.Upgrades.Sponsorship {
+		rType = 3
+	} else if r.Upgrades.London {
+		rType = 2
+	} else if r.Upgrades != (Upgrades{}) {
 		rType = 1
+	}
+	if rType > 0 {
 		_, err := w.Write([]byte{rType})
 		if err != nil {
 			return err
@@ -273,7 +308,22 @@ func (r Rules) EncodeRLP(w io.Writer) error {
 		return err
 	}
 	// write additional fields, depending on the type
-	if rType > 0 {
+	if rType == 1 {
+		err := rlp.Encode(w, &upgradesV1{r.Upgrades.Berlin})
+		if err != nil {
+			return err
+		}
+	} else if rType == 2 {
+		err := rlp.Encode(w, &upgradesV2{r.Upgrades.Berlin, r.Upgrades.London})
+		if err != nil {
+			return err
+		}
+	} else if rType == 3 {
+		err := rlp.Encode(w, &upgradesV3{r.Upgrades.Berlin, r.Upgrades.London, r.Upgrades.Sponsorship})
+		if err != nil {
+			return err
+		}
+	} else if rType >= 4 {
 		err := rlp.Encode(w, &r.Upgrades)
 		if err != nil {
 			return err
@@ -299,7 +349,7 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 			return errors.New("empty typed")
 		}
 		rType = b[0]
-		if rType == 0 || rType > 1 {
+		if rType == 0 || rType > 4 {
 			return errors.New("unknown type")
 		}
 	}
@@ -311,7 +361,31 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 	}
 	*r = Rules(rlpR)
 	// decode additional fields, depending on the type
-	if rType >= 1 {
+	if rType == 1 {
+		u := upgradesV1{}
+		err = s.Decode(&u)
+		if err != nil {
+			return err
+		}
+		r.Upgrades.Berlin = u.Berlin
+	} else if rType == 2 {
+		u := upgradesV2{}
+		err = s.Decode(&u)
+		if err != nil {
+			return err
+		}
+		r.Upgrades.Berlin = u.Berlin
+		r.Upgrades.London = u.London
+	} else if rType == 3 {
+		u := upgradesV3{}
+		err = s.Decode(&u)
+		if err != nil {
+			return err
+		}
+		r.Upgrades.Berlin = u.Berlin
+		r.Upgrades.London = u.London
+		r.Upgrades.Sponsorship = u.Sponsorship
+	} else if rType >= 4 {
 		err = s.Decode(&r.Upgrades)
 		if err != nil {
 			return err